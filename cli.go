@@ -0,0 +1,1029 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coollabsio/jean/config"
+	"github.com/coollabsio/jean/git"
+	"github.com/coollabsio/jean/github"
+	"github.com/coollabsio/jean/session"
+	"github.com/coollabsio/jean/tui"
+)
+
+// cliContext holds the managers shared by the non-interactive subcommands
+type cliContext struct {
+	repoPath       string // Absolute repository root (same key the TUI uses for config)
+	baseBranch     string
+	jsonOutput     bool
+	gitManager     *git.Manager
+	githubManager  *github.Manager
+	sessionManager *session.Manager
+	configManager  *config.Manager // May be nil if the config could not be loaded
+}
+
+// worktreeJSON is the machine-readable representation of a worktree
+type worktreeJSON struct {
+	Path           string          `json:"path"`
+	Branch         string          `json:"branch"`
+	Detached       bool            `json:"detached,omitempty"`
+	Ref            string          `json:"ref,omitempty"` // Tag or short hash of a detached worktree
+	Commit         string          `json:"commit"`
+	IsCurrent      bool            `json:"is_current"`
+	Managed        bool            `json:"managed"` // Created by jean, not the main worktree or one added by hand
+	Ahead          int             `json:"ahead"`
+	Behind         int             `json:"behind"`
+	HasUncommitted bool            `json:"has_uncommitted"`
+	Prunable       bool            `json:"prunable,omitempty"` // Directory was deleted, `jean gc` prunes it
+	Locked         bool            `json:"locked"`
+	LockReason     string          `json:"lock_reason,omitempty"`
+	SparseDirs     []string        `json:"sparse_dirs,omitempty"` // Directories of a sparse checkout
+	SessionName    string          `json:"session_name"`
+	SessionActive  bool            `json:"session_active"`
+	PRs            []config.PRInfo `json:"prs"`
+	Port           int             `json:"port,omitempty"`
+	PortRange      string          `json:"port_range,omitempty"`
+}
+
+// newCLIContext resolves the repository and loads the managers for a subcommand
+func newCLIContext(path string, jsonOutput bool) (*cliContext, error) {
+	gitManager := git.NewManager(path)
+	root, err := gitManager.GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &cliContext{
+		repoPath:       root,
+		jsonOutput:     jsonOutput,
+		gitManager:     git.NewManager(root),
+		githubManager:  github.NewManager(),
+		sessionManager: session.NewManager(),
+	}
+
+	// Config is optional, fall back to defaults if it can't be loaded
+	if cfg, err := config.NewManager(); err == nil {
+		ctx.configManager = cfg
+		ctx.gitManager.SetConfigManager(cfg)
+		debugLoggingEnabled = cfg.GetDebugLoggingEnabled()
+	}
+
+	// Invalid jean.json entries are skipped by hooks and scripts, say which ones
+	var validationErr *config.ValidationError
+	if _, err := config.LoadScripts(root); errors.As(err, &validationErr) {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid jean.json entries:\n  - %s\n", strings.Join(validationErr.Problems, "\n  - "))
+	}
+
+	ctx.baseBranch = ctx.resolveBaseBranch()
+	return ctx, nil
+}
+
+// resolveBaseBranch mirrors the TUI: saved base branch, then current branch, then default branch
+func (c *cliContext) resolveBaseBranch() string {
+	if c.configManager != nil {
+		if saved := c.configManager.GetBaseBranch(c.repoPath); saved != "" {
+			return saved
+		}
+	}
+	if branch, err := c.gitManager.GetCurrentBranch(); err == nil && branch != "" {
+		return branch
+	}
+	if branch, err := c.gitManager.GetDefaultBranch(); err == nil {
+		return branch
+	}
+	return ""
+}
+
+// sessionName returns the tmux session name used for a branch
+func (c *cliContext) sessionName(branch string) string {
+	return c.sessionManager.SanitizeName(filepath.Base(c.repoPath), branch)
+}
+
+// hookContext builds the JEAN_* environment for a lifecycle hook on a worktree
+func (c *cliContext) hookContext(worktreePath, branch string) git.HookContext {
+	hookCtx := git.HookContext{
+		WorkspacePath: worktreePath,
+		Branch:        branch,
+		BaseBranch:    c.baseBranch,
+	}
+	if c.configManager != nil {
+		if pr := c.configManager.GetLatestPR(c.repoPath, branch); pr != nil {
+			hookCtx.PRURL = pr.URL
+		}
+		hookCtx.Port = c.configManager.GetPortBlock(c.repoPath, worktreePath)
+	}
+	return hookCtx
+}
+
+// runHook runs a lifecycle hook and exits if it fails
+func (c *cliContext) runHook(hook, worktreePath, branch string) {
+	output, err := c.gitManager.RunHook(hook, c.hookContext(worktreePath, branch))
+	if err != nil {
+		exitWithError(err)
+	}
+	// Keep stdout clean for -json consumers
+	if output != "" {
+		fmt.Fprint(os.Stderr, output)
+	}
+}
+
+// findWorktree looks up a worktree by branch name, or by directory name for detached worktrees
+func (c *cliContext) findWorktree(branch string) (*git.Worktree, error) {
+	worktrees, err := c.gitManager.ListLightweight()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range worktrees {
+		if worktrees[i].Name() == branch {
+			return &worktrees[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no worktree found for branch '%s'", branch)
+}
+
+// currentWorktree returns the worktree that contains the working directory
+func (c *cliContext) currentWorktree(path string) (*git.Worktree, error) {
+	root, err := git.NewManager(path).GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees, err := c.gitManager.ListLightweight()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range worktrees {
+		if worktrees[i].Path == root {
+			return &worktrees[i], nil
+		}
+	}
+	return nil, fmt.Errorf("'%s' is not inside a worktree of %s", path, c.repoPath)
+}
+
+// toJSON converts a worktree into its machine-readable representation
+func (c *cliContext) toJSON(wt git.Worktree, activeSessions map[string]bool) worktreeJSON {
+	prs := []config.PRInfo{}
+	if c.configManager != nil {
+		if saved := c.configManager.GetPRs(c.repoPath, wt.Branch); saved != nil {
+			prs = saved
+		}
+	}
+
+	name := c.sessionName(wt.Name())
+	hookCtx := c.hookContext(wt.Path, wt.Branch)
+	return worktreeJSON{
+		Path:           wt.Path,
+		Branch:         wt.Branch,
+		Detached:       wt.Detached,
+		Ref:            wt.Ref,
+		Commit:         wt.Commit,
+		IsCurrent:      wt.IsCurrent,
+		Managed:        wt.Managed,
+		Ahead:          wt.AheadCount,
+		Behind:         wt.BehindCount,
+		HasUncommitted: wt.HasUncommitted,
+		Prunable:       wt.Prunable,
+		Locked:         wt.Locked,
+		LockReason:     wt.LockReason,
+		SparseDirs:     wt.SparseDirs,
+		SessionName:    name,
+		SessionActive:  activeSessions[name],
+		PRs:            prs,
+		Port:           hookCtx.Port,
+		PortRange:      hookCtx.PortRange(),
+	}
+}
+
+// activeSessions returns the set of running jean tmux sessions for the repository
+func (c *cliContext) activeSessions(worktrees []git.Worktree) map[string]bool {
+	active := make(map[string]bool)
+	paths := make([]string, 0, len(worktrees))
+	for _, wt := range worktrees {
+		paths = append(paths, wt.Path)
+	}
+	sessions, err := c.sessionManager.List(c.repoPath, paths...)
+	if err != nil {
+		return active
+	}
+	for _, s := range sessions {
+		active[s.Name] = true
+	}
+	return active
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		exitWithError(err)
+	}
+}
+
+// exitWithError prints an error to stderr and exits with status 1
+func exitWithError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
+
+// parseSubcommandFlags parses flags that may appear before or after positional arguments
+// (the standard flag package stops at the first non-flag argument)
+func parseSubcommandFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return positional
+}
+
+// handleList handles the list subcommand
+func handleList() {
+	listCmd := flag.NewFlagSet("list", flag.ExitOnError)
+	pathFlag := listCmd.String("path", ".", "Path to git repository")
+	jsonFlag := listCmd.Bool("json", false, "Output as JSON")
+	parseSubcommandFlags(listCmd, os.Args[2:])
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	worktrees, err := ctx.gitManager.List(ctx.baseBranch)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	activeSessions := ctx.activeSessions(worktrees)
+	result := make([]worktreeJSON, 0, len(worktrees))
+	for _, wt := range worktrees {
+		result = append(result, ctx.toJSON(wt, activeSessions))
+	}
+
+	if ctx.jsonOutput {
+		printJSON(result)
+		return
+	}
+
+	for _, wt := range result {
+		marker := " "
+		if wt.IsCurrent {
+			marker = "*"
+		}
+		status := fmt.Sprintf("↑%d ↓%d", wt.Ahead, wt.Behind)
+		if wt.HasUncommitted {
+			status += " ●"
+		}
+		if wt.Prunable {
+			status += " ✗ deleted"
+		}
+		if wt.Locked {
+			status += " 🔒"
+		}
+		if wt.SparseDirs != nil {
+			status += " sparse"
+		}
+		if len(wt.PRs) > 0 {
+			latest := wt.PRs[len(wt.PRs)-1]
+			status += fmt.Sprintf(" PR #%d (%s)", latest.PRNumber, latest.Status)
+		}
+		name := wt.Branch
+		if wt.Detached {
+			name = fmt.Sprintf("(detached at %s)", wt.Ref)
+		}
+		fmt.Printf("%s %-30s %-14s %s\n", marker, name, status, wt.Path)
+	}
+}
+
+// handleNew handles the new subcommand
+func handleNew() {
+	newCmd := flag.NewFlagSet("new", flag.ExitOnError)
+	pathFlag := newCmd.String("path", ".", "Path to git repository")
+	jsonFlag := newCmd.Bool("json", false, "Output as JSON")
+	baseFlag := newCmd.String("base", "", "Base branch for the new branch (default: configured base branch)")
+	atFlag := newCmd.String("at", "", "Create a worktree with a detached HEAD at a tag or commit instead of a branch")
+	sparseFlag := newCmd.String("sparse", "", "Sparse-checkout profile of jean.json to check out instead of the whole repository")
+	args := parseSubcommandFlags(newCmd, os.Args[2:])
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+	if *atFlag != "" {
+		ctx.newDetached(*atFlag, args)
+		return
+	}
+	if *baseFlag != "" {
+		ctx.baseBranch = *baseFlag
+	}
+
+	// Empty name generates a random branch name, same as the TUI
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		if name, err = ctx.gitManager.GenerateRandomName(); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	branch := ctx.sessionManager.SanitizeBranchName(name)
+	if branch == "" {
+		exitWithError(fmt.Errorf("branch name '%s' contains no valid characters", name))
+	}
+
+	path, err := ctx.gitManager.GetDefaultPath(branch)
+	if err != nil {
+		exitWithError(err)
+	}
+	if err := ctx.gitManager.EnsureWorkspacesDir(); err != nil {
+		exitWithError(err)
+	}
+
+	// Reuse an existing local branch instead of failing on "branch already exists"
+	exists, _ := ctx.gitManager.BranchExists(ctx.repoPath, branch)
+	setupWarning := ""
+	submoduleWarning := ""
+	copyConflicts := []string{}
+	opts := git.CreateOptions{
+		SparseProfile: *sparseFlag,
+		// Keep stdout clean for -json consumers
+		Progress: func(step string) { fmt.Fprintf(os.Stderr, "%s...\n", step) },
+	}
+	if err := ctx.gitManager.CreateWithOptions(path, branch, !exists, ctx.baseBranch, opts); err != nil {
+		var conflictErr *git.CopyConflictError
+		if errors.As(err, &conflictErr) {
+			copyConflicts = conflictErr.Conflicts
+		}
+		var submoduleErr *git.SubmoduleError
+		if errors.As(err, &submoduleErr) {
+			submoduleWarning = submoduleErr.Error()
+		}
+		var setupErr *git.SetupError
+		if errors.As(err, &setupErr) {
+			// Worktree was created, only the setup script or post_create hook failed
+			setupWarning = setupErr.Err.Error()
+			fmt.Fprintf(os.Stderr, "Warning: worktree created but %s\n", setupErr.Error())
+		} else if conflictErr != nil || submoduleErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: worktree created but %s\n", err.Error())
+		} else {
+			exitWithError(err)
+		}
+	} else if hint := ctx.submoduleHint(path); hint != "" {
+		fmt.Fprintf(os.Stderr, "Note: %s\n", hint)
+	}
+
+	portRange := ctx.hookContext(path, branch).PortRange()
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"path":            path,
+			"branch":          branch,
+			"base_branch":     ctx.baseBranch,
+			"new_branch":      !exists,
+			"session_name":    ctx.sessionName(branch),
+			"port_range":      portRange,
+			"sparse_profile":  *sparseFlag,
+			"setup_error":     setupWarning,
+			"submodule_error": submoduleWarning,
+			"copy_conflicts":  copyConflicts,
+		})
+		return
+	}
+
+	fmt.Printf("✓ Created worktree for '%s' at %s\n", branch, path)
+	if *sparseFlag != "" {
+		fmt.Printf("  Sparse checkout: %s\n", *sparseFlag)
+	}
+	if portRange != "" {
+		fmt.Printf("  Ports: %s (JEAN_PORT / JEAN_PORT_RANGE)\n", portRange)
+	}
+}
+
+// newDetached creates a worktree at a tag or commit for `jean new -at`. It is named after the
+// optional name argument, or else after the ref.
+func (c *cliContext) newDetached(ref string, args []string) {
+	name := ref
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	path, err := c.gitManager.GetDefaultPath(name)
+	if err != nil {
+		exitWithError(err)
+	}
+	if err := c.gitManager.EnsureWorkspacesDir(); err != nil {
+		exitWithError(err)
+	}
+
+	setupWarning := ""
+	copyConflicts := []string{}
+	if err := c.gitManager.CreateDetached(path, ref); err != nil {
+		var conflictErr *git.CopyConflictError
+		var submoduleErr *git.SubmoduleError
+		var setupErr *git.SetupError
+		if errors.As(err, &conflictErr) {
+			copyConflicts = conflictErr.Conflicts
+		}
+		if errors.As(err, &setupErr) {
+			setupWarning = setupErr.Err.Error()
+			fmt.Fprintf(os.Stderr, "Warning: worktree created but %s\n", setupErr.Error())
+		} else if conflictErr != nil || errors.As(err, &submoduleErr) {
+			fmt.Fprintf(os.Stderr, "Warning: worktree created but %s\n", err.Error())
+		} else {
+			exitWithError(err)
+		}
+	}
+
+	sessionName := c.sessionName(filepath.Base(path))
+	portRange := c.hookContext(path, "").PortRange()
+	if c.jsonOutput {
+		printJSON(map[string]interface{}{
+			"path":           path,
+			"ref":            ref,
+			"detached":       true,
+			"session_name":   sessionName,
+			"port_range":     portRange,
+			"setup_error":    setupWarning,
+			"copy_conflicts": copyConflicts,
+		})
+		return
+	}
+
+	fmt.Printf("✓ Created worktree at %s in %s (detached HEAD)\n", ref, path)
+	fmt.Printf("  Switch to it with 'jean switch %s', create a branch in it with 'git switch -c <branch>'\n", filepath.Base(path))
+	if portRange != "" {
+		fmt.Printf("  Ports: %s (JEAN_PORT / JEAN_PORT_RANGE)\n", portRange)
+	}
+}
+
+// submoduleHint tells how to finish a new worktree that has submodules or LFS files, when the
+// repository setting to do it on creation is off. Returns "" if there is nothing to do.
+func (c *cliContext) submoduleHint(worktreePath string) string {
+	if c.configManager != nil && c.configManager.GetInitSubmodules(c.repoPath) {
+		return ""
+	}
+	return git.SubmoduleHint(c.gitManager.DetectSubmodulesAndLFS(worktreePath))
+}
+
+// handleRm handles the rm subcommand
+func handleRm() {
+	rmCmd := flag.NewFlagSet("rm", flag.ExitOnError)
+	pathFlag := rmCmd.String("path", ".", "Path to git repository")
+	jsonFlag := rmCmd.Bool("json", false, "Output as JSON")
+	forceFlag := rmCmd.Bool("force", false, "Remove even if the worktree has uncommitted changes or is locked")
+	args := parseSubcommandFlags(rmCmd, os.Args[2:])
+
+	if len(args) == 0 {
+		exitWithError(fmt.Errorf("usage: jean rm <branch> [-force]"))
+	}
+	branch := args[0]
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	wt, err := ctx.findWorktree(branch)
+	if err != nil {
+		exitWithError(err)
+	}
+	if wt.IsCurrent {
+		exitWithError(fmt.Errorf("cannot delete current worktree"))
+	}
+
+	if !*forceFlag {
+		if hasUncommitted, err := ctx.gitManager.HasUncommittedChanges(wt.Path); err == nil && hasUncommitted {
+			exitWithError(fmt.Errorf("worktree '%s' has uncommitted changes (use -force to remove anyway)", branch))
+		}
+	}
+
+	ctx.runHook(config.HookPreDelete, wt.Path, branch)
+
+	if err := ctx.gitManager.Remove(wt.Path, *forceFlag); err != nil {
+		exitWithError(err)
+	}
+
+	// Same cleanup as the TUI: drop branch config and kill the tmux session
+	if ctx.configManager != nil {
+		_ = ctx.configManager.CleanupBranch(ctx.repoPath, branch)
+	}
+	sessionName := ctx.sessionName(branch)
+	_ = ctx.sessionManager.Kill(sessionName)
+
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"path":         wt.Path,
+			"branch":       branch,
+			"session_name": sessionName,
+			"removed":      true,
+		})
+		return
+	}
+
+	fmt.Printf("✓ Removed worktree for '%s'\n", branch)
+}
+
+// handleLock handles the lock and unlock subcommands
+func handleLock(lock bool) {
+	name := "unlock"
+	if lock {
+		name = "lock"
+	}
+	lockCmd := flag.NewFlagSet(name, flag.ExitOnError)
+	pathFlag := lockCmd.String("path", ".", "Path to git repository")
+	jsonFlag := lockCmd.Bool("json", false, "Output as JSON")
+	reasonFlag := lockCmd.String("reason", "", "Why the worktree is locked (lock only)")
+	args := parseSubcommandFlags(lockCmd, os.Args[2:])
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+	wt, err := ctx.resolveTargetWorktree(*pathFlag, args)
+	if err != nil {
+		exitWithError(err)
+	}
+	if wt.IsCurrent {
+		exitWithError(fmt.Errorf("the main worktree cannot be locked"))
+	}
+
+	if lock {
+		err = ctx.gitManager.Lock(wt.Path, *reasonFlag)
+	} else {
+		err = ctx.gitManager.Unlock(wt.Path)
+	}
+	if err != nil {
+		exitWithError(err)
+	}
+
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"path":   wt.Path,
+			"branch": wt.Branch,
+			"locked": lock,
+		})
+		return
+	}
+	if lock {
+		fmt.Printf("✓ Locked worktree for '%s'\n", wt.Branch)
+	} else {
+		fmt.Printf("✓ Unlocked worktree for '%s'\n", wt.Branch)
+	}
+}
+
+// handleAdopt handles the adopt subcommand: a worktree added with git worktree add becomes a
+// managed one (recorded in the config, port block, tmux session), optionally moved with -move
+func handleAdopt() {
+	adoptCmd := flag.NewFlagSet("adopt", flag.ExitOnError)
+	pathFlag := adoptCmd.String("path", ".", "Path to git repository")
+	jsonFlag := adoptCmd.Bool("json", false, "Output as JSON")
+	moveFlag := adoptCmd.Bool("move", false, "Move the worktree to where jean creates worktrees")
+	args := parseSubcommandFlags(adoptCmd, os.Args[2:])
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+	wt, err := ctx.resolveTargetWorktree(*pathFlag, args)
+	if err != nil && len(args) > 0 {
+		// Not a branch name, try it as the path of the worktree
+		wt, err = ctx.currentWorktree(args[0])
+	}
+	if err != nil {
+		exitWithError(err)
+	}
+
+	path, err := ctx.gitManager.Adopt(wt.Path, *moveFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+	sessionName := ctx.sessionName(wt.Name())
+	if ctx.sessionManager.IsTmuxAvailable() {
+		if err := ctx.sessionManager.CreateDetached(sessionName, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"path":         path,
+			"branch":       wt.Branch,
+			"moved":        path != wt.Path,
+			"session_name": sessionName,
+			"port_range":   ctx.hookContext(path, wt.Branch).PortRange(),
+		})
+		return
+	}
+	fmt.Printf("✓ Adopted worktree for '%s'\n", wt.Name())
+	if path != wt.Path {
+		fmt.Printf("  Moved to %s\n", path)
+	}
+}
+
+// gcJSON is the machine-readable representation of a stale worktree
+type gcJSON struct {
+	Path           string   `json:"path"`
+	Branch         string   `json:"branch"`
+	Reasons        []string `json:"reasons"`
+	HasUncommitted bool     `json:"has_uncommitted"`
+	Prunable       bool     `json:"prunable"`
+	Locked         bool     `json:"locked"`
+	Removed        bool     `json:"removed"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// handleGC handles the gc subcommand.
+// Without -yes it only lists the stale worktrees and orphaned config/tmux state (dry run).
+func handleGC() {
+	gcCmd := flag.NewFlagSet("gc", flag.ExitOnError)
+	pathFlag := gcCmd.String("path", ".", "Path to git repository")
+	jsonFlag := gcCmd.Bool("json", false, "Output as JSON")
+	daysFlag := gcCmd.Int("days", git.DefaultStaleDays, "Days without changes before a worktree is stale (0 disables)")
+	yesFlag := gcCmd.Bool("yes", false, "Remove the stale worktrees instead of only listing them")
+	forceFlag := gcCmd.Bool("force", false, "Also remove locked worktrees and worktrees with uncommitted changes")
+	parseSubcommandFlags(gcCmd, os.Args[2:])
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	result, orphanedBranches, orphanedSessions, err := ctx.collectGarbage(*daysFlag, *yesFlag, *forceFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"worktrees":         result,
+			"orphaned_branches": orphanedBranches,
+			"orphaned_sessions": orphanedSessions,
+			"removed":           *yesFlag,
+		})
+		return
+	}
+
+	if len(result) == 0 && len(orphanedBranches) == 0 && len(orphanedSessions) == 0 {
+		fmt.Println("No stale worktrees")
+		return
+	}
+	for _, entry := range result {
+		status := strings.Join(entry.Reasons, ", ")
+		if entry.HasUncommitted {
+			status += " ●"
+		}
+		if entry.Locked {
+			status += " 🔒"
+		}
+		switch {
+		case entry.Removed:
+			fmt.Printf("✓ Removed %-30s %s\n", entry.Branch, status)
+		case entry.Error != "":
+			fmt.Printf("✗ Skipped %-30s %s\n", entry.Branch, entry.Error)
+		default:
+			fmt.Printf("  %-30s %-40s %s\n", entry.Branch, status, entry.Path)
+		}
+	}
+
+	prefix := "  Orphaned"
+	if *yesFlag {
+		prefix = "✓ Cleaned up orphaned"
+	}
+	if len(orphanedBranches) > 0 {
+		fmt.Printf("%s config entries: %s\n", prefix, strings.Join(orphanedBranches, ", "))
+	}
+	if len(orphanedSessions) > 0 {
+		fmt.Printf("%s tmux sessions: %s\n", prefix, strings.Join(orphanedSessions, ", "))
+	}
+	if !*yesFlag {
+		fmt.Printf("\n%d stale worktree(s). Run 'jean gc -yes' to remove them\n", len(result))
+	}
+}
+
+// collectGarbage finds the stale worktrees and the orphaned config entries and tmux sessions.
+// With remove they are cleaned up too, each entry records whether its worktree was removed or
+// why it was skipped.
+func (c *cliContext) collectGarbage(maxAgeDays int, remove, force bool) ([]gcJSON, []string, []string, error) {
+	stale, err := c.gitManager.FindStaleWorktrees(c.baseBranch, maxAgeDays, force)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	orphanedBranches, orphanedSessions := c.findOrphans()
+
+	result := make([]gcJSON, 0, len(stale))
+	for _, wt := range stale {
+		entry := gcJSON{Path: wt.Path, Branch: wt.Branch, Reasons: wt.Reasons, HasUncommitted: wt.HasUncommitted, Prunable: wt.Prunable, Locked: wt.Locked}
+		if remove {
+			if err := c.removeStaleWorktree(wt, force); err != nil {
+				entry.Error = err.Error()
+			} else {
+				entry.Removed = true
+			}
+		}
+		result = append(result, entry)
+	}
+	if remove {
+		for _, branch := range orphanedBranches {
+			_ = c.configManager.CleanupBranch(c.repoPath, branch)
+		}
+		for _, name := range orphanedSessions {
+			_ = c.sessionManager.Kill(name)
+		}
+	}
+	return result, orphanedBranches, orphanedSessions, nil
+}
+
+// findOrphans returns the branches with saved config and the jean tmux sessions that belong
+// to no worktree anymore. Worktrees whose directory was deleted still count, gc cleans them up
+// with their worktree.
+func (c *cliContext) findOrphans() (branches []string, sessions []string) {
+	branches, sessions = []string{}, []string{}
+	worktrees, err := c.gitManager.ListLightweight()
+	if err != nil {
+		return branches, sessions
+	}
+	live := make([]string, 0, len(worktrees))
+	paths := make([]string, 0, len(worktrees))
+	for _, wt := range worktrees {
+		live = append(live, wt.Name())
+		paths = append(paths, wt.Path)
+	}
+
+	if c.configManager != nil {
+		branches = append(branches, c.configManager.OrphanedBranches(c.repoPath, live)...)
+	}
+	if orphaned, err := c.sessionManager.Orphaned(c.repoPath, live, paths...); err == nil {
+		for _, sess := range orphaned {
+			sessions = append(sessions, sess.Name)
+		}
+	}
+	return branches, sessions
+}
+
+// removeStaleWorktree removes a worktree found by gc the same way rm does: pre_delete hook,
+// worktree and branch, config entries and tmux session. The directory of a prunable worktree
+// is already gone (PruneWorktrees drops its metadata), so only its config and session are
+// cleaned up and its branch is kept.
+func (c *cliContext) removeStaleWorktree(wt git.StaleWorktree, force bool) error {
+	if wt.Locked && !force {
+		return fmt.Errorf("locked (use -force to remove anyway)")
+	}
+
+	if wt.Prunable {
+		// git never prunes locked worktrees
+		if wt.Locked {
+			if err := c.gitManager.Unlock(wt.Path); err != nil {
+				return err
+			}
+		}
+		if err := c.gitManager.PruneWorktrees(); err != nil {
+			return err
+		}
+	} else {
+		if wt.HasUncommitted && !force {
+			return fmt.Errorf("uncommitted changes (use -force to remove anyway)")
+		}
+		if output, err := c.gitManager.RunHook(config.HookPreDelete, c.hookContext(wt.Path, wt.Branch)); err != nil {
+			return err
+		} else if output != "" {
+			fmt.Fprint(os.Stderr, output)
+		}
+		if err := c.gitManager.Remove(wt.Path, force); err != nil {
+			return err
+		}
+	}
+
+	if c.configManager != nil {
+		_ = c.configManager.CleanupBranch(c.repoPath, wt.Name())
+	}
+	_ = c.sessionManager.Kill(c.sessionName(wt.Name()))
+	return nil
+}
+
+// handleSwitch handles the switch subcommand.
+// The switch info is handed to the shell wrapper the same way the TUI does it.
+func handleSwitch() {
+	switchCmd := flag.NewFlagSet("switch", flag.ExitOnError)
+	pathFlag := switchCmd.String("path", ".", "Path to git repository")
+	jsonFlag := switchCmd.Bool("json", false, "Output as JSON")
+	terminalFlag := switchCmd.Bool("terminal", false, "Attach to the terminal window instead of Claude")
+	noClaudeFlag := switchCmd.Bool("no-claude", false, "Don't auto-start Claude CLI in tmux session")
+	args := parseSubcommandFlags(switchCmd, os.Args[2:])
+
+	if len(args) == 0 {
+		exitWithError(fmt.Errorf("usage: jean switch <branch> [-terminal] [-no-claude]"))
+	}
+	branch := args[0]
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	wt, err := ctx.findWorktree(branch)
+	if err != nil {
+		exitWithError(err)
+	}
+	if err := ctx.gitManager.EnsureWorktreeExists(wt.Path, wt.Branch); err != nil {
+		exitWithError(err)
+	}
+	ctx.runHook(config.HookPostSwitch, wt.Path, wt.Branch)
+
+	switchInfo := tui.SwitchInfo{
+		Path:         wt.Path,
+		Branch:       wt.Branch,
+		SessionName:  ctx.sessionName(wt.Name()),
+		AutoClaude:   !*noClaudeFlag && !*terminalFlag,
+		TargetWindow: "claude",
+	}
+	if *terminalFlag {
+		switchInfo.TargetWindow = "terminal"
+	}
+
+	if ctx.configManager != nil {
+		_ = ctx.configManager.SetLastSelectedBranch(ctx.repoPath, wt.Branch)
+		if switchInfo.AutoClaude {
+			switchInfo.IsClaudeInitialized = ctx.configManager.IsClaudeInitialized(ctx.repoPath, wt.Name())
+			if !switchInfo.IsClaudeInitialized {
+				_ = ctx.configManager.SetClaudeInitialized(ctx.repoPath, wt.Name())
+			}
+		}
+	}
+
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"path":          switchInfo.Path,
+			"branch":        switchInfo.Branch,
+			"session_name":  switchInfo.SessionName,
+			"target_window": switchInfo.TargetWindow,
+		})
+		// Only hand off to the wrapper when it is listening, never mix with JSON on stdout
+		if os.Getenv("JEAN_SWITCH_FILE") == "" {
+			return
+		}
+	}
+
+	writeSwitchInfo(switchInfo)
+}
+
+// handlePush handles the push subcommand
+func handlePush() {
+	pushCmd := flag.NewFlagSet("push", flag.ExitOnError)
+	pathFlag := pushCmd.String("path", ".", "Path to git repository")
+	jsonFlag := pushCmd.Bool("json", false, "Output as JSON")
+	args := parseSubcommandFlags(pushCmd, os.Args[2:])
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	wt, err := ctx.resolveTargetWorktree(*pathFlag, args)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	ctx.runHook(config.HookPrePush, wt.Path, wt.Branch)
+
+	if err := ctx.gitManager.Push(wt.Path, wt.Branch); err != nil {
+		exitWithError(err)
+	}
+
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"path":   wt.Path,
+			"branch": wt.Branch,
+			"pushed": true,
+		})
+		return
+	}
+
+	fmt.Printf("✓ Pushed '%s'\n", wt.Branch)
+}
+
+// handlePR handles the pr subcommand
+func handlePR() {
+	prCmd := flag.NewFlagSet("pr", flag.ExitOnError)
+	pathFlag := prCmd.String("path", ".", "Path to git repository")
+	jsonFlag := prCmd.Bool("json", false, "Output as JSON")
+	titleFlag := prCmd.String("title", "", "PR title (default: derived from branch name)")
+	bodyFlag := prCmd.String("body", "", "PR description")
+	draftFlag := prCmd.Bool("draft", false, "Create as draft (default: repository PR state setting)")
+	readyFlag := prCmd.Bool("ready", false, "Create as ready for review")
+	args := parseSubcommandFlags(prCmd, os.Args[2:])
+
+	ctx, err := newCLIContext(*pathFlag, *jsonFlag)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	wt, err := ctx.resolveTargetWorktree(*pathFlag, args)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	if ctx.baseBranch == "" {
+		exitWithError(fmt.Errorf("base branch not set"))
+	}
+	if wt.Branch == ctx.baseBranch {
+		exitWithError(fmt.Errorf("cannot create a PR from the base branch '%s'", ctx.baseBranch))
+	}
+
+	isGitHub, err := ctx.gitManager.IsGitHubRepo()
+	if err != nil {
+		exitWithError(fmt.Errorf("failed to check repository: %w", err))
+	}
+	if !isGitHub {
+		exitWithError(fmt.Errorf("not a GitHub repository"))
+	}
+
+	// Push first if the remote is missing the branch or some commits
+	hasUnpushed, err := ctx.gitManager.HasUnpushedCommits(wt.Path, wt.Branch)
+	if err != nil {
+		exitWithError(fmt.Errorf("failed to check for unpushed commits: %w", err))
+	}
+	if hasUnpushed {
+		ctx.runHook(config.HookPrePush, wt.Path, wt.Branch)
+		if err := ctx.gitManager.Push(wt.Path, wt.Branch); err != nil {
+			exitWithError(fmt.Errorf("failed to push commits: %w", err))
+		}
+	}
+
+	title := *titleFlag
+	if title == "" {
+		title = github.TitleFromBranch(wt.Branch)
+	}
+
+	isDraft := false
+	if ctx.configManager != nil {
+		isDraft = ctx.configManager.GetPRDefaultState(ctx.repoPath) == "draft"
+	}
+	if *draftFlag {
+		isDraft = true
+	} else if *readyFlag {
+		isDraft = false
+	}
+
+	// From a fork, the PR targets the base remote's repository
+	repo, headOwner, err := ctx.gitManager.PRRepos()
+	if err != nil {
+		exitWithError(err)
+	}
+	opts := github.PROptions{Draft: isDraft, Repo: repo, HeadOwner: headOwner}
+	prURL, err := ctx.githubManager.CreatePRWithOptions(wt.Path, wt.Branch, ctx.baseBranch, title, *bodyFlag, opts)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	prNumber := 0
+	if parts := strings.Split(prURL, "/pull/"); len(parts) == 2 {
+		fmt.Sscanf(parts[1], "%d", &prNumber)
+	}
+	author, _ := ctx.gitManager.GetCurrentUser(wt.Path)
+	if ctx.configManager != nil {
+		_ = ctx.configManager.AddPR(ctx.repoPath, wt.Branch, prURL, prNumber, title, author)
+	}
+
+	if ctx.jsonOutput {
+		printJSON(map[string]interface{}{
+			"url":         prURL,
+			"number":      prNumber,
+			"branch":      wt.Branch,
+			"base_branch": ctx.baseBranch,
+			"title":       title,
+			"draft":       isDraft,
+		})
+		return
+	}
+
+	fmt.Printf("✓ Created PR: %s\n", prURL)
+}
+
+// resolveTargetWorktree picks the worktree named by the optional branch argument,
+// falling back to the worktree that contains path
+func (c *cliContext) resolveTargetWorktree(path string, args []string) (*git.Worktree, error) {
+	if len(args) > 0 {
+		return c.findWorktree(args[0])
+	}
+	wt, err := c.currentWorktree(path)
+	if err != nil {
+		return nil, err
+	}
+	if wt.Detached || wt.Branch == "" {
+		return nil, fmt.Errorf("worktree at %s is not on a branch", wt.Path)
+	}
+	return wt, nil
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+	"github.com/coollabsio/jean/git"
+	"github.com/coollabsio/jean/github"
+	"github.com/coollabsio/jean/session"
+)
+
+// TestMain runs jean itself instead of the tests when re-executed by runJean
+func TestMain(m *testing.M) {
+	if args := os.Getenv("JEAN_TEST_ARGS"); args != "" {
+		os.Args = append([]string{"jean"}, strings.Split(args, "\n")...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runJean runs jean with args in a separate process and returns its exit code and stderr
+func runJean(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), "JEAN_TEST_ARGS="+strings.Join(args, "\n"), "JEAN_INIT_ATTEMPTED=1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), stderr.String()
+	} else if err != nil {
+		t.Fatalf("running jean %v: %v", args, err)
+	}
+	return 0, stderr.String()
+}
+
+// initRepo creates a git repository with one commit and returns its (symlink-free) root
+func initRepo(t *testing.T) string {
+	t.Helper()
+	repoRoot, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoRoot
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	return repoRoot
+}
+
+// newTestContext returns the context of the subcommands for /repo, with git answered by runner,
+// a fresh config and no tmux server
+func newTestContext(t *testing.T, runner *git.FakeRunner) *cliContext {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+
+	gitManager := git.NewManagerWithRunner("/repo", runner)
+	gitManager.SetConfigManager(configManager)
+	return &cliContext{
+		repoPath:       "/repo",
+		baseBranch:     "main",
+		gitManager:     gitManager,
+		githubManager:  github.NewManager(),
+		sessionManager: session.NewManager(),
+		configManager:  configManager,
+	}
+}
+
+func TestParseSubcommandFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		want  []string
+		force bool
+		path  string
+	}{
+		{name: "flags first", args: []string{"-force", "feature"}, want: []string{"feature"}, force: true, path: "."},
+		{name: "flags last", args: []string{"feature", "-force"}, want: []string{"feature"}, force: true, path: "."},
+		{name: "flags in between", args: []string{"a", "-path", "/code/app", "b"}, want: []string{"a", "b"}, path: "/code/app"},
+		{name: "no arguments", args: nil, want: nil, path: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("rm", flag.ContinueOnError)
+			force := fs.Bool("force", false, "")
+			path := fs.String("path", ".", "")
+			if got := parseSubcommandFlags(fs, tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected positional arguments %q, got %q", tt.want, got)
+			}
+			if *force != tt.force || *path != tt.path {
+				t.Errorf("expected -force=%v -path=%q, got -force=%v -path=%q", tt.force, tt.path, *force, *path)
+			}
+		})
+	}
+}
+
+func TestSubcommands_InvalidArguments(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repoRoot := initRepo(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "rm without branch", args: []string{"rm"}, want: "usage: jean rm <branch> [-force]"},
+		{name: "switch without branch", args: []string{"switch"}, want: "usage: jean switch <branch>"},
+		{name: "not a repository", args: []string{"list", "-path", t.TempDir()}, want: "Error:"},
+		{name: "unknown branch", args: []string{"rm", "missing", "-path", repoRoot}, want: "no worktree found for branch 'missing'"},
+		{name: "branch name without valid characters", args: []string{"new", "-path", repoRoot, "???"}, want: "branch name '???' contains no valid characters"},
+		{name: "lock the main worktree", args: []string{"lock", "-path", repoRoot}, want: "the main worktree cannot be locked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stderr := runJean(t, tt.args...)
+			if code != 1 || !strings.Contains(stderr, tt.want) {
+				t.Errorf("expected exit status 1 with %q, got %d with %q", tt.want, code, stderr)
+			}
+		})
+	}
+}
+
+func TestFindWorktree_ByName(t *testing.T) {
+	runner := git.NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/feature-login\nHEAD aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nbranch refs/heads/feature/login\n\n"+
+		"worktree /repo/.workspaces/v1.0\nHEAD bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\ndetached\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	ctx := newTestContext(t, runner)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "feature/login", want: "/repo/.workspaces/feature-login"},
+		{name: "v1.0", want: "/repo/.workspaces/v1.0"},
+		{name: "main", want: "/repo"},
+	}
+	for _, tt := range tests {
+		wt, err := ctx.findWorktree(tt.name)
+		if err != nil || wt.Path != tt.want {
+			t.Errorf("findWorktree(%q): expected %s, got %+v (%v)", tt.name, tt.want, wt, err)
+		}
+	}
+
+	// The directory name of a worktree on a branch is not its name
+	if wt, err := ctx.findWorktree("feature-login"); err == nil {
+		t.Errorf("expected no worktree for the directory name of a branch, got %+v", wt)
+	}
+
+	wt, err := ctx.resolveTargetWorktree(".", []string{"v1.0"})
+	if err != nil || !wt.Detached {
+		t.Errorf("expected the detached worktree, got %+v (%v)", wt, err)
+	}
+}
+
+func TestResolveTargetWorktree_CurrentWorktree(t *testing.T) {
+	repoRoot := initRepo(t)
+	subdir := filepath.Join(repoRoot, "src")
+	if err := os.Mkdir(subdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := git.NewFakeRunner()
+	runner.On("worktree "+repoRoot+"\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n", "worktree", "list", "--porcelain")
+	ctx := newTestContext(t, runner)
+
+	wt, err := ctx.resolveTargetWorktree(subdir, nil)
+	if err != nil || wt.Path != repoRoot || wt.Branch != "main" {
+		t.Fatalf("expected the worktree containing %s, got %+v (%v)", subdir, wt, err)
+	}
+
+	runner.On("worktree "+repoRoot+"\nHEAD 1111111111111111111111111111111111111111\ndetached\n\n", "worktree", "list", "--porcelain")
+	if _, err := ctx.resolveTargetWorktree(subdir, nil); err == nil || !strings.Contains(err.Error(), "is not on a branch") {
+		t.Errorf("expected a detached worktree to be refused, got %v", err)
+	}
+
+	if _, err := ctx.resolveTargetWorktree(t.TempDir(), nil); err == nil {
+		t.Error("expected an error outside of a repository")
+	}
+}
+
+func TestFindOrphans(t *testing.T) {
+	runner := git.NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/feature\nHEAD aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nbranch refs/heads/feature\n\n"+
+		"worktree /repo/.workspaces/v1.0\nHEAD bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\ndetached\n\n"+
+		"worktree /repo/.workspaces/gone\nHEAD cccccccccccccccccccccccccccccccccccccccc\nbranch refs/heads/gone\nprunable gitdir file points to non-existent location\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	ctx := newTestContext(t, runner)
+
+	for _, branch := range []string{"feature", "gone", "deleted"} {
+		if err := ctx.configManager.AddPR("/repo", branch, "https://github.com/o/r/pull/1", 1, branch, "me"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ctx.configManager.SetClaudeInitialized("/repo", "v1.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	branches, sessions := ctx.findOrphans()
+	if !reflect.DeepEqual(branches, []string{"deleted"}) {
+		t.Errorf("expected only the branch without a worktree to be orphaned, got %q", branches)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("expected no orphaned sessions without tmux, got %q", sessions)
+	}
+}
+
+// staleRunner lists a worktree whose directory was deleted, a locked one whose directory was
+// deleted and a merged one with uncommitted changes
+func staleRunner() *git.FakeRunner {
+	runner := git.NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/gone\nHEAD aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nbranch refs/heads/gone\nprunable gitdir file points to non-existent location\n\n"+
+		"worktree /repo/.workspaces/kept\nHEAD bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\nbranch refs/heads/kept\nlocked on a USB drive\nprunable gitdir file points to non-existent location\n\n"+
+		"worktree /repo/.workspaces/wip\nHEAD cccccccccccccccccccccccccccccccccccccccc\nbranch refs/heads/wip\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	runner.On("ccc\n", "rev-parse", "--verify", "wip^{commit}")
+	runner.On("ddd\n", "rev-list", "--first-parent", "main", "--not", "ccc^@")
+	runner.On(" M main.go\n", "status", "--porcelain")
+	return runner
+}
+
+func TestCollectGarbage(t *testing.T) {
+	tests := []struct {
+		name    string
+		remove  bool
+		force   bool
+		want    []string // Branch and outcome of each stale worktree
+		unlock  bool
+		cleanup bool
+	}{
+		{
+			name: "dry run",
+			want: []string{"gone: listed", "wip: listed"},
+		},
+		{
+			name:    "remove",
+			remove:  true,
+			want:    []string{"gone: removed", "wip: uncommitted changes (use -force to remove anyway)"},
+			cleanup: true,
+		},
+		{
+			name:    "remove with force",
+			remove:  true,
+			force:   true,
+			want:    []string{"gone: removed", "kept: removed", "wip: removed"},
+			unlock:  true,
+			cleanup: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := staleRunner()
+			ctx := newTestContext(t, runner)
+			if err := ctx.configManager.AddPR("/repo", "deleted", "https://github.com/o/r/pull/1", 1, "deleted", "me"); err != nil {
+				t.Fatal(err)
+			}
+
+			result, branches, _, err := ctx.collectGarbage(0, tt.remove, tt.force)
+			if err != nil {
+				t.Fatalf("collectGarbage: %v", err)
+			}
+			var got []string
+			for _, entry := range result {
+				outcome := "listed"
+				if entry.Removed {
+					outcome = "removed"
+				} else if entry.Error != "" {
+					outcome = entry.Error
+				}
+				got = append(got, entry.Branch+": "+outcome)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if !reflect.DeepEqual(branches, []string{"deleted"}) {
+				t.Errorf("expected the orphaned config entry to be reported, got %q", branches)
+			}
+
+			if runner.Ran("worktree", "prune") != tt.remove {
+				t.Errorf("expected worktree prune to run only when removing, calls %v", runner.Calls())
+			}
+			if runner.Ran("worktree", "remove", "--force", "/repo/.workspaces/wip") != tt.force {
+				t.Errorf("expected wip to be force-removed only with -force, calls %v", runner.Calls())
+			}
+			if runner.Ran("worktree", "unlock", "/repo/.workspaces/kept") != tt.unlock {
+				t.Errorf("expected the locked worktree to be unlocked only with -force, calls %v", runner.Calls())
+			}
+			if orphaned := ctx.configManager.OrphanedBranches("/repo", nil); (len(orphaned) == 0) != tt.cleanup {
+				t.Errorf("expected the orphaned config entry to be cleaned up only when removing, got %q", orphaned)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 	"github.com/coollabsio/jean/openrouter"
 )
 
@@ -28,6 +30,8 @@ type Config struct {
 	AIPrompts           *AIPrompts             `json:"ai_prompts,omitempty"` // Customizable AI prompts
 	WrapperChecksums    map[string]string      `json:"wrapper_checksums,omitempty"` // Shell -> SHA256 checksum of installed wrapper
 	Onboarded           bool                   `json:"onboarded"` // Whether the user has completed the onboarding flow
+	WorktreeRoot        string                 `json:"worktree_root,omitempty"` // Default directory of new worktrees, "" = .workspaces
+	WorktreeTemplate    string                 `json:"worktree_template,omitempty"` // Default path template of new worktrees, "" = {branch}
 }
 
 // PRInfo represents information about a pull request
@@ -51,6 +55,24 @@ type RepoConfig struct {
 	PRDefaultState     string            `json:"pr_default_state,omitempty"`    // "draft" or "ready", "" = use default (ready)
 	PRs                map[string][]PRInfo `json:"prs,omitempty"`                 // branch -> list of PRs
 	InitializedClaudes map[string]bool   `json:"initialized_claudes,omitempty"` // branch -> whether Claude has been started
+	Ports              map[string]int    `json:"ports,omitempty"`               // worktree path -> first port of its block
+	UpdateMode         string            `json:"update_mode,omitempty"`         // "merge" or "rebase" for updating from base, "" = merge
+	AutoStash          bool              `json:"auto_stash,omitempty"`          // Stash uncommitted changes around updates from base
+	CommitSignoff      bool              `json:"commit_signoff,omitempty"`      // Add Signed-off-by to commits (--signoff)
+	CommitGPGSign      string            `json:"commit_gpg_sign,omitempty"`     // "always", "never" or "" = follow git's commit.gpgsign
+	InitSubmodules     bool              `json:"init_submodules,omitempty"`     // Init submodules and pull LFS files in new worktrees
+	PushRemote         string            `json:"push_remote,omitempty"`         // Remote branches are pushed to, "" = origin
+	BaseRemote         string            `json:"base_remote,omitempty"`         // Remote of the base branch PRs target, "" = origin
+	WorktreeRoot       string            `json:"worktree_root,omitempty"`       // Directory of new worktrees, "" = global default
+	WorktreeTemplate   string            `json:"worktree_template,omitempty"`   // Path template of new worktrees, "" = global default
+	Worktrees          map[string]ManagedWorktree `json:"worktrees,omitempty"` // worktree path -> worktree created by jean
+}
+
+// ManagedWorktree records a worktree created (or adopted) by jean, telling it apart from the
+// main worktree and worktrees added by hand
+type ManagedWorktree struct {
+	CreatedAt string `json:"created_at,omitempty"` // RFC3339 format
+	Adopted   bool   `json:"adopted,omitempty"`    // Added by hand and adopted later
 }
 
 // Manager handles configuration loading and saving
@@ -488,6 +510,108 @@ func (m *Manager) CleanupBranch(repoPath, branch string) error {
 	return m.save()
 }
 
+// OrphanedBranches returns the branches with saved PRs or Claude state that are not in branches,
+// e.g. because their worktree was deleted outside jean
+func (m *Manager) OrphanedBranches(repoPath string, branches []string) []string {
+	repo, ok := m.config.Repositories[repoPath]
+	if !ok {
+		return nil
+	}
+
+	live := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		live[branch] = true
+	}
+	orphaned := map[string]bool{}
+	for branch := range repo.PRs {
+		if !live[branch] {
+			orphaned[branch] = true
+		}
+	}
+	for branch := range repo.InitializedClaudes {
+		if !live[branch] {
+			orphaned[branch] = true
+		}
+	}
+
+	result := make([]string, 0, len(orphaned))
+	for branch := range orphaned {
+		result = append(result, branch)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Port blocks handed out to worktrees (JEAN_PORT / JEAN_PORT_RANGE)
+const (
+	PortRangeStart = 20000 // First port of the first block
+	PortRangeEnd   = 30000 // Blocks never reach this port
+	PortBlockSize  = 10    // Number of ports per worktree
+)
+
+// GetPortBlock returns the first port of a worktree's port block, or 0 if none was allocated
+func (m *Manager) GetPortBlock(repoPath, worktreePath string) int {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		if repo.Ports != nil {
+			return repo.Ports[worktreePath]
+		}
+	}
+	return 0
+}
+
+// AllocatePortBlock returns the port block of a worktree, allocating the lowest free one
+// if the worktree has none yet. Blocks are unique across all repositories and stay with
+// the worktree (branch renames don't change them) until ReleasePortBlock is called.
+func (m *Manager) AllocatePortBlock(repoPath, worktreePath string) (int, error) {
+	if port := m.GetPortBlock(repoPath, worktreePath); port != 0 {
+		return port, nil
+	}
+
+	used := make(map[int]bool)
+	for _, repo := range m.config.Repositories {
+		for _, port := range repo.Ports {
+			used[port] = true
+		}
+	}
+
+	for port := PortRangeStart; port+PortBlockSize <= PortRangeEnd; port += PortBlockSize {
+		if used[port] {
+			continue
+		}
+
+		if m.config.Repositories == nil {
+			m.config.Repositories = make(map[string]*RepoConfig)
+		}
+
+		if _, ok := m.config.Repositories[repoPath]; !ok {
+			m.config.Repositories[repoPath] = &RepoConfig{}
+		}
+
+		repo := m.config.Repositories[repoPath]
+		if repo.Ports == nil {
+			repo.Ports = make(map[string]int)
+		}
+
+		repo.Ports[worktreePath] = port
+		return port, m.save()
+	}
+
+	return 0, fmt.Errorf("no free port block left between %d and %d", PortRangeStart, PortRangeEnd)
+}
+
+// ReleasePortBlock frees the port block of a removed worktree
+func (m *Manager) ReleasePortBlock(repoPath, worktreePath string) error {
+	repo, ok := m.config.Repositories[repoPath]
+	if !ok || repo.Ports == nil {
+		return nil
+	}
+	if _, ok := repo.Ports[worktreePath]; !ok {
+		return nil
+	}
+	delete(repo.Ports, worktreePath)
+	return m.save()
+}
+
 // GetCommitPrompt returns the custom commit message prompt
 // Returns the custom prompt if set, otherwise returns the default prompt
 func (m *Manager) GetCommitPrompt() string {
@@ -601,3 +725,238 @@ func (m *Manager) SetPRDefaultState(repoPath, state string) error {
 	m.config.Repositories[repoPath].PRDefaultState = state
 	return m.save()
 }
+
+// GetUpdateMode returns how worktrees are updated from the base branch
+// Returns "merge" or "rebase", defaults to "merge" if not set
+func (m *Manager) GetUpdateMode(repoPath string) string {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		if repo.UpdateMode == "rebase" {
+			return "rebase"
+		}
+	}
+	return "merge"
+}
+
+// SetUpdateMode sets how worktrees are updated from the base branch ("merge" or "rebase")
+func (m *Manager) SetUpdateMode(repoPath, mode string) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].UpdateMode = mode
+	return m.save()
+}
+
+// GetAutoStash returns whether uncommitted changes are stashed before updating from the base
+// branch and re-applied afterwards
+func (m *Manager) GetAutoStash(repoPath string) bool {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		return repo.AutoStash
+	}
+	return false
+}
+
+// SetAutoStash sets whether uncommitted changes are stashed around updates from the base branch
+func (m *Manager) SetAutoStash(repoPath string, enabled bool) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].AutoStash = enabled
+	return m.save()
+}
+
+// GetCommitSignoff returns whether commits made by jean get a Signed-off-by trailer
+func (m *Manager) GetCommitSignoff(repoPath string) bool {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		return repo.CommitSignoff
+	}
+	return false
+}
+
+// SetCommitSignoff sets whether commits made by jean get a Signed-off-by trailer
+func (m *Manager) SetCommitSignoff(repoPath string, enabled bool) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].CommitSignoff = enabled
+	return m.save()
+}
+
+// GetRemotes returns the remote branches are pushed to and the remote of the base branch
+// Returns "" for the ones that are not set (origin)
+func (m *Manager) GetRemotes(repoPath string) (push, base string) {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		return repo.PushRemote, repo.BaseRemote
+	}
+	return "", ""
+}
+
+// SetRemotes sets the remote branches are pushed to and the remote of the base branch
+// ("" for origin), e.g. "fork" and "upstream" in a fork-based workflow
+func (m *Manager) SetRemotes(repoPath, push, base string) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].PushRemote = push
+	m.config.Repositories[repoPath].BaseRemote = base
+	return m.save()
+}
+
+// GetInitSubmodules returns whether new worktrees get their submodules initialized and
+// their LFS files pulled
+func (m *Manager) GetInitSubmodules(repoPath string) bool {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		return repo.InitSubmodules
+	}
+	return false
+}
+
+// SetInitSubmodules sets whether new worktrees get their submodules initialized and their
+// LFS files pulled
+func (m *Manager) SetInitSubmodules(repoPath string, enabled bool) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].InitSubmodules = enabled
+	return m.save()
+}
+
+// GetCommitGPGSign returns whether commits made by jean are GPG-signed
+// Returns "always", "never" or "" to follow git's commit.gpgsign setting
+func (m *Manager) GetCommitGPGSign(repoPath string) string {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		if repo.CommitGPGSign == "always" || repo.CommitGPGSign == "never" {
+			return repo.CommitGPGSign
+		}
+	}
+	return ""
+}
+
+// SetCommitGPGSign sets whether commits made by jean are GPG-signed ("always", "never" or "" for git's setting)
+func (m *Manager) SetCommitGPGSign(repoPath, mode string) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].CommitGPGSign = mode
+	return m.save()
+}
+
+// GetWorktreeLocation returns the root directory and path template of new worktrees: the
+// repository's setting, else the global default, else "" for jean's built-in default
+func (m *Manager) GetWorktreeLocation(repoPath string) (root, template string) {
+	root, template = m.config.WorktreeRoot, m.config.WorktreeTemplate
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		if repo.WorktreeRoot != "" {
+			root = repo.WorktreeRoot
+		}
+		if repo.WorktreeTemplate != "" {
+			template = repo.WorktreeTemplate
+		}
+	}
+	return root, template
+}
+
+// SetWorktreeLocation sets the root directory and path template of new worktrees of a repository
+// ("" = global default). Existing worktrees stay where they are.
+func (m *Manager) SetWorktreeLocation(repoPath, root, template string) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].WorktreeRoot = root
+	m.config.Repositories[repoPath].WorktreeTemplate = template
+	return m.save()
+}
+
+// IsManagedWorktree checks if the worktree at worktreePath was created by jean
+func (m *Manager) IsManagedWorktree(repoPath, worktreePath string) bool {
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		_, managed := repo.Worktrees[worktreePath]
+		return managed
+	}
+	return false
+}
+
+// AddManagedWorktree records a worktree created by jean, or adopted when it was added by hand
+func (m *Manager) AddManagedWorktree(repoPath, worktreePath string, adopted bool) error {
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	repo := m.config.Repositories[repoPath]
+	if repo.Worktrees == nil {
+		repo.Worktrees = make(map[string]ManagedWorktree)
+	}
+	if _, ok := repo.Worktrees[worktreePath]; ok {
+		return nil
+	}
+	repo.Worktrees[worktreePath] = ManagedWorktree{CreatedAt: time.Now().Format(time.RFC3339), Adopted: adopted}
+	return m.save()
+}
+
+// RemoveManagedWorktree forgets a removed worktree
+func (m *Manager) RemoveManagedWorktree(repoPath, worktreePath string) error {
+	repo, ok := m.config.Repositories[repoPath]
+	if !ok || repo.Worktrees == nil {
+		return nil
+	}
+	if _, ok := repo.Worktrees[worktreePath]; !ok {
+		return nil
+	}
+	delete(repo.Worktrees, worktreePath)
+	return m.save()
+}
+
+// MoveWorktree moves the record and port block of a worktree whose directory was moved
+func (m *Manager) MoveWorktree(repoPath, oldPath, newPath string) error {
+	repo, ok := m.config.Repositories[repoPath]
+	if !ok {
+		return nil
+	}
+	if managed, ok := repo.Worktrees[oldPath]; ok {
+		delete(repo.Worktrees, oldPath)
+		repo.Worktrees[newPath] = managed
+	}
+	if port, ok := repo.Ports[oldPath]; ok {
+		delete(repo.Ports, oldPath)
+		repo.Ports[newPath] = port
+	}
+	return m.save()
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAllocatePortBlock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	// Each step allocates (or releases, with a negative want) the block of a worktree
+	steps := []struct {
+		repo, worktree string
+		want           int
+	}{
+		{"/src/app", "/src/app/.workspaces/a", PortRangeStart},
+		{"/src/app", "/src/app/.workspaces/b", PortRangeStart + PortBlockSize},
+		{"/src/app", "/src/app/.workspaces/a", PortRangeStart},                   // stable for the worktree
+		{"/src/api", "/src/api/.workspaces/a", PortRangeStart + 2*PortBlockSize}, // unique across repositories
+		{"/src/app", "/src/app/.workspaces/a", -1},                               // released
+		{"/src/api", "/src/api/.workspaces/c", PortRangeStart},                   // the freed block is reused first
+		{"/src/app", "/src/app/.workspaces/a", PortRangeStart + 3*PortBlockSize},
+	}
+	for i, step := range steps {
+		if step.want < 0 {
+			if err := m.ReleasePortBlock(step.repo, step.worktree); err != nil {
+				t.Fatalf("step %d: ReleasePortBlock: %v", i, err)
+			}
+			if port := m.GetPortBlock(step.repo, step.worktree); port != 0 {
+				t.Errorf("step %d: expected the block of %s to be released, got %d", i, step.worktree, port)
+			}
+			continue
+		}
+		port, err := m.AllocatePortBlock(step.repo, step.worktree)
+		if err != nil {
+			t.Fatalf("step %d: AllocatePortBlock: %v", i, err)
+		}
+		if port != step.want {
+			t.Errorf("step %d: expected %s to get port %d, got %d", i, step.worktree, step.want, port)
+		}
+	}
+
+	// Blocks survive a reload
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if port := reloaded.GetPortBlock("/src/api", "/src/api/.workspaces/c"); port != PortRangeStart {
+		t.Errorf("expected the saved block %d, got %d", PortRangeStart, port)
+	}
+}
+
+func TestAllocatePortBlock_RangeExhausted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	// Fill every block but the last one without saving each time
+	blocks := (PortRangeEnd - PortRangeStart) / PortBlockSize
+	ports := make(map[string]int, blocks)
+	for i := 0; i < blocks-1; i++ {
+		ports[fmt.Sprintf("/src/app/.workspaces/w%d", i)] = PortRangeStart + i*PortBlockSize
+	}
+	m.config.Repositories["/src/app"] = &RepoConfig{Ports: ports}
+
+	last, err := m.AllocatePortBlock("/src/app", "/src/app/.workspaces/last")
+	if err != nil || last != PortRangeEnd-PortBlockSize {
+		t.Fatalf("expected the last block %d, got %d (%v)", PortRangeEnd-PortBlockSize, last, err)
+	}
+	if port, err := m.AllocatePortBlock("/src/app", "/src/app/.workspaces/one-more"); err == nil {
+		t.Fatalf("expected the range to be exhausted, got port %d", port)
+	}
+
+	// Releasing a block makes room again
+	if err := m.ReleasePortBlock("/src/app", "/src/app/.workspaces/w7"); err != nil {
+		t.Fatalf("ReleasePortBlock: %v", err)
+	}
+	if port, err := m.AllocatePortBlock("/src/app", "/src/app/.workspaces/one-more"); err != nil || port != PortRangeStart+7*PortBlockSize {
+		t.Errorf("expected the released block %d, got %d (%v)", PortRangeStart+7*PortBlockSize, port, err)
+	}
+}
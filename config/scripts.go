@@ -2,17 +2,111 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
+// Lifecycle hook names supported in the "hooks" section of jean.json
+const (
+	HookPreCreate  = "pre_create"  // Before the worktree is created (non-zero exit aborts)
+	HookPostCreate = "post_create" // After the worktree is created and the setup script ran
+	HookPreDelete  = "pre_delete"  // Before the worktree is removed (non-zero exit aborts)
+	HookTeardown   = "teardown"    // Alias for pre_delete
+	HookPostSwitch = "post_switch" // Before handing off to tmux (non-zero exit cancels the switch)
+	HookPrePush    = "pre_push"    // Before pushing a branch (non-zero exit aborts)
+	HookPostMerge  = "post_merge"  // After a local merge or PR merge succeeded
+)
+
+// knownHooks lists the hook names accepted in jean.json
+var knownHooks = []string{HookPreCreate, HookPostCreate, HookPreDelete, HookTeardown, HookPostSwitch, HookPrePush, HookPostMerge}
+
+// DefaultProtectedBranches are the branches protected when jean.json has no "protected" list
+var DefaultProtectedBranches = []string{"main", "master", "develop", "development", "staging", "production"}
+
 // ScriptConfig represents the jean.json configuration file
 type ScriptConfig struct {
-	Scripts map[string]string `json:"scripts"`
+	Scripts   map[string]Script   `json:"scripts"`
+	Hooks     map[string]string   `json:"hooks,omitempty"`
+	Copy      []string            `json:"copy,omitempty"`      // Glob patterns copied from the repo root into new worktrees
+	Symlink   []string            `json:"symlink,omitempty"`   // Glob patterns symlinked from the repo root into new worktrees
+	Protected []string            `json:"protected,omitempty"` // Branch glob patterns that jean never deletes, renames or merges away
+	Sparse    map[string][]string `json:"sparse,omitempty"`    // Sparse-checkout profiles: name -> directories checked out (cone mode)
+}
+
+// Script is an entry of the "scripts" section of jean.json.
+// It is either a plain command string or an object with per-script options:
+//
+//	"dev": {"command": "npm run dev", "cwd": "web", "env": {"PORT": "3000"}, "description": "Dev server"}
+type Script struct {
+	Command     string
+	Description string            // Shown in the script picker
+	Cwd         string            // Working directory, relative to the worktree
+	Env         map[string]string // Extra environment variables
+	Timeout     time.Duration     // Only enforced for background runs (0 = no limit)
+	Interactive *bool             // Run in a tmux window (default) or in the background
+}
+
+// IsInteractive returns true if the script runs in its own tmux window (the default)
+// rather than in the background
+func (s Script) IsInteractive() bool {
+	return s.Interactive == nil || *s.Interactive
+}
+
+// Dir returns the directory the script runs in, resolving cwd against the worktree path
+func (s Script) Dir(worktreePath string) string {
+	if s.Cwd == "" {
+		return worktreePath
+	}
+	if filepath.IsAbs(s.Cwd) {
+		return s.Cwd
+	}
+	return filepath.Join(worktreePath, s.Cwd)
+}
+
+// Environ returns the script's env as KEY=value pairs, sorted by key
+func (s Script) Environ() []string {
+	keys := make([]string, 0, len(s.Env))
+	for key := range s.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, fmt.Sprintf("%s=%s", key, s.Env[key]))
+	}
+	return env
+}
+
+// UnmarshalJSON accepts both the plain string and the object form of a script
+func (s *Script) UnmarshalJSON(data []byte) error {
+	script, problems := parseScript("script", data)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	*s = script
+	return nil
+}
+
+// ValidationError lists the problems found in jean.json (unknown keys, bad types, ...)
+// It comes with a usable *ScriptConfig holding the valid entries, so callers report it as a
+// warning instead of failing.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
 }
 
 // LoadScripts loads the jean.json file from a repository path
-// Returns an empty ScriptConfig if the file doesn't exist
+// Returns an empty ScriptConfig if the file doesn't exist. Unknown keys and values of the
+// wrong type are dropped and listed in a *ValidationError returned alongside the config.
 func LoadScripts(repoPath string) (*ScriptConfig, error) {
 	configPath := filepath.Join(repoPath, "jean.json")
 
@@ -21,22 +115,352 @@ func LoadScripts(repoPath string) (*ScriptConfig, error) {
 		// If file doesn't exist, return empty config (not an error)
 		if os.IsNotExist(err) {
 			return &ScriptConfig{
-				Scripts: make(map[string]string),
+				Scripts: make(map[string]Script),
 			}, nil
 		}
 		return nil, err
 	}
 
-	var config ScriptConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	return ParseScripts(data)
+}
+
+// ParseScripts parses and validates the contents of a jean.json file
+// Invalid entries are skipped, the config keeps every valid one (a bad hook doesn't drop the
+// scripts or the protected branches) and the problems are returned as a *ValidationError.
+// Only malformed JSON returns a nil config.
+func ParseScripts(data []byte) (*ScriptConfig, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, err
 	}
 
-	if config.Scripts == nil {
-		config.Scripts = make(map[string]string)
+	config := &ScriptConfig{
+		Scripts: make(map[string]Script),
+	}
+	var problems []string
+
+	for _, key := range sortedKeys(raw) {
+		switch key {
+		case "$schema":
+			// Allowed so editors can validate the file
+
+		case "scripts":
+			var scripts map[string]json.RawMessage
+			if err := json.Unmarshal(raw[key], &scripts); err != nil {
+				problems = append(problems, fmt.Sprintf("scripts: expected an object, got %s", jsonType(raw[key])))
+				continue
+			}
+			for _, name := range sortedKeys(scripts) {
+				script, scriptProblems := parseScript("scripts."+name, scripts[name])
+				problems = append(problems, scriptProblems...)
+				if len(scriptProblems) == 0 {
+					config.Scripts[name] = script
+				}
+			}
+
+		case "hooks":
+			var hooks map[string]json.RawMessage
+			if err := json.Unmarshal(raw[key], &hooks); err != nil {
+				problems = append(problems, fmt.Sprintf("hooks: expected an object, got %s", jsonType(raw[key])))
+				continue
+			}
+			config.Hooks = make(map[string]string)
+			for _, name := range sortedKeys(hooks) {
+				if !isKnownHook(name) {
+					problems = append(problems, fmt.Sprintf("hooks.%s: unknown hook (expected one of %s)", name, strings.Join(knownHooks, ", ")))
+					continue
+				}
+				var command string
+				if err := json.Unmarshal(hooks[name], &command); err != nil {
+					problems = append(problems, fmt.Sprintf("hooks.%s: expected a string, got %s", name, jsonType(hooks[name])))
+					continue
+				}
+				config.Hooks[name] = command
+			}
+
+		case "copy":
+			var patternProblems []string
+			config.Copy, patternProblems = parsePatterns(key, raw[key])
+			problems = append(problems, patternProblems...)
+
+		case "symlink":
+			var patternProblems []string
+			config.Symlink, patternProblems = parsePatterns(key, raw[key])
+			problems = append(problems, patternProblems...)
+
+		case "protected":
+			var patternProblems []string
+			config.Protected, patternProblems = parseBranchPatterns(key, raw[key])
+			problems = append(problems, patternProblems...)
+
+		case "sparse":
+			var profiles map[string]json.RawMessage
+			if err := json.Unmarshal(raw[key], &profiles); err != nil {
+				problems = append(problems, fmt.Sprintf("sparse: expected an object, got %s", jsonType(raw[key])))
+				continue
+			}
+			config.Sparse = make(map[string][]string)
+			for _, name := range sortedKeys(profiles) {
+				dirs, dirProblems := parseSparseDirs("sparse."+name, profiles[name])
+				problems = append(problems, dirProblems...)
+				if len(dirProblems) == 0 {
+					config.Sparse[name] = dirs
+				}
+			}
+
+		default:
+			problems = append(problems, fmt.Sprintf("%s: unknown key (expected scripts, hooks, copy, symlink, protected or sparse)", key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return config, &ValidationError{Problems: problems}
+	}
+
+	return config, nil
+}
+
+// parseScript parses a single script entry, collecting problems prefixed with path
+func parseScript(path string, data json.RawMessage) (Script, []string) {
+	var script Script
+
+	// Plain string form: "dev": "npm run dev"
+	if err := json.Unmarshal(data, &script.Command); err == nil {
+		if strings.TrimSpace(script.Command) == "" {
+			return script, []string{fmt.Sprintf("%s: command is empty", path)}
+		}
+		return script, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return script, []string{fmt.Sprintf("%s: expected a string or an object, got %s", path, jsonType(data))}
+	}
+
+	var problems []string
+	for _, key := range sortedKeys(fields) {
+		value := fields[key]
+		var err error
+		switch key {
+		case "command":
+			err = json.Unmarshal(value, &script.Command)
+		case "description":
+			err = json.Unmarshal(value, &script.Description)
+		case "cwd":
+			err = json.Unmarshal(value, &script.Cwd)
+		case "env":
+			var env map[string]json.RawMessage
+			if err = json.Unmarshal(value, &env); err != nil {
+				break
+			}
+			script.Env = make(map[string]string, len(env))
+			for _, name := range sortedKeys(env) {
+				var envValue string
+				if err := json.Unmarshal(env[name], &envValue); err != nil {
+					problems = append(problems, fmt.Sprintf("%s.env.%s: expected a string, got %s", path, name, jsonType(env[name])))
+					continue
+				}
+				script.Env[name] = envValue
+			}
+		case "interactive":
+			var interactive bool
+			if err = json.Unmarshal(value, &interactive); err == nil {
+				script.Interactive = &interactive
+			}
+		case "timeout":
+			script.Timeout, err = parseTimeout(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s.timeout: %s", path, err.Error()))
+			}
+			continue
+		default:
+			problems = append(problems, fmt.Sprintf("%s: unknown key %q (expected command, description, cwd, env, timeout or interactive)", path, key))
+			continue
+		}
+
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s: expected %s, got %s", path, key, expectedType(key), jsonType(value)))
+		}
+	}
+
+	if _, ok := fields["command"]; !ok {
+		problems = append(problems, fmt.Sprintf("%s: missing \"command\"", path))
+	} else if strings.TrimSpace(script.Command) == "" && len(problems) == 0 {
+		problems = append(problems, fmt.Sprintf("%s: command is empty", path))
+	}
+
+	return script, problems
+}
+
+// parsePatterns parses a list of glob patterns relative to the repository root
+func parsePatterns(path string, data json.RawMessage) ([]string, []string) {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, []string{fmt.Sprintf("%s: expected an array of glob patterns, got %s", path, jsonType(data))}
+	}
+
+	var patterns []string
+	var problems []string
+	for i, value := range values {
+		var pattern string
+		if err := json.Unmarshal(value, &pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("%s[%d]: expected a string, got %s", path, i, jsonType(value)))
+			continue
+		}
+		if strings.TrimSpace(pattern) == "" {
+			problems = append(problems, fmt.Sprintf("%s[%d]: pattern is empty", path, i))
+			continue
+		}
+		if filepath.IsAbs(pattern) || pattern == ".." || strings.HasPrefix(filepath.Clean(pattern), ".."+string(filepath.Separator)) {
+			problems = append(problems, fmt.Sprintf("%s[%d]: %q must be relative to the repository root", path, i, pattern))
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("%s[%d]: invalid glob pattern %q", path, i, pattern))
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, problems
+}
+
+// parseSparseDirs parses the directories of a sparse-checkout profile. Cone mode only takes
+// directories relative to the repository root, so glob patterns are rejected.
+func parseSparseDirs(path string, data json.RawMessage) ([]string, []string) {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, []string{fmt.Sprintf("%s: expected an array of directories, got %s", path, jsonType(data))}
+	}
+	if len(values) == 0 {
+		return nil, []string{fmt.Sprintf("%s: no directories listed", path)}
 	}
 
-	return &config, nil
+	var dirs []string
+	var problems []string
+	for i, value := range values {
+		var dir string
+		if err := json.Unmarshal(value, &dir); err != nil {
+			problems = append(problems, fmt.Sprintf("%s[%d]: expected a string, got %s", path, i, jsonType(value)))
+			continue
+		}
+		dir = strings.Trim(strings.TrimSpace(dir), "/")
+		switch {
+		case dir == "":
+			problems = append(problems, fmt.Sprintf("%s[%d]: directory is empty", path, i))
+		case filepath.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, "../"):
+			problems = append(problems, fmt.Sprintf("%s[%d]: %q must be relative to the repository root", path, i, dir))
+		case strings.ContainsAny(dir, "*?[!"):
+			problems = append(problems, fmt.Sprintf("%s[%d]: %q must be a directory, not a pattern", path, i, dir))
+		default:
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, problems
+}
+
+// parseBranchPatterns parses a list of branch glob patterns such as "release/*"
+func parseBranchPatterns(key string, data json.RawMessage) ([]string, []string) {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, []string{fmt.Sprintf("%s: expected an array of branch patterns, got %s", key, jsonType(data))}
+	}
+
+	// An empty list is kept (not nil) so it can turn off the default protection
+	patterns := []string{}
+	var problems []string
+	for i, value := range values {
+		var pattern string
+		if err := json.Unmarshal(value, &pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("%s[%d]: expected a string, got %s", key, i, jsonType(value)))
+			continue
+		}
+		if strings.TrimSpace(pattern) == "" {
+			problems = append(problems, fmt.Sprintf("%s[%d]: pattern is empty", key, i))
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("%s[%d]: invalid glob pattern %q", key, i, pattern))
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, problems
+}
+
+// parseTimeout accepts a duration string ("90s", "5m") or a number of seconds
+func parseTimeout(data json.RawMessage) (time.Duration, error) {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		if seconds <= 0 {
+			return 0, fmt.Errorf("must be greater than zero")
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return 0, fmt.Errorf("expected a duration like \"90s\" or a number of seconds, got %s", jsonType(data))
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. \"90s\" or \"5m\")", value)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("must be greater than zero")
+	}
+	return timeout, nil
+}
+
+// expectedType describes the JSON type expected for a script option
+func expectedType(key string) string {
+	switch key {
+	case "env":
+		return "an object"
+	case "interactive":
+		return "a boolean"
+	default:
+		return "a string"
+	}
+}
+
+// jsonType describes the type of a raw JSON value for validation messages
+func jsonType(data json.RawMessage) string {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return "nothing"
+	}
+	switch trimmed[0] {
+	case '"':
+		return "a string"
+	case '{':
+		return "an object"
+	case '[':
+		return "an array"
+	case 't', 'f':
+		return "a boolean"
+	case 'n':
+		return "null"
+	default:
+		return "a number"
+	}
+}
+
+func isKnownHook(name string) bool {
+	for _, hook := range knownHooks {
+		if hook == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // GetScript returns the command for a named script
@@ -44,7 +468,118 @@ func (s *ScriptConfig) GetScript(name string) string {
 	if s == nil || s.Scripts == nil {
 		return ""
 	}
-	return s.Scripts[name]
+	return s.Scripts[name].Command
+}
+
+// LookupScript returns a named script with its options
+func (s *ScriptConfig) LookupScript(name string) (Script, bool) {
+	if s == nil || s.Scripts == nil {
+		return Script{}, false
+	}
+	script, ok := s.Scripts[name]
+	return script, ok
+}
+
+// GetHook returns the command for a lifecycle hook
+// "teardown" is used for pre_delete when no pre_delete hook is configured
+func (s *ScriptConfig) GetHook(name string) string {
+	if s == nil || s.Hooks == nil {
+		return ""
+	}
+	if name == HookPreDelete && s.Hooks[HookPreDelete] == "" {
+		return s.Hooks[HookTeardown]
+	}
+	return s.Hooks[name]
+}
+
+// ProtectedBranches returns the protected branch patterns, falling back to DefaultProtectedBranches
+func (s *ScriptConfig) ProtectedBranches() []string {
+	if s == nil || s.Protected == nil {
+		return DefaultProtectedBranches
+	}
+	return s.Protected
+}
+
+// IsProtectedBranch returns true if the branch matches one of the protected patterns.
+// Patterns use path.Match syntax, so "release/*" matches "release/1.2" but not "release/1.2/hotfix".
+// The branch is a local branch name, git.Manager.IsProtectedBranch strips the remote of
+// remote tracking branches.
+func (s *ScriptConfig) IsProtectedBranch(branch string) bool {
+	for _, pattern := range s.ProtectedBranches() {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// SparseProfile returns the directories of a sparse-checkout profile
+func (s *ScriptConfig) SparseProfile(name string) ([]string, bool) {
+	if s == nil || s.Sparse == nil {
+		return nil, false
+	}
+	dirs, ok := s.Sparse[name]
+	return dirs, ok
+}
+
+// SparseProfileNames returns a sorted list of sparse-checkout profile names
+func (s *ScriptConfig) SparseProfileNames() []string {
+	if s == nil || s.Sparse == nil {
+		return []string{}
+	}
+
+	names := make([]string, 0, len(s.Sparse))
+	for name := range s.Sparse {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SparseProfilesOf names the profiles a sparse checkout was made of: the profiles whose
+// directories are all checked out, if together they are exactly the checked out directories.
+// Returns nil for a checkout that was widened by hand (or with profiles that changed since).
+func (s *ScriptConfig) SparseProfilesOf(dirs []string) []string {
+	checkedOut := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		checkedOut[dir] = true
+	}
+
+	var names []string
+	covered := make(map[string]bool, len(dirs))
+	for _, name := range s.SparseProfileNames() {
+		if s.SparseIncludes(dirs, name) {
+			names = append(names, name)
+			for _, dir := range s.Sparse[name] {
+				covered[dir] = true
+			}
+		}
+	}
+	if len(names) == 0 || len(covered) != len(checkedOut) {
+		return nil
+	}
+	return names
+}
+
+// SparseIncludes checks if all directories of a profile are among the checked out directories
+func (s *ScriptConfig) SparseIncludes(dirs []string, profile string) bool {
+	profileDirs, ok := s.SparseProfile(profile)
+	if !ok {
+		return false
+	}
+	for _, want := range profileDirs {
+		found := false
+		for _, dir := range dirs {
+			if dir == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // GetScriptNames returns a sorted list of script names
@@ -57,6 +592,7 @@ func (s *ScriptConfig) GetScriptNames() []string {
 	for name := range s.Scripts {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
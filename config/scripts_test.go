@@ -0,0 +1,294 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadScripts_Hooks(t *testing.T) {
+	repo := t.TempDir()
+	data := `{"scripts": {"setup": "npm install"}, "hooks": {"pre_push": "make lint", "teardown": "docker compose down"}}`
+	if err := os.WriteFile(filepath.Join(repo, "jean.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadScripts(repo)
+	if err != nil {
+		t.Fatalf("LoadScripts: %v", err)
+	}
+	if got := config.GetHook(HookPrePush); got != "make lint" {
+		t.Errorf("expected the pre_push hook, got %q", got)
+	}
+	if got := config.GetHook(HookPostMerge); got != "" {
+		t.Errorf("expected no post_merge hook, got %q", got)
+	}
+}
+
+func TestGetHook_TeardownAlias(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks map[string]string
+		want  string
+	}{
+		{name: "teardown only", hooks: map[string]string{HookTeardown: "down"}, want: "down"},
+		{name: "pre_delete wins", hooks: map[string]string{HookTeardown: "down", HookPreDelete: "backup"}, want: "backup"},
+		{name: "no hooks", hooks: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ScriptConfig{Hooks: tt.hooks}
+			if got := config.GetHook(HookPreDelete); got != tt.want {
+				t.Errorf("expected pre_delete %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadScripts_MissingFile(t *testing.T) {
+	config, err := LoadScripts(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadScripts: %v", err)
+	}
+	if config.GetHook(HookPreCreate) != "" || config.HasScripts() {
+		t.Errorf("expected an empty config, got %+v", config)
+	}
+}
+
+func TestParseScripts_Valid(t *testing.T) {
+	data := `{
+		"scripts": {
+			"setup": "npm install",
+			"dev": {"command": "npm run dev", "cwd": "web", "env": {"PORT": "3000"}, "timeout": "90s", "interactive": false}
+		},
+		"hooks": {"pre_push": "make lint"},
+		"copy": [".env", "config/*.local.json"],
+		"symlink": ["node_modules"],
+		"protected": [],
+		"sparse": {"web": ["web", "shared/ui/"]}
+	}`
+
+	config, err := ParseScripts([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseScripts: %v", err)
+	}
+	if got := config.GetScript("setup"); got != "npm install" {
+		t.Errorf("expected the plain setup command, got %q", got)
+	}
+	dev, ok := config.LookupScript("dev")
+	if !ok || dev.Command != "npm run dev" || dev.Cwd != "web" || dev.Timeout != 90*time.Second || dev.IsInteractive() {
+		t.Errorf("expected the options of dev to be parsed, got %+v", dev)
+	}
+	if !reflect.DeepEqual(dev.Environ(), []string{"PORT=3000"}) {
+		t.Errorf("expected PORT=3000, got %v", dev.Environ())
+	}
+	if config.GetHook(HookPrePush) != "make lint" {
+		t.Errorf("expected the pre_push hook, got %q", config.GetHook(HookPrePush))
+	}
+	if !reflect.DeepEqual(config.Copy, []string{".env", "config/*.local.json"}) || !reflect.DeepEqual(config.Symlink, []string{"node_modules"}) {
+		t.Errorf("expected the copy and symlink patterns, got %q and %q", config.Copy, config.Symlink)
+	}
+	if config.Protected == nil || len(config.Protected) != 0 {
+		t.Errorf("expected an empty (not nil) protected list, got %#v", config.Protected)
+	}
+	if dirs, ok := config.SparseProfile("web"); !ok || !reflect.DeepEqual(dirs, []string{"web", "shared/ui"}) {
+		t.Errorf("expected the web sparse profile, got %q", dirs)
+	}
+}
+
+func TestParseScripts_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		problems []string
+	}{
+		{
+			name:     "unknown top-level key",
+			data:     `{"script": {}}`,
+			problems: []string{"script: unknown key (expected scripts, hooks, copy, symlink, protected or sparse)"},
+		},
+		{
+			name:     "scripts not an object",
+			data:     `{"scripts": ["npm install"]}`,
+			problems: []string{"scripts: expected an object, got an array"},
+		},
+		{
+			name:     "script of the wrong type",
+			data:     `{"scripts": {"setup": 42}}`,
+			problems: []string{"scripts.setup: expected a string or an object, got a number"},
+		},
+		{
+			name:     "empty command",
+			data:     `{"scripts": {"setup": "  "}}`,
+			problems: []string{"scripts.setup: command is empty"},
+		},
+		{
+			name:     "missing command",
+			data:     `{"scripts": {"dev": {"cwd": "web"}}}`,
+			problems: []string{`scripts.dev: missing "command"`},
+		},
+		{
+			name: "unknown script option and bad option types",
+			data: `{"scripts": {"dev": {"command": "npm run dev", "dir": "web", "env": {"PORT": 3000}, "interactive": "no", "cwd": 1}}}`,
+			problems: []string{
+				"scripts.dev.cwd: expected a string, got a number",
+				`scripts.dev: unknown key "dir" (expected command, description, cwd, env, timeout or interactive)`,
+				"scripts.dev.env.PORT: expected a string, got a number",
+				"scripts.dev.interactive: expected a boolean, got a string",
+			},
+		},
+		{
+			name: "bad timeouts",
+			data: `{"scripts": {"a": {"command": "x", "timeout": "soon"}, "b": {"command": "x", "timeout": 0}, "c": {"command": "x", "timeout": true}}}`,
+			problems: []string{
+				`scripts.a.timeout: invalid duration "soon" (use e.g. "90s" or "5m")`,
+				"scripts.b.timeout: must be greater than zero",
+				`scripts.c.timeout: expected a duration like "90s" or a number of seconds, got a boolean`,
+			},
+		},
+		{
+			name:     "hooks not an object",
+			data:     `{"hooks": "make lint"}`,
+			problems: []string{"hooks: expected an object, got a string"},
+		},
+		{
+			name: "unknown hook and hook of the wrong type",
+			data: `{"hooks": {"post_push": "make", "pre_push": ["make", "lint"]}}`,
+			problems: []string{
+				"hooks.post_push: unknown hook (expected one of pre_create, post_create, pre_delete, teardown, post_switch, pre_push, post_merge)",
+				"hooks.pre_push: expected a string, got an array",
+			},
+		},
+		{
+			name: "bad copy and symlink patterns",
+			data: `{"copy": ".env", "symlink": ["", "/etc/hosts", "../secrets", "[", 1]}`,
+			problems: []string{
+				"copy: expected an array of glob patterns, got a string",
+				"symlink[0]: pattern is empty",
+				`symlink[1]: "/etc/hosts" must be relative to the repository root`,
+				`symlink[2]: "../secrets" must be relative to the repository root`,
+				`symlink[3]: invalid glob pattern "["`,
+				"symlink[4]: expected a string, got a number",
+			},
+		},
+		{
+			name: "bad protected patterns",
+			data: `{"protected": ["main", "", "release/["]}`,
+			problems: []string{
+				"protected[1]: pattern is empty",
+				`protected[2]: invalid glob pattern "release/["`,
+			},
+		},
+		{
+			name: "bad sparse profiles",
+			data: `{"sparse": {"api": [], "web": ["web/*", "../lib", " /"]}}`,
+			problems: []string{
+				"sparse.api: no directories listed",
+				`sparse.web[0]: "web/*" must be a directory, not a pattern`,
+				`sparse.web[1]: "../lib" must be relative to the repository root`,
+				"sparse.web[2]: directory is empty",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseScripts([]byte(tt.data))
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a *ValidationError, got %v (config %+v)", err, config)
+			}
+			if config == nil {
+				t.Fatal("expected a config with the valid entries")
+			}
+			if !reflect.DeepEqual(validationErr.Problems, tt.problems) {
+				t.Errorf("expected problems\n  %q\ngot\n  %q", tt.problems, validationErr.Problems)
+			}
+		})
+	}
+}
+
+func TestParseScripts_KeepsValidSections(t *testing.T) {
+	data := `{
+		"scripts": {"setup": "npm install", "dev": {"command": "npm run dev", "timeout": "soon"}},
+		"hooks": {"pre_push": "make lint", "post_push": "make"},
+		"protected": ["trunk", ""],
+		"extra": true
+	}`
+
+	config, err := ParseScripts([]byte(data))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 4 {
+		t.Fatalf("expected a *ValidationError with 4 problems, got %v", err)
+	}
+	if config == nil {
+		t.Fatal("expected the valid entries to be returned with the problems")
+	}
+	if got := config.GetScript("setup"); got != "npm install" {
+		t.Errorf("expected the valid setup script, got %q", got)
+	}
+	if _, ok := config.LookupScript("dev"); ok {
+		t.Error("expected the invalid dev script to be dropped")
+	}
+	if got := config.GetHook(HookPrePush); got != "make lint" {
+		t.Errorf("expected the valid pre_push hook, got %q", got)
+	}
+	if !reflect.DeepEqual(config.ProtectedBranches(), []string{"trunk"}) {
+		t.Errorf("expected the configured protected branches, not the defaults, got %q", config.ProtectedBranches())
+	}
+}
+
+func TestLoadScripts_InvalidEntriesDontDropProtected(t *testing.T) {
+	repo := t.TempDir()
+	data := `{"hooks": {"pre_pushh": "make lint"}, "protected": ["trunk"]}`
+	if err := os.WriteFile(filepath.Join(repo, "jean.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadScripts(repo)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	if !config.IsProtectedBranch("trunk") || config.IsProtectedBranch("main") {
+		t.Errorf("expected only trunk to be protected, got %q", config.ProtectedBranches())
+	}
+}
+
+func TestParseScripts_MalformedJSON(t *testing.T) {
+	_, err := ParseScripts([]byte(`{"scripts": {`))
+	var validationErr *ValidationError
+	if err == nil || errors.As(err, &validationErr) {
+		t.Errorf("expected a JSON syntax error, got %v", err)
+	}
+}
+
+func TestIsProtectedBranch(t *testing.T) {
+	tests := []struct {
+		name      string
+		protected []string
+		branch    string
+		want      bool
+	}{
+		{name: "default list", protected: nil, branch: "main", want: true},
+		{name: "default list, feature branch", protected: nil, branch: "feature/login", want: false},
+		{name: "remote branches are not stripped here", protected: nil, branch: "origin/develop", want: false},
+		{name: "glob pattern", protected: []string{"release/*"}, branch: "release/1.2", want: true},
+		{name: "glob doesn't cross slashes", protected: []string{"release/*"}, branch: "release/1.2/hotfix", want: false},
+		{name: "configured list replaces the defaults", protected: []string{"trunk"}, branch: "main", want: false},
+		{name: "empty list protects nothing", protected: []string{}, branch: "main", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ScriptConfig{Protected: tt.protected}
+			if got := config.IsProtectedBranch(tt.branch); got != tt.want {
+				t.Errorf("IsProtectedBranch(%q) = %v, want %v", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
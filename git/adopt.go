@@ -0,0 +1,63 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Adopt makes a worktree that was added outside jean (git worktree add) a managed one, so it
+// can be updated, merged and renamed like the worktrees jean creates: it is recorded in the
+// config and gets a port block. With move, it is first moved to where jean would have created
+// it (see GetDefaultPath). Returns the path of the adopted worktree.
+func (m *Manager) Adopt(worktreePath string, move bool) (string, error) {
+	if m.configManager == nil {
+		return "", fmt.Errorf("cannot adopt worktree: no jean config")
+	}
+	wt := m.findWorktree(worktreePath)
+	if wt == nil {
+		return "", fmt.Errorf("'%s' is not a worktree of this repository", worktreePath)
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case wt.Path == repoRoot:
+		return "", fmt.Errorf("the main worktree cannot be adopted")
+	case wt.Prunable:
+		return "", fmt.Errorf("the directory of '%s' was deleted, prune it instead", wt.Name())
+	case m.isManaged(repoRoot, wt.Path):
+		return "", fmt.Errorf("'%s' is already managed by jean", wt.Name())
+	}
+
+	path := wt.Path
+	if move {
+		newPath, err := m.GetDefaultPath(wt.Name())
+		if err != nil {
+			return "", err
+		}
+		if newPath != path {
+			if wt.Locked {
+				return "", fmt.Errorf("%w, unlock it before moving it", ErrWorktreeLocked)
+			}
+			if _, err := os.Stat(newPath); err == nil {
+				return "", fmt.Errorf("cannot move worktree: %s already exists", newPath)
+			}
+			if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+				return "", fmt.Errorf("failed to create worktree directory: %w", err)
+			}
+			if err := m.MoveWorktree(path, newPath); err != nil {
+				return "", err
+			}
+			path = newPath
+		}
+	}
+
+	if err := m.configManager.AddManagedWorktree(repoRoot, absPath(path), true); err != nil {
+		return "", fmt.Errorf("failed to record worktree: %w", err)
+	}
+	m.AllocatePortBlock(path)
+	return path, nil
+}
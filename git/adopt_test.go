@@ -0,0 +1,47 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+)
+
+func TestAdopt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	repoRoot := t.TempDir()
+
+	runner := NewFakeRunner()
+	runner.On("worktree "+repoRoot+"\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /elsewhere/by-hand\nHEAD aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nbranch refs/heads/feature/login\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	m := NewManagerWithRunner(repoRoot, runner)
+	m.SetConfigManager(configManager)
+
+	if _, err := m.Adopt(repoRoot, false); err == nil {
+		t.Error("the main worktree should not be adoptable")
+	}
+
+	path, err := m.Adopt("/elsewhere/by-hand", true)
+	if err != nil {
+		t.Fatalf("Adopt: %v", err)
+	}
+	want := filepath.Join(repoRoot, DefaultWorktreeRoot, "feature-login")
+	if path != want {
+		t.Errorf("expected the worktree to be moved to %s, got %s", want, path)
+	}
+	if !runner.Ran("worktree", "move", "/elsewhere/by-hand", want) {
+		t.Error("expected git worktree move to be run")
+	}
+	if !configManager.IsManagedWorktree(repoRoot, want) {
+		t.Error("expected the adopted worktree to be recorded as managed")
+	}
+	if configManager.GetPortBlock(repoRoot, want) == 0 {
+		t.Error("expected the adopted worktree to get a port block")
+	}
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CommitOptions holds everything of a commit besides the subject
+type CommitOptions struct {
+	Body     string   // Paragraphs after the subject
+	Trailers []string // "Key: value" lines, e.g. "Co-authored-by: Name <email>" or "Refs: #123"
+	Signoff  bool     // Add a Signed-off-by trailer (--signoff)
+	GPGSign  string   // "always" (-S), "never" (--no-gpg-sign) or "" to follow git's commit.gpgsign
+}
+
+// trailerPattern matches "Key: value" and "Key #value" (e.g. "Refs #123") trailer lines
+var trailerPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)(:\s*|\s+#)(.+)$`)
+
+// ParseTrailers parses one trailer per line, e.g. "Co-authored-by: Name <email>".
+// "Refs #123" is accepted and normalized to "Refs: #123". Empty lines are ignored.
+func ParseTrailers(text string) ([]string, error) {
+	var trailers []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		match := trailerPattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("invalid trailer %q, expected \"Key: value\"", line)
+		}
+		value := strings.TrimSpace(match[3])
+		if !strings.HasPrefix(match[2], ":") {
+			value = "#" + value
+		}
+		trailers = append(trailers, fmt.Sprintf("%s: %s", match[1], value))
+	}
+	return trailers, nil
+}
+
+// args builds the git commit arguments; each -m becomes its own paragraph and the trailers
+// form the last one, where git expects them (--signoff appends to it)
+func (o CommitOptions) args(subject string) []string {
+	args := []string{"commit", "-m", subject}
+	if body := strings.TrimSpace(o.Body); body != "" {
+		args = append(args, "-m", body)
+	}
+	if len(o.Trailers) > 0 {
+		args = append(args, "-m", strings.Join(o.Trailers, "\n"))
+	}
+	if o.Signoff {
+		args = append(args, "--signoff")
+	}
+	return append(args, o.signingArgs()...)
+}
+
+// signingArgs returns the GPG flag of the GPGSign preference, none to follow git's config
+func (o CommitOptions) signingArgs() []string {
+	switch o.GPGSign {
+	case "always":
+		return []string{"-S"}
+	case "never":
+		return []string{"--no-gpg-sign"}
+	}
+	return nil
+}
+
+// CommitStaged commits the staged changes only, unlike CreateCommitWithOptions which stages everything first
+// Returns the hash of the new commit
+func (m *Manager) CommitStaged(worktreePath, subject string, opts CommitOptions) (string, error) {
+	if subject == "" {
+		return "", fmt.Errorf("commit subject cannot be empty")
+	}
+
+	if _, err := m.run(worktreePath, opts.args(subject)...); err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	// Resolve the new commit hash
+	res, err := m.run(worktreePath, "rev-parse", "HEAD")
+	if err != nil {
+		// Commit was successful but we couldn't get the hash
+		return "", nil
+	}
+	return res.Trimmed(), nil
+}
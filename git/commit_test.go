@@ -0,0 +1,49 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTrailers_Normalizes(t *testing.T) {
+	trailers, err := ParseTrailers("Co-authored-by: Jane Doe <jane@example.com>\n\n  Refs #123  \nSigned-off-by:Bob <bob@example.com>\n")
+	if err != nil {
+		t.Fatalf("ParseTrailers: %v", err)
+	}
+	want := []string{
+		"Co-authored-by: Jane Doe <jane@example.com>",
+		"Refs: #123",
+		"Signed-off-by: Bob <bob@example.com>",
+	}
+	if strings.Join(trailers, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, trailers)
+	}
+}
+
+func TestParseTrailers_Invalid(t *testing.T) {
+	if _, err := ParseTrailers("just some text"); err == nil {
+		t.Error("expected an error for a line without a key")
+	}
+}
+
+func TestCommitStaged_Options(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("abc123\n", "rev-parse", "HEAD")
+
+	m := NewManagerWithRunner("/repo", runner)
+	hash, err := m.CommitStaged("/repo", "feat: add x", CommitOptions{
+		Body:     "Explain why.",
+		Trailers: []string{"Refs: #1", "Co-authored-by: A <a@a>"},
+		Signoff:  true,
+		GPGSign:  "never",
+	})
+	if err != nil {
+		t.Fatalf("CommitStaged: %v", err)
+	}
+	if hash != "abc123" {
+		t.Errorf("expected hash abc123, got %q", hash)
+	}
+	if !runner.Ran("commit", "-m", "feat: add x", "-m", "Explain why.", "-m", "Refs: #1\nCo-authored-by: A <a@a>", "--signoff", "--no-gpg-sign") {
+		t.Errorf("unexpected commit command: %+v", runner.Calls())
+	}
+}
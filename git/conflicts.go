@@ -0,0 +1,186 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictFile is a path left unmerged by a merge or rebase
+type ConflictFile struct {
+	Path   string
+	Status string // Two-letter unmerged status from `git status --porcelain`, e.g. "UU"
+}
+
+// Description explains the conflict status, e.g. "both modified" or "deleted by them"
+func (f ConflictFile) Description() string {
+	switch f.Status {
+	case "DD":
+		return "both deleted"
+	case "AU":
+		return "added by us"
+	case "UD":
+		return "deleted by them"
+	case "UA":
+		return "added by them"
+	case "DU":
+		return "deleted by us"
+	case "AA":
+		return "both added"
+	default:
+		return "both modified"
+	}
+}
+
+// hasOurs returns true if "our" side of the conflict still has the file
+func (f ConflictFile) hasOurs() bool {
+	return f.Status != "DU" && f.Status != "DD"
+}
+
+// hasTheirs returns true if "their" side of the conflict still has the file
+func (f ConflictFile) hasTheirs() bool {
+	return f.Status != "UD" && f.Status != "DD"
+}
+
+// Conflicts returns the unmerged entries of `git status --porcelain`
+func (m *Manager) Conflicts(worktreePath string) ([]ConflictFile, error) {
+	res, err := m.run(worktreePath, "status", "--porcelain", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w", err)
+	}
+	return parseConflicts(res.Stdout), nil
+}
+
+// parseConflicts extracts the unmerged entries from `git status --porcelain -z` output
+func parseConflicts(output string) []ConflictFile {
+	var conflicts []ConflictFile
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			// Renames and copies are followed by the original path
+			i++
+			continue
+		}
+		if isUnmergedStatus(status) {
+			conflicts = append(conflicts, ConflictFile{Path: path, Status: status})
+		}
+	}
+	return conflicts
+}
+
+// isUnmergedStatus returns true for the XY codes git uses for unmerged paths
+func isUnmergedStatus(status string) bool {
+	switch status {
+	case "DD", "AU", "UD", "UA", "DU", "AA", "UU":
+		return true
+	}
+	return false
+}
+
+// ResolveOurs resolves a conflicted file with "our" version and stages it.
+// During a merge "ours" is the worktree branch; during a rebase it is the branch being rebased onto.
+func (m *Manager) ResolveOurs(worktreePath string, file ConflictFile) error {
+	return m.resolveWith(worktreePath, file, "--ours", file.hasOurs())
+}
+
+// ResolveTheirs resolves a conflicted file with "their" version and stages it.
+// During a merge "theirs" is the merged branch; during a rebase it is the commit being replayed.
+func (m *Manager) ResolveTheirs(worktreePath string, file ConflictFile) error {
+	return m.resolveWith(worktreePath, file, "--theirs", file.hasTheirs())
+}
+
+// resolveWith checks out one side of a conflict, or removes the file if that side deleted it
+func (m *Manager) resolveWith(worktreePath string, file ConflictFile, side string, exists bool) error {
+	if !exists {
+		if _, err := m.run(worktreePath, "rm", "--quiet", "--", file.Path); err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", file.Path, err)
+		}
+		return nil
+	}
+
+	if _, err := m.run(worktreePath, "checkout", side, "--", file.Path); err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", file.Path, err)
+	}
+	return m.MarkResolved(worktreePath, file.Path)
+}
+
+// MarkResolved stages a conflicted file after it was resolved by hand
+func (m *Manager) MarkResolved(worktreePath, path string) error {
+	if unresolved := filesWithConflictMarkers(worktreePath, []string{path}); len(unresolved) > 0 {
+		return fmt.Errorf("%w: %s still contains conflict markers", ErrMergeConflict, path)
+	}
+	if _, err := m.run(worktreePath, "add", "-A", "--", path); err != nil {
+		return fmt.Errorf("failed to mark %s as resolved: %w", path, err)
+	}
+	return nil
+}
+
+// IsMergeInProgress checks if the worktree is in the middle of a merge (MERGE_HEAD exists)
+func (m *Manager) IsMergeInProgress(worktreePath string) bool {
+	_, err := m.run(worktreePath, "rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return err == nil
+}
+
+// MergeMessage returns the subject git prepared for the merge in progress
+// (e.g. "Merge branch 'main' into feature"), or "" if there is none
+func (m *Manager) MergeMessage(worktreePath string) string {
+	res, err := m.run(worktreePath, "rev-parse", "--git-path", "MERGE_MSG")
+	if err != nil {
+		return ""
+	}
+	path := res.Trimmed()
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(worktreePath, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// stageResolved stages the resolved conflicted files (removals included), leaving any other
+// change of the worktree out of the commit that concludes the merge or rebase step
+func (m *Manager) stageResolved(worktreePath string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	if _, err := m.run(worktreePath, append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("failed to stage resolved files: %w", err)
+	}
+	return nil
+}
+
+// PrepareMergeCommit checks that every conflict of the merge in progress is resolved and stages
+// the result, so the merge can be concluded with CommitStaged
+func (m *Manager) PrepareMergeCommit(worktreePath string) error {
+	if !m.IsMergeInProgress(worktreePath) {
+		return fmt.Errorf("no merge in progress")
+	}
+
+	conflicts, err := m.Conflicts(worktreePath)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		paths = append(paths, conflict.Path)
+	}
+	if unresolved := filesWithConflictMarkers(worktreePath, paths); len(unresolved) > 0 {
+		return fmt.Errorf("%w: resolve %s first", ErrMergeConflict, strings.Join(unresolved, ", "))
+	}
+
+	return m.stageResolved(worktreePath, paths)
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseConflicts_OnlyUnmergedEntries(t *testing.T) {
+	output := "UU main.go\x00M  README.md\x00R  new.go\x00old.go\x00UD gone.go\x00?? notes.txt\x00AA both.go\x00"
+
+	conflicts := parseConflicts(output)
+	want := []ConflictFile{{Path: "main.go", Status: "UU"}, {Path: "gone.go", Status: "UD"}, {Path: "both.go", Status: "AA"}}
+	if len(conflicts) != len(want) {
+		t.Fatalf("expected %d conflicts, got %+v", len(want), conflicts)
+	}
+	for i := range want {
+		if conflicts[i] != want[i] {
+			t.Errorf("conflict %d: expected %+v, got %+v", i, want[i], conflicts[i])
+		}
+	}
+	if got := conflicts[1].Description(); got != "deleted by them" {
+		t.Errorf("unexpected description %q", got)
+	}
+}
+
+func TestResolveTheirs_DeletedByThemRemovesFile(t *testing.T) {
+	runner := NewFakeRunner()
+
+	m := NewManagerWithRunner("/repo", runner)
+	if err := m.ResolveTheirs("/repo", ConflictFile{Path: "gone.go", Status: "UD"}); err != nil {
+		t.Fatalf("ResolveTheirs: %v", err)
+	}
+	if !runner.Ran("rm", "--quiet", "--", "gone.go") || runner.Ran("checkout") {
+		t.Error("expected the file to be removed instead of checked out")
+	}
+}
+
+func TestPrepareMergeCommit_WithoutMergeFails(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.OnError(1, "", "rev-parse", "-q", "--verify", "MERGE_HEAD")
+
+	m := NewManagerWithRunner("/repo", runner)
+	err := m.PrepareMergeCommit("/repo")
+	if err == nil || errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected a no-merge error, got %v", err)
+	}
+}
+
+func TestPrepareMergeCommit_StagesOnlyResolvedFiles(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("UD gone.go\x00 M notes.txt\x00", "status", "--porcelain", "-z")
+
+	m := NewManagerWithRunner("/repo", runner)
+	if err := m.PrepareMergeCommit("/repo"); err != nil {
+		t.Fatalf("PrepareMergeCommit: %v", err)
+	}
+	if !runner.Ran("add", "-A", "--", "gone.go") || runner.Ran("add", "-A", "--", "notes.txt") {
+		t.Error("expected only the conflicted file to be staged")
+	}
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/coollabsio/jean/config"
+)
+
+// Name identifies a worktree in session names and config: its branch, or the name of its
+// directory when HEAD is detached
+func (w Worktree) Name() string {
+	if w.Detached || w.Branch == "" {
+		return filepath.Base(w.Path)
+	}
+	return w.Branch
+}
+
+// DisplayName returns the branch of a worktree, or the tag/commit it is detached at
+func (w Worktree) DisplayName() string {
+	if w.Detached {
+		return fmt.Sprintf("(detached at %s)", w.Ref)
+	}
+	return w.Branch
+}
+
+// detachedRef returns the tag pointing at the HEAD of a detached worktree, or its short hash
+func (m *Manager) detachedRef(wt Worktree) string {
+	if !wt.Prunable {
+		if res, err := m.run(wt.Path, "describe", "--tags", "--exact-match", "HEAD"); err == nil && res.Trimmed() != "" {
+			return res.Trimmed()
+		}
+	}
+	return wt.Commit[:min(7, len(wt.Commit))]
+}
+
+// ListTags returns the tags of the repository, newest first
+func (m *Manager) ListTags() ([]string, error) {
+	res, err := m.run(m.repoPath, "tag", "--list", "--sort=-creatordate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return strings.Fields(res.Stdout), nil
+}
+
+// CreateDetached creates a worktree with a detached HEAD at a tag or any commit (e.g. to bisect
+// or reproduce a bug of a release). It runs the same hooks and setup as a branch worktree.
+func (m *Manager) CreateDetached(path, ref string) error {
+	if _, err := m.run(m.repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return fmt.Errorf("'%s' is not a tag or commit", ref)
+	}
+
+	hookCtx := HookContext{WorkspacePath: path}
+
+	// pre_create hook can veto the creation
+	if _, err := m.RunHook(config.HookPreCreate, hookCtx); err != nil {
+		return err
+	}
+
+	if _, err := m.run(m.repoPath, "worktree", "add", "--detach", path, ref); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	return m.setupWorktree(hookCtx, nil)
+}
+
+// CreateBranchHere turns a detached worktree into a normal one by creating a branch at its HEAD
+func (m *Manager) CreateBranchHere(worktreePath, branch string) error {
+	if branch == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+	if _, err := m.run(worktreePath, "checkout", "-b", branch); err != nil {
+		if errors.Is(err, ErrBranchExists) {
+			return fmt.Errorf("%w: '%s'", ErrBranchExists, branch)
+		}
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package git
+
+import "testing"
+
+func TestListLightweight_DetachedWorktree(t *testing.T) {
+	porcelain := "worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n" +
+		"worktree /repo/.workspaces/bisect\nHEAD abcdef0123456789abcdef0123456789abcdef01\ndetached\n\n"
+
+	runner := NewFakeRunner()
+	runner.On(porcelain, "worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	runner.On("v1.2.0\n", "describe", "--tags", "--exact-match", "HEAD")
+
+	worktrees, err := NewManagerWithRunner("/repo", runner).ListLightweight()
+	if err != nil {
+		t.Fatalf("ListLightweight: %v", err)
+	}
+	wt := worktrees[1]
+	if !wt.Detached || wt.Branch != "" || wt.Ref != "v1.2.0" {
+		t.Fatalf("expected a worktree detached at v1.2.0, got %+v", wt)
+	}
+	if wt.Name() != "bisect" || wt.DisplayName() != "(detached at v1.2.0)" {
+		t.Errorf("unexpected names %q and %q", wt.Name(), wt.DisplayName())
+	}
+
+	// Without a tag at HEAD the short hash is shown
+	runner.OnError(128, "fatal: no tag exactly matches", "describe")
+	worktrees, err = NewManagerWithRunner("/repo", runner).ListLightweight()
+	if err != nil {
+		t.Fatalf("ListLightweight: %v", err)
+	}
+	if worktrees[1].Ref != "abcdef0" {
+		t.Errorf("expected the short hash, got %q", worktrees[1].Ref)
+	}
+}
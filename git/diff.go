@@ -0,0 +1,66 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxUntrackedSize is the largest untracked file whose content UncommittedDiff shows
+const maxUntrackedSize = 512 * 1024
+
+// UncommittedDiff returns the uncommitted changes of the worktree like Changes, with the content
+// of untracked text files as a single added hunk
+func (m *Manager) UncommittedDiff(worktreePath string) ([]ChangedFile, error) {
+	files, err := m.Changes(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	for i := range files {
+		if files[i].Status == "?" {
+			files[i].Hunks, files[i].Binary = untrackedHunks(filepath.Join(worktreePath, files[i].Path))
+		}
+	}
+	return files, nil
+}
+
+// BaseDiff returns the changes of the worktree (committed or not) since its branch forked off
+// the base branch, by file. Commits added to the base branch since then are left out.
+func (m *Manager) BaseDiff(worktreePath, baseBranch string) ([]ChangedFile, error) {
+	if baseBranch == "" {
+		return nil, fmt.Errorf("base branch not specified")
+	}
+	mergeBase, err := m.run(worktreePath, "merge-base", baseBranch, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base with '%s': %w", baseBranch, err)
+	}
+	return m.diffFiles(worktreePath, mergeBase.Trimmed())
+}
+
+// untrackedHunks returns the content of a new file as added lines
+// Binary, unreadable and large files return no hunks
+func untrackedHunks(path string) ([]Hunk, bool) {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxUntrackedSize {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, true
+	}
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	hunk := Hunk{Header: fmt.Sprintf("@@ -0,0 +1,%d @@", len(lines))}
+	for _, line := range lines {
+		hunk.Lines = append(hunk.Lines, "+"+line)
+	}
+	return []Hunk{hunk}, false
+}
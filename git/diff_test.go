@@ -0,0 +1,44 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUntrackedHunks(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "new.go")
+	binary := filepath.Join(dir, "image.png")
+	if err := os.WriteFile(text, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(binary, []byte{0x89, 'P', 'N', 'G', 0, 1}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hunks, isBinary := untrackedHunks(text)
+	if isBinary || len(hunks) != 1 {
+		t.Fatalf("expected one text hunk, got %+v (binary %v)", hunks, isBinary)
+	}
+	if hunks[0].Header != "@@ -0,0 +1,3 @@" || len(hunks[0].Lines) != 3 || hunks[0].Lines[0] != "+package main" {
+		t.Errorf("unexpected hunk %+v", hunks[0])
+	}
+
+	if hunks, isBinary := untrackedHunks(binary); !isBinary || hunks != nil {
+		t.Errorf("expected a binary file without hunks, got %+v (binary %v)", hunks, isBinary)
+	}
+}
+
+func TestBaseDiff_FromMergeBase(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("abc123\n", "merge-base", "main", "HEAD")
+
+	m := NewManagerWithRunner("/repo", runner)
+	if _, err := m.BaseDiff("/repo", "main"); err != nil {
+		t.Fatalf("BaseDiff: %v", err)
+	}
+	if !runner.Ran("-c", "core.quotepath=false", "diff", "abc123") {
+		t.Errorf("expected a diff against the merge base, ran %+v", runner.Calls())
+	}
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeRunner is a Runner for tests. It records every command and answers with the
+// responses registered with On/OnError; unmatched commands succeed with no output.
+type FakeRunner struct {
+	mu        sync.Mutex
+	calls     []FakeCall
+	responses []fakeResponse
+}
+
+// FakeCall is a command received by a FakeRunner
+type FakeCall struct {
+	Dir  string
+	Args []string
+}
+
+// String returns the command as it would be typed, without the -C dir
+func (c FakeCall) String() string {
+	return "git " + strings.Join(c.Args, " ")
+}
+
+type fakeResponse struct {
+	prefix   []string
+	result   Result
+	exitCode int // 0 = success
+}
+
+// NewFakeRunner creates an empty FakeRunner
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{}
+}
+
+// On makes commands whose arguments start with args print stdout and succeed.
+// Later registrations take precedence over earlier ones.
+func (f *FakeRunner) On(stdout string, args ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, fakeResponse{prefix: args, result: Result{Stdout: stdout}})
+}
+
+// OnError makes commands whose arguments start with args print stderr and exit with exitCode
+func (f *FakeRunner) OnError(exitCode int, stderr string, args ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, fakeResponse{prefix: args, result: Result{Stderr: stderr}, exitCode: exitCode})
+}
+
+// Calls returns the commands run so far
+func (f *FakeRunner) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// Ran returns true if a command starting with args was run
+func (f *FakeRunner) Ran(args ...string) bool {
+	for _, call := range f.Calls() {
+		if hasPrefix(call.Args, args) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run implements Runner
+func (f *FakeRunner) Run(ctx context.Context, dir string, args ...string) (Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{Dir: dir, Args: append([]string(nil), args...)})
+	if err := ctx.Err(); err != nil {
+		return Result{}, &CommandError{Args: args, Dir: dir, ExitCode: -1, Err: err}
+	}
+
+	for i := len(f.responses) - 1; i >= 0; i-- {
+		response := f.responses[i]
+		if !hasPrefix(args, response.prefix) {
+			continue
+		}
+		if response.exitCode != 0 {
+			return response.result, &CommandError{
+				Args:     args,
+				Dir:      dir,
+				ExitCode: response.exitCode,
+				Result:   response.result,
+				Err:      fmt.Errorf("exit status %d", response.exitCode),
+			}
+		}
+		return response.result, nil
+	}
+	return Result{}, nil
+}
+
+func hasPrefix(args, prefix []string) bool {
+	if len(prefix) > len(args) {
+		return false
+	}
+	for i := range prefix {
+		if args[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
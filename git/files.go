@@ -0,0 +1,215 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CopyConflictError is returned when files from the "copy"/"symlink" lists of jean.json
+// could not be applied to a new worktree. The worktree itself was created; existing files
+// are never overwritten.
+type CopyConflictError struct {
+	Conflicts []string // One entry per skipped path, e.g. ".env: already exists in the worktree"
+}
+
+func (e *CopyConflictError) Error() string {
+	return "some files were not copied into the worktree:\n  - " + strings.Join(e.Conflicts, "\n  - ")
+}
+
+// applyWorktreeFiles copies and symlinks the files matched by the "copy" and "symlink"
+// patterns of jean.json from the repository root into a new worktree.
+// Returns a *CopyConflictError listing the paths that were skipped, nil if everything applied.
+func (m *Manager) applyWorktreeFiles(worktreePath string) error {
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get repo root: %w", err)
+	}
+
+	scriptConfig, err := loadScripts(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	// Never copy the worktrees themselves: the configured directory and the legacy .workspaces
+	root, _ := m.worktreeLocation(repoRoot)
+	skip := worktreeDirsInRepo(repoRoot, expandWorktreeRoot(repoRoot, root), expandWorktreeRoot(repoRoot, DefaultWorktreeRoot))
+
+	var conflicts []string
+	for _, rel := range matchRepoFiles(repoRoot, scriptConfig.Copy, skip) {
+		conflicts = append(conflicts, copyPath(filepath.Join(repoRoot, rel), filepath.Join(worktreePath, rel), rel, skip)...)
+	}
+	for _, rel := range matchRepoFiles(repoRoot, scriptConfig.Symlink, skip) {
+		if conflict := symlinkPath(filepath.Join(repoRoot, rel), filepath.Join(worktreePath, rel), rel); conflict != "" {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &CopyConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// worktreeDirsInRepo returns the worktree directories that lie inside the repository, relative to it
+func worktreeDirsInRepo(repoRoot string, worktreeRoots ...string) []string {
+	var dirs []string
+	for _, root := range worktreeRoots {
+		rel, err := filepath.Rel(repoRoot, root)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		dirs = append(dirs, rel)
+	}
+	return dirs
+}
+
+// inAnyDir checks if a relative path is one of dirs or lies inside one of them
+func inAnyDir(rel string, dirs []string) bool {
+	for _, dir := range dirs {
+		if rel == dir || strings.HasPrefix(rel, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRepoFiles expands glob patterns relative to the repository root.
+// Returns relative paths, skipping .git and the worktree directories in skip.
+func matchRepoFiles(repoRoot string, patterns []string, skip []string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(filepath.Join(repoRoot, pattern))
+		if err != nil {
+			continue
+		}
+		for _, path := range paths {
+			rel, err := filepath.Rel(repoRoot, path)
+			if err != nil || seen[rel] {
+				continue
+			}
+			first := strings.SplitN(rel, string(filepath.Separator), 2)[0]
+			if first == ".git" || first == ".." || inAnyDir(rel, skip) {
+				continue
+			}
+			seen[rel] = true
+			matches = append(matches, rel)
+		}
+	}
+	return matches
+}
+
+// copyPath copies a file, symlink or directory tree without overwriting anything.
+// Identical files already in the worktree (e.g. tracked ones) are skipped silently, and so are
+// the worktree directories in skip.
+// Returns the conflicts found.
+func copyPath(src, dst, rel string, skip []string) []string {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", rel, err)}
+	}
+
+	if info.IsDir() {
+		if existing, err := os.Lstat(dst); err == nil && !existing.IsDir() {
+			return []string{fmt.Sprintf("%s: already exists in the worktree (not a directory)", rel)}
+		}
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return []string{fmt.Sprintf("%s: %v", rel, err)}
+		}
+
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %v", rel, err)}
+		}
+		var conflicts []string
+		for _, entry := range entries {
+			if entryRel := filepath.Join(rel, entry.Name()); !inAnyDir(entryRel, skip) {
+				conflicts = append(conflicts, copyPath(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()), entryRel, skip)...)
+			}
+		}
+		return conflicts
+	}
+
+	if _, err := os.Lstat(dst); err == nil {
+		if sameFile(src, dst) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: already exists in the worktree", rel)}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return []string{fmt.Sprintf("%s: %v", rel, err)}
+	}
+
+	// Keep symlinks as symlinks
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err == nil {
+			err = os.Symlink(target, dst)
+		}
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %v", rel, err)}
+		}
+		return nil
+	}
+
+	if err := copyFile(src, dst, info.Mode().Perm()); err != nil {
+		return []string{fmt.Sprintf("%s: %v", rel, err)}
+	}
+	return nil
+}
+
+// symlinkPath links dst to the absolute src path without overwriting anything.
+// Returns a conflict description, or "" on success.
+func symlinkPath(src, dst, rel string) string {
+	if _, err := os.Lstat(dst); err == nil {
+		if target, err := os.Readlink(dst); err == nil && target == src {
+			return ""
+		}
+		return fmt.Sprintf("%s: already exists in the worktree", rel)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Sprintf("%s: %v", rel, err)
+	}
+	if err := os.Symlink(src, dst); err != nil {
+		return fmt.Sprintf("%s: %v", rel, err)
+	}
+	return ""
+}
+
+// copyFile copies the contents of a regular file, creating dst with the given permissions
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// sameFile returns true if both paths are regular files with identical contents
+func sameFile(a, b string) bool {
+	infoA, errA := os.Lstat(a)
+	infoB, errB := os.Lstat(b)
+	if errA != nil || errB != nil || !infoA.Mode().IsRegular() || !infoB.Mode().IsRegular() || infoA.Size() != infoB.Size() {
+		return false
+	}
+
+	dataA, errA := os.ReadFile(a)
+	dataB, errB := os.ReadFile(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
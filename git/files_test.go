@@ -0,0 +1,171 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+)
+
+func TestApplyWorktreeFiles_SkipsWorktreeDirectories(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	repoRoot := t.TempDir()
+	if err := configManager.SetWorktreeLocation(repoRoot, "build/trees", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{".env", "build/cache.db", "build/trees/other/.env", ".workspaces/old/.env"} {
+		if err := os.MkdirAll(filepath.Join(repoRoot, filepath.Dir(rel)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoRoot, rel), []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "jean.json"), []byte(`{"copy": [".*", "build"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	m := NewManagerWithRunner(repoRoot, runner)
+	m.SetConfigManager(configManager)
+
+	worktreePath := t.TempDir()
+	if err := m.applyWorktreeFiles(worktreePath); err != nil {
+		t.Fatalf("applyWorktreeFiles: %v", err)
+	}
+	var copied []string
+	_ = filepath.WalkDir(worktreePath, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(worktreePath, path)
+			copied = append(copied, rel)
+		}
+		return nil
+	})
+	sort.Strings(copied)
+	if want := []string{".env", "build/cache.db"}; !reflect.DeepEqual(copied, want) {
+		t.Errorf("expected %v to be copied without the worktrees, got %v", want, copied)
+	}
+}
+
+func TestApplyWorktreeFiles_Conflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		jeanJSON  string
+		repo      map[string]string // Files of the repository root
+		worktree  map[string]string // Files already in the new worktree
+		conflicts []string
+		want      map[string]string // Contents expected in the worktree afterwards
+	}{
+		{
+			name:     "copied without conflicts",
+			jeanJSON: `{"copy": [".env", "config/*.local.json"]}`,
+			repo:     map[string]string{".env": "SECRET=1", "config/app.local.json": "{}", "config/app.json": "tracked"},
+			want:     map[string]string{".env": "SECRET=1", "config/app.local.json": "{}"},
+		},
+		{
+			name:     "identical files are skipped silently",
+			jeanJSON: `{"copy": [".env"]}`,
+			repo:     map[string]string{".env": "SECRET=1"},
+			worktree: map[string]string{".env": "SECRET=1"},
+			want:     map[string]string{".env": "SECRET=1"},
+		},
+		{
+			name:      "different files are never overwritten",
+			jeanJSON:  `{"copy": [".env"]}`,
+			repo:      map[string]string{".env": "SECRET=1"},
+			worktree:  map[string]string{".env": "SECRET=2"},
+			conflicts: []string{".env: already exists in the worktree"},
+			want:      map[string]string{".env": "SECRET=2"},
+		},
+		{
+			name:      "a file where a directory is copied",
+			jeanJSON:  `{"copy": ["cache"]}`,
+			repo:      map[string]string{"cache/a.bin": "a"},
+			worktree:  map[string]string{"cache": "not a directory"},
+			conflicts: []string{"cache: already exists in the worktree (not a directory)"},
+			want:      map[string]string{"cache": "not a directory"},
+		},
+		{
+			name:      "directories are merged file by file",
+			jeanJSON:  `{"copy": ["cache"]}`,
+			repo:      map[string]string{"cache/a.bin": "a", "cache/b.bin": "b"},
+			worktree:  map[string]string{"cache/b.bin": "changed"},
+			conflicts: []string{"cache/b.bin: already exists in the worktree"},
+			want:      map[string]string{"cache/a.bin": "a", "cache/b.bin": "changed"},
+		},
+		{
+			name:      "symlinks don't replace existing files",
+			jeanJSON:  `{"symlink": ["node_modules", ".env"]}`,
+			repo:      map[string]string{"node_modules/x/index.js": "x", ".env": "SECRET=1"},
+			worktree:  map[string]string{".env": "SECRET=1"},
+			conflicts: []string{".env: already exists in the worktree"},
+			want:      map[string]string{"node_modules/x/index.js": "x", ".env": "SECRET=1"},
+		},
+		{
+			name:     ".git and the worktrees are skipped",
+			jeanJSON: `{"copy": ["*"]}`,
+			repo:     map[string]string{".workspaces/other/.env": "other", "Makefile": "all:"},
+			want:     map[string]string{"Makefile": "all:", "jean.json": `{"copy": ["*"]}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoRoot := initRepo(t)
+			writeFiles(t, repoRoot, tt.repo)
+			writeFiles(t, repoRoot, map[string]string{"jean.json": tt.jeanJSON})
+			worktreePath := t.TempDir()
+			writeFiles(t, worktreePath, tt.worktree)
+
+			err := NewManager(repoRoot).applyWorktreeFiles(worktreePath)
+
+			var conflictErr *CopyConflictError
+			if errors.As(err, &conflictErr) {
+				if !reflect.DeepEqual(conflictErr.Conflicts, tt.conflicts) {
+					t.Errorf("expected conflicts %q, got %q", tt.conflicts, conflictErr.Conflicts)
+				}
+			} else if err != nil || tt.conflicts != nil {
+				t.Errorf("expected conflicts %q, got %v", tt.conflicts, err)
+			}
+
+			var files []string
+			_ = filepath.WalkDir(worktreePath, func(path string, d os.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					rel, _ := filepath.Rel(worktreePath, path)
+					files = append(files, rel)
+				}
+				return nil
+			})
+			if len(files) != len(tt.want) {
+				t.Errorf("expected the files %v in the worktree, got %v", tt.want, files)
+			}
+			for rel, content := range tt.want {
+				if data, err := os.ReadFile(filepath.Join(worktreePath, rel)); err != nil || string(data) != content {
+					t.Errorf("expected %s to contain %q, got %q (%v)", rel, content, data, err)
+				}
+			}
+		})
+	}
+}
+
+// writeFiles creates files (and their directories) under dir from relative path -> content
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(rel)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
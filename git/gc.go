@@ -0,0 +1,122 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultStaleDays is how long a worktree can go untouched before `jean gc` offers to remove it
+const DefaultStaleDays = 30
+
+// StaleWorktree is a worktree that can be garbage collected, with the reasons why
+type StaleWorktree struct {
+	Worktree
+	Reasons []string // e.g. "PR #42 merged", "merged into main", "untouched for 45 days"
+}
+
+// FindStaleWorktrees returns the worktrees whose latest PR was merged or closed, whose branch
+// is fully merged into the base branch, which were not modified for maxAgeDays (0 disables
+// the age check), or whose directory was deleted (Prunable, cleaned up with PruneWorktrees).
+// The main worktree, the base branch and protected branches are never returned, locked
+// worktrees only with includeLocked (jean gc -force, the TUI asks before forcing them).
+// HasUncommitted is set on the results so callers can skip or force their removal.
+func (m *Manager) FindStaleWorktrees(baseBranch string, maxAgeDays int, includeLocked bool) ([]StaleWorktree, error) {
+	worktrees, err := m.ListLightweight()
+	if err != nil {
+		return nil, err
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []StaleWorktree
+	for _, wt := range worktrees {
+		if wt.IsCurrent || wt.Path == repoRoot {
+			continue
+		}
+		if wt.Branch == baseBranch || m.IsProtectedBranch(wt.Branch) {
+			continue
+		}
+		if wt.Locked && !includeLocked {
+			continue
+		}
+
+		// Nothing left to check (or lose) in a deleted directory, it only needs pruning
+		if wt.Prunable {
+			stale = append(stale, StaleWorktree{Worktree: wt, Reasons: []string{"directory was deleted"}})
+			continue
+		}
+		// Detached worktrees have no branch to be merged
+		if wt.Branch == "" {
+			continue
+		}
+
+		var reasons []string
+		if m.configManager != nil {
+			if pr := m.configManager.GetLatestPR(repoRoot, wt.Branch); pr != nil && (pr.Status == "merged" || pr.Status == "closed") {
+				reasons = append(reasons, fmt.Sprintf("PR #%d %s", pr.PRNumber, pr.Status))
+			}
+		}
+		if baseBranch != "" && m.isBranchMerged(wt.Branch, baseBranch) {
+			reasons = append(reasons, fmt.Sprintf("merged into %s", baseBranch))
+		}
+		if maxAgeDays > 0 && !wt.LastModified.IsZero() {
+			if days := int(time.Since(wt.LastModified).Hours() / 24); days >= maxAgeDays {
+				reasons = append(reasons, fmt.Sprintf("untouched for %d days", days))
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		// Only checked for candidates, it's the expensive part
+		wt.HasUncommitted, _ = m.HasUncommittedChanges(wt.Path)
+		stale = append(stale, StaleWorktree{Worktree: wt, Reasons: reasons})
+	}
+	return stale, nil
+}
+
+// isBranchMerged checks if a branch was merged into the base branch with a merge commit.
+// A branch that was just created from the base (or fast-forwarded into it) has its tip on the
+// base's first-parent history and can't be told apart from new work, so it doesn't count.
+func (m *Manager) isBranchMerged(branch, baseBranch string) bool {
+	if _, err := m.run(m.repoPath, "merge-base", "--is-ancestor", branch, baseBranch); err != nil {
+		return false
+	}
+	tip, err := m.run(m.repoPath, "rev-parse", "--verify", branch+"^{commit}")
+	if err != nil {
+		return false
+	}
+	// The base's first-parent commits since the branch tip, which includes the tip itself if it is on that line
+	res, err := m.run(m.repoPath, "rev-list", "--first-parent", baseBranch, "--not", tip.Trimmed()+"^@")
+	if err != nil {
+		return false
+	}
+	for _, hash := range strings.Fields(res.Stdout) {
+		if hash == tip.Trimmed() {
+			return false
+		}
+	}
+	return true
+}
+
+// PruneWorktrees removes git's metadata of the worktrees whose directory was deleted
+// (git worktree prune) and releases their port blocks. Their branches are kept.
+func (m *Manager) PruneWorktrees() error {
+	worktrees, err := m.ListLightweight()
+	if err != nil {
+		return err
+	}
+	if _, err := m.run(m.repoPath, "worktree", "prune"); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		if wt.Prunable {
+			m.releasePortBlock(wt.Path)
+			m.forgetManaged(wt.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package git
+
+import "testing"
+
+func TestFindStaleWorktrees_MergedBranches(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/merged\nHEAD aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nbranch refs/heads/merged\n\n"+
+		"worktree /repo/.workspaces/fresh\nHEAD dddddddddddddddddddddddddddddddddddddddd\nbranch refs/heads/fresh\n\n"+
+		"worktree /repo/.workspaces/wip\nHEAD eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee\nbranch refs/heads/wip\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+
+	// merged was merged with a merge commit, fresh was just branched off main, wip has new commits
+	runner.On("aaa\n", "rev-parse", "--verify", "merged^{commit}")
+	runner.On("ccc\nbbb\n", "rev-list", "--first-parent", "main", "--not", "aaa^@")
+	runner.On("ddd\n", "rev-parse", "--verify", "fresh^{commit}")
+	runner.On("ccc\nddd\n", "rev-list", "--first-parent", "main", "--not", "ddd^@")
+	runner.OnError(1, "", "merge-base", "--is-ancestor", "wip", "main")
+
+	m := NewManagerWithRunner("/repo", runner)
+	stale, err := m.FindStaleWorktrees("main", 0, false)
+	if err != nil {
+		t.Fatalf("FindStaleWorktrees: %v", err)
+	}
+
+	if len(stale) != 1 {
+		t.Fatalf("expected only the merged worktree, got %+v", stale)
+	}
+	if stale[0].Branch != "merged" || len(stale[0].Reasons) != 1 || stale[0].Reasons[0] != "merged into main" {
+		t.Errorf("unexpected stale worktree %+v", stale[0])
+	}
+}
+
+func TestFindStaleWorktrees_DeletedDirectory(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/gone\nHEAD aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nbranch refs/heads/gone\nprunable gitdir file points to non-existent location\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	runner.OnError(1, "", "merge-base", "--is-ancestor")
+
+	m := NewManagerWithRunner("/repo", runner)
+	stale, err := m.FindStaleWorktrees("main", DefaultStaleDays, false)
+	if err != nil {
+		t.Fatalf("FindStaleWorktrees: %v", err)
+	}
+
+	if len(stale) != 1 || !stale[0].Prunable || stale[0].Reasons[0] != "directory was deleted" {
+		t.Fatalf("expected the deleted worktree to be prunable, got %+v", stale)
+	}
+	if runner.Ran("status") {
+		t.Error("a deleted worktree has no status to check")
+	}
+}
+
+func TestFindStaleWorktrees_LockedOnlyWhenIncluded(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/kept\nHEAD aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nbranch refs/heads/kept\nlocked on a USB drive\n\n"+
+		"worktree /repo/.workspaces/gone\nHEAD bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\nbranch refs/heads/gone\nlocked\nprunable gitdir file points to non-existent location\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	runner.On("aaa\n", "rev-parse", "--verify", "kept^{commit}")
+	runner.On("ccc\n", "rev-list", "--first-parent", "main", "--not", "aaa^@")
+
+	m := NewManagerWithRunner("/repo", runner)
+	stale, err := m.FindStaleWorktrees("main", 0, false)
+	if err != nil {
+		t.Fatalf("FindStaleWorktrees: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Fatalf("expected locked worktrees to be skipped, got %+v", stale)
+	}
+
+	stale, err = m.FindStaleWorktrees("main", 0, true)
+	if err != nil {
+		t.Fatalf("FindStaleWorktrees: %v", err)
+	}
+	if len(stale) != 2 || !stale[0].Locked || !stale[1].Locked {
+		t.Fatalf("expected both locked worktrees when included, got %+v", stale)
+	}
+}
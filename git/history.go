@@ -0,0 +1,248 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Commit is a commit of a worktree branch with its diffstat
+type Commit struct {
+	Hash         string
+	ShortHash    string
+	Subject      string
+	Author       string
+	Date         string // Relative date, e.g. "2 hours ago"
+	FilesChanged int
+	Insertions   int
+	Deletions    int
+}
+
+// commitLogFormat starts every commit with a record separator, so the --shortstat line
+// that follows it can be told apart
+const commitLogFormat = "--format=%x1e%H%x1f%h%x1f%s%x1f%an%x1f%cr"
+
+// CommitsAhead returns the commits of the worktree branch that are not in the base branch,
+// newest first
+func (m *Manager) CommitsAhead(worktreePath, baseBranch string) ([]Commit, error) {
+	if baseBranch == "" {
+		return nil, fmt.Errorf("base branch not specified")
+	}
+	if _, err := m.run(worktreePath, "rev-parse", "--verify", baseBranch); err != nil {
+		return nil, fmt.Errorf("base branch '%s' does not exist", baseBranch)
+	}
+
+	res, err := m.run(worktreePath, "log", commitLogFormat, "--shortstat", fmt.Sprintf("%s..HEAD", baseBranch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
+	}
+	return parseCommits(res.Stdout), nil
+}
+
+// parseCommits parses `git log` output in commitLogFormat with --shortstat
+func parseCommits(output string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		header, stat, _ := strings.Cut(record, "\n")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+
+		commit := Commit{Hash: fields[0], ShortHash: fields[1], Subject: fields[2], Author: fields[3], Date: fields[4]}
+		// e.g. " 3 files changed, 10 insertions(+), 2 deletions(-)", missing for merges and empty commits
+		for _, part := range strings.Split(strings.TrimSpace(stat), ", ") {
+			count, kind, ok := strings.Cut(part, " ")
+			if !ok {
+				continue
+			}
+			n, err := strconv.Atoi(count)
+			if err != nil {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(kind, "file"):
+				commit.FilesChanged = n
+			case strings.HasPrefix(kind, "insertion"):
+				commit.Insertions = n
+			case strings.HasPrefix(kind, "deletion"):
+				commit.Deletions = n
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// CommitDiff returns the header, diffstat and patch of a commit (git show)
+func (m *Manager) CommitDiff(worktreePath, hash string) (string, error) {
+	res, err := m.run(worktreePath, "show", "--stat", "--patch", "--format=fuller", "--no-color", "--no-ext-diff", hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to show commit %s: %w", hash, err)
+	}
+	return res.Stdout, nil
+}
+
+// CommitMessage returns the full message of a commit
+func (m *Manager) CommitMessage(worktreePath, hash string) (string, error) {
+	res, err := m.run(worktreePath, "log", "-1", "--format=%B", hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get message of %s: %w", hash, err)
+	}
+	return strings.TrimSpace(res.Stdout), nil
+}
+
+// AmendCommit adds all uncommitted changes to the last commit, keeping its message
+// Only the GPGSign preference of opts is used
+func (m *Manager) AmendCommit(worktreePath string, opts CommitOptions) (string, error) {
+	if err := m.checkCanRewrite(worktreePath); err != nil {
+		return "", err
+	}
+
+	if _, err := m.run(worktreePath, "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+	args := append([]string{"commit", "--amend", "--no-edit"}, opts.signingArgs()...)
+	if _, err := m.run(worktreePath, args...); err != nil {
+		return "", fmt.Errorf("failed to amend commit: %w", err)
+	}
+
+	res, err := m.run(worktreePath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return res.Trimmed(), nil
+}
+
+// RewordCommit replaces the message of a commit of the worktree branch. Commits after it are
+// replayed on top of the reworded one, so the worktree must be clean and the history linear.
+func (m *Manager) RewordCommit(worktreePath, hash, message string) error {
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("commit message cannot be empty")
+	}
+	if err := m.checkCanRewrite(worktreePath); err != nil {
+		return err
+	}
+
+	head, err := m.run(worktreePath, "rev-parse", "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	target, err := m.run(worktreePath, "rev-parse", "--verify", hash+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("commit %s does not exist", hash)
+	}
+
+	// The last commit is reworded in place; --only leaves staged changes out of it
+	if target.Trimmed() == head.Trimmed() {
+		if _, err := m.run(worktreePath, "commit", "--amend", "--only", "--allow-empty", "-m", message); err != nil {
+			return fmt.Errorf("failed to reword commit: %w", err)
+		}
+		return nil
+	}
+
+	if dirty, err := m.HasUncommittedChanges(worktreePath); err != nil {
+		return err
+	} else if dirty {
+		return fmt.Errorf("worktree has uncommitted changes. Commit or stash them first")
+	}
+	if merges, err := m.run(worktreePath, "rev-list", "--merges", fmt.Sprintf("%s..HEAD", hash)); err != nil {
+		return fmt.Errorf("failed to check for merges: %w", err)
+	} else if merges.Trimmed() != "" {
+		return fmt.Errorf("cannot reword %s: merge commits follow it. Squash the branch instead", hash)
+	}
+
+	branch, err := m.GetCurrentBranchForWorktree(worktreePath)
+	if err != nil {
+		return err
+	}
+
+	// Reword a detached copy of the commit and replay the later ones on it. The branch only
+	// moves once everything succeeded, so a failure just checks it out again.
+	restore := func(err error) error {
+		_, _ = m.run(worktreePath, "cherry-pick", "--abort")
+		_, _ = m.run(worktreePath, "checkout", "-q", branch)
+		return fmt.Errorf("failed to reword commit: %w", err)
+	}
+	if _, err := m.run(worktreePath, "checkout", "-q", "--detach", target.Trimmed()); err != nil {
+		return restore(err)
+	}
+	if _, err := m.run(worktreePath, "commit", "--amend", "--only", "--allow-empty", "-m", message); err != nil {
+		return restore(err)
+	}
+	if _, err := m.run(worktreePath, "cherry-pick", "--allow-empty", fmt.Sprintf("%s..%s", target.Trimmed(), head.Trimmed())); err != nil {
+		return restore(err)
+	}
+	if _, err := m.run(worktreePath, "checkout", "-q", "-B", branch); err != nil {
+		return restore(err)
+	}
+	return nil
+}
+
+// RevertCommit creates a commit undoing the given one
+// If the revert conflicts with later changes it is aborted and an ErrMergeConflict error is returned
+func (m *Manager) RevertCommit(worktreePath, hash string) error {
+	if _, err := m.run(worktreePath, "revert", "--no-edit", hash); err != nil {
+		if _, verr := m.run(worktreePath, "rev-parse", "-q", "--verify", "REVERT_HEAD"); verr == nil {
+			_, _ = m.run(worktreePath, "revert", "--abort")
+		}
+		if errors.Is(err, ErrMergeConflict) {
+			return fmt.Errorf("%w: reverting %s conflicts with later changes, revert it by hand", ErrMergeConflict, hash)
+		}
+		return fmt.Errorf("failed to revert %s: %w", hash, err)
+	}
+	return nil
+}
+
+// SquashCommits replaces the commits of the worktree branch that are not in the base branch
+// by a single commit. Returns the hash of the new commit.
+func (m *Manager) SquashCommits(worktreePath, baseBranch, subject string, opts CommitOptions) (string, error) {
+	if subject == "" {
+		return "", fmt.Errorf("commit subject cannot be empty")
+	}
+	if err := m.checkCanRewrite(worktreePath); err != nil {
+		return "", err
+	}
+	if dirty, err := m.HasUncommittedChanges(worktreePath); err != nil {
+		return "", err
+	} else if dirty {
+		return "", fmt.Errorf("worktree has uncommitted changes. Commit or stash them first")
+	}
+
+	// The merge base is the last base commit in the branch, also after merging the base into it
+	mergeBase, err := m.run(worktreePath, "merge-base", baseBranch, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base with '%s': %w", baseBranch, err)
+	}
+	head, err := m.run(worktreePath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	if mergeBase.Trimmed() == head.Trimmed() {
+		return "", fmt.Errorf("no commits ahead of '%s' to squash", baseBranch)
+	}
+
+	if _, err := m.run(worktreePath, "reset", "--soft", mergeBase.Trimmed()); err != nil {
+		return "", fmt.Errorf("failed to squash commits: %w", err)
+	}
+	hash, err := m.CommitStaged(worktreePath, subject, opts)
+	if err != nil {
+		// Put the branch back where it was, the index still holds the same tree
+		_, _ = m.run(worktreePath, "reset", "--soft", head.Trimmed())
+		return "", err
+	}
+	return hash, nil
+}
+
+// checkCanRewrite refuses to rewrite the history of a protected branch
+func (m *Manager) checkCanRewrite(worktreePath string) error {
+	branch, err := m.GetCurrentBranchForWorktree(worktreePath)
+	if err != nil {
+		return err
+	}
+	if branch == "" {
+		return fmt.Errorf("HEAD is detached, check out a branch first")
+	}
+	return m.checkNotProtected("rewrite the history of", branch)
+}
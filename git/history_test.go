@@ -0,0 +1,54 @@
+package git
+
+import "testing"
+
+func TestParseCommits_Diffstat(t *testing.T) {
+	output := "\x1eaaa111\x1faaa\x1ffeat: add x\x1fJane\x1f2 hours ago\n\n 3 files changed, 10 insertions(+), 2 deletions(-)\n" +
+		"\x1ebbb222\x1fbbb\x1fMerge branch 'main'\x1fBob\x1f3 days ago\n" +
+		"\x1eccc333\x1fccc\x1fchore: remove y\x1fJane\x1f4 days ago\n\n 1 file changed, 5 deletions(-)\n"
+
+	commits := parseCommits(output)
+	want := []Commit{
+		{Hash: "aaa111", ShortHash: "aaa", Subject: "feat: add x", Author: "Jane", Date: "2 hours ago", FilesChanged: 3, Insertions: 10, Deletions: 2},
+		{Hash: "bbb222", ShortHash: "bbb", Subject: "Merge branch 'main'", Author: "Bob", Date: "3 days ago"},
+		{Hash: "ccc333", ShortHash: "ccc", Subject: "chore: remove y", Author: "Jane", Date: "4 days ago", FilesChanged: 1, Deletions: 5},
+	}
+	if len(commits) != len(want) {
+		t.Fatalf("expected %d commits, got %+v", len(want), commits)
+	}
+	for i := range want {
+		if commits[i] != want[i] {
+			t.Errorf("commit %d: expected %+v, got %+v", i, want[i], commits[i])
+		}
+	}
+}
+
+func TestSquashCommits_RefusesDirtyWorktree(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("feature\n", "branch", "--show-current")
+	runner.On(" M main.go\n", "status", "--porcelain")
+
+	m := NewManagerWithRunner("/repo", runner)
+	if _, err := m.SquashCommits("/repo", "main", "feat: x", CommitOptions{}); err == nil {
+		t.Fatal("expected an error for uncommitted changes")
+	}
+	if runner.Ran("reset") {
+		t.Error("expected the branch not to be reset")
+	}
+}
+
+func TestAmendCommit_ReturnsHashOrError(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("feature\n", "branch", "--show-current")
+	runner.On("abc123\n", "rev-parse", "HEAD")
+
+	m := NewManagerWithRunner("/repo", runner)
+	if hash, err := m.AmendCommit("/repo", CommitOptions{}); err != nil || hash != "abc123" {
+		t.Fatalf("expected hash abc123, got %q and %v", hash, err)
+	}
+
+	runner.OnError(128, "fatal: ambiguous argument 'HEAD'", "rev-parse", "HEAD")
+	if _, err := m.AmendCommit("/repo", CommitOptions{}); err == nil {
+		t.Error("expected the rev-parse failure to be returned")
+	}
+}
@@ -0,0 +1,158 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/coollabsio/jean/config"
+)
+
+// HookContext holds the values exported to jean.json scripts and hooks as JEAN_* variables
+type HookContext struct {
+	WorkspacePath string // JEAN_WORKSPACE_PATH
+	Branch        string // JEAN_BRANCH
+	BaseBranch    string // JEAN_BASE_BRANCH
+	PRURL         string // JEAN_PR_URL
+	Port          int    // JEAN_PORT (first port of the worktree's block, 0 = none)
+}
+
+// Environ returns the JEAN_* variables for the context as KEY=value pairs
+func (ctx HookContext) Environ(repoRoot string) []string {
+	return []string{
+		fmt.Sprintf("JEAN_WORKSPACE_PATH=%s", ctx.WorkspacePath),
+		fmt.Sprintf("JEAN_ROOT_PATH=%s", repoRoot),
+		fmt.Sprintf("JEAN_BRANCH=%s", ctx.Branch),
+		fmt.Sprintf("JEAN_BASE_BRANCH=%s", ctx.BaseBranch),
+		fmt.Sprintf("JEAN_PR_URL=%s", ctx.PRURL),
+		fmt.Sprintf("JEAN_PORT=%s", ctx.portString()),
+		fmt.Sprintf("JEAN_PORT_RANGE=%s", ctx.PortRange()),
+	}
+}
+
+// PortRange returns the worktree's port block as "first-last", or "" if it has none
+func (ctx HookContext) PortRange() string {
+	if ctx.Port == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", ctx.Port, ctx.Port+config.PortBlockSize-1)
+}
+
+func (ctx HookContext) portString() string {
+	if ctx.Port == 0 {
+		return ""
+	}
+	return strconv.Itoa(ctx.Port)
+}
+
+// HookError is returned when a lifecycle hook exits non-zero
+type HookError struct {
+	Hook   string // Hook name (e.g. "pre_create")
+	Output string // Combined stdout/stderr of the hook
+	Err    error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook failed: %s\n\nHook output:\n%s", e.Hook, e.Err.Error(), e.Output)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// RunHook runs a lifecycle hook from the "hooks" section of jean.json.
+// Returns the hook output. A missing hook is a no-op; a non-zero exit returns a *HookError
+// so callers can abort the operation and show the output.
+func (m *Manager) RunHook(hook string, ctx HookContext) (string, error) {
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repo root: %w", err)
+	}
+
+	scriptConfig, err := loadScripts(repoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	script := scriptConfig.GetHook(hook)
+	if script == "" {
+		return "", nil
+	}
+
+	// Only hooks get JEAN_HOOK, one command can serve several of them
+	output, err := m.runScript(config.Script{Command: script, Env: map[string]string{"JEAN_HOOK": hook}}, repoRoot, ctx)
+	if err != nil {
+		return output, &HookError{Hook: hook, Output: output, Err: err}
+	}
+	return output, nil
+}
+
+// loadScripts loads jean.json from the repository root. Invalid entries only make
+// config.LoadScripts skip them (the TUI and CLI warn about those), so they never stop
+// hooks, scripts or the setup of a worktree; a file that can't be parsed does.
+func loadScripts(repoRoot string) (*config.ScriptConfig, error) {
+	scriptConfig, err := config.LoadScripts(repoRoot)
+	var validationErr *config.ValidationError
+	if err != nil && !errors.As(err, &validationErr) {
+		return nil, fmt.Errorf("failed to load jean.json: %w", err)
+	}
+	return scriptConfig, nil
+}
+
+// RunScript runs a named script from jean.json in the background and returns its output.
+// The script's cwd, env and timeout options are applied.
+func (m *Manager) RunScript(name string, ctx HookContext) (string, error) {
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repo root: %w", err)
+	}
+
+	scriptConfig, err := loadScripts(repoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	script, ok := scriptConfig.LookupScript(name)
+	if !ok {
+		return "", fmt.Errorf("script '%s' not found in jean.json", name)
+	}
+
+	return m.runScript(script, repoRoot, ctx)
+}
+
+// runScript runs a shell command with the JEAN_* environment plus the script's own env.
+// The command runs in the workspace if it exists, otherwise in the repository root,
+// with the script's cwd resolved against that directory. A timeout kills the command.
+func (m *Manager) runScript(script config.Script, repoRoot string, ctx HookContext) (string, error) {
+	dir := repoRoot
+	if ctx.WorkspacePath != "" {
+		if info, err := os.Stat(ctx.WorkspacePath); err == nil && info.IsDir() {
+			dir = ctx.WorkspacePath
+		}
+	}
+
+	runCtx := context.Background()
+	if script.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, script.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", script.Command)
+	cmd.Dir = script.Dir(dir)
+	cmd.Env = append(os.Environ(), ctx.Environ(repoRoot)...)
+	cmd.Env = append(cmd.Env, script.Environ()...)
+	// Don't wait forever on background processes still holding the output pipe after a kill
+	cmd.WaitDelay = 2 * time.Second
+
+	// Capture both stdout and stderr so the output can be shown to the user
+	output, err := cmd.CombinedOutput()
+	if runCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", script.Timeout)
+	}
+	return string(output), err
+}
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Where new worktrees go when nothing is configured: <repo>/.workspaces/<branch>
+const (
+	DefaultWorktreeRoot     = ".workspaces"
+	DefaultWorktreeTemplate = "{branch}"
+)
+
+// WorktreePath expands the worktree root and path template for a branch. The root may start
+// with ~ and is relative to the repository root otherwise. Both may use the {repo} (name of
+// the repository directory) and {branch} (with slashes replaced by dashes) placeholders, e.g.
+// root "~/worktrees/{repo}" with template "{repo}-{branch}".
+func WorktreePath(repoRoot, root, template, branch string) string {
+	if template == "" {
+		template = DefaultWorktreeTemplate
+	}
+	name := expandPlaceholders(template, repoRoot, sanitizeBranchForPath(branch))
+	return filepath.Join(expandWorktreeRoot(repoRoot, root), name)
+}
+
+// expandWorktreeRoot resolves the directory worktrees are created in
+func expandWorktreeRoot(repoRoot, root string) string {
+	if root == "" {
+		root = DefaultWorktreeRoot
+	}
+	root = expandPlaceholders(root, repoRoot, "")
+	if root == "~" || strings.HasPrefix(root, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			root = filepath.Join(home, strings.TrimPrefix(root, "~"))
+		}
+	}
+	if !filepath.IsAbs(root) {
+		root = filepath.Join(repoRoot, root)
+	}
+	return filepath.Clean(root)
+}
+
+func expandPlaceholders(s, repoRoot, branch string) string {
+	return strings.NewReplacer("{repo}", filepath.Base(repoRoot), "{branch}", branch).Replace(s)
+}
+
+// ValidateWorktreeTemplate checks that a path template names a directory for each branch
+func ValidateWorktreeTemplate(template string) error {
+	if template != "" && !strings.Contains(template, "{branch}") {
+		return fmt.Errorf("path template must contain {branch}, otherwise all worktrees get the same directory")
+	}
+	return nil
+}
+
+// worktreeLocation returns the configured worktree root and path template, "" for the defaults
+func (m *Manager) worktreeLocation(repoRoot string) (root, template string) {
+	if m.configManager == nil {
+		return "", ""
+	}
+	return m.configManager.GetWorktreeLocation(repoRoot)
+}
+
+// IsManaged checks if the worktree at path was created by jean. Worktrees are recorded in the
+// config when they are created, the ones in <repo>/.workspaces predate the record and count too.
+func (m *Manager) IsManaged(path string) bool {
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return false
+	}
+	return m.isManaged(repoRoot, path)
+}
+
+func (m *Manager) isManaged(repoRoot, path string) bool {
+	path = absPath(path)
+	if m.configManager != nil && m.configManager.IsManagedWorktree(repoRoot, path) {
+		return true
+	}
+	legacyDir := filepath.Join(repoRoot, DefaultWorktreeRoot)
+	return strings.HasPrefix(path, legacyDir+string(filepath.Separator))
+}
+
+// markManaged records a worktree jean just created
+func (m *Manager) markManaged(path string) {
+	if m.configManager == nil {
+		return
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return
+	}
+	if err := m.configManager.AddManagedWorktree(repoRoot, absPath(path), false); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record worktree '%s': %v\n", path, err)
+	}
+}
+
+// forgetManaged drops the record of a removed worktree
+func (m *Manager) forgetManaged(path string) {
+	if m.configManager == nil {
+		return
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return
+	}
+	_ = m.configManager.RemoveManagedWorktree(repoRoot, absPath(path))
+}
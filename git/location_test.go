@@ -0,0 +1,54 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+)
+
+func TestWorktreePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		root, template, want string
+	}{
+		{"", "", "/src/app/.workspaces/feature-login"},
+		{"../trees", "", "/src/trees/feature-login"},
+		{"~/worktrees/{repo}", "{repo}-{branch}", filepath.Join(home, "worktrees/app/app-feature-login")},
+		{"/tmp/wt", "{repo}/{branch}", "/tmp/wt/app/feature-login"},
+	}
+	for _, tt := range tests {
+		if got := WorktreePath("/src/app", tt.root, tt.template, "feature/login"); got != tt.want {
+			t.Errorf("WorktreePath(%q, %q) = %q, want %q", tt.root, tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestIsManaged_RecordedOrLegacyWorkspaces(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	if err := configManager.AddManagedWorktree("/repo", "/worktrees/repo-feature", false); err != nil {
+		t.Fatalf("AddManagedWorktree: %v", err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	m := NewManagerWithRunner("/repo", runner)
+	m.SetConfigManager(configManager)
+
+	for path, want := range map[string]bool{
+		"/worktrees/repo-feature": true,  // recorded when jean created it
+		"/repo/.workspaces/old":   true,  // created before worktrees were recorded
+		"/repo":                   false, // main worktree
+		"/elsewhere/by-hand":      false, // git worktree add
+	} {
+		if got := m.IsManaged(path); got != want {
+			t.Errorf("IsManaged(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
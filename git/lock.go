@@ -0,0 +1,53 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWorktreeLocked is returned when removing a locked worktree without forcing it
+var ErrWorktreeLocked = errors.New("worktree is locked")
+
+// Lock locks a worktree so it is not removed, pruned or garbage collected unless forced.
+// The reason is optional and shown next to the lock badge.
+func (m *Manager) Lock(worktreePath, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, worktreePath)
+	if _, err := m.run(m.repoPath, args...); err != nil {
+		return fmt.Errorf("failed to lock worktree: %w", err)
+	}
+	return nil
+}
+
+// Unlock unlocks a worktree
+func (m *Manager) Unlock(worktreePath string) error {
+	if _, err := m.run(m.repoPath, "worktree", "unlock", worktreePath); err != nil {
+		return fmt.Errorf("failed to unlock worktree: %w", err)
+	}
+	return nil
+}
+
+// findWorktree returns the worktree at path, nil if git doesn't know it
+func (m *Manager) findWorktree(worktreePath string) *Worktree {
+	worktrees, err := m.ListLightweight()
+	if err != nil {
+		return nil
+	}
+	for i := range worktrees {
+		if worktrees[i].Path == absPath(worktreePath) {
+			return &worktrees[i]
+		}
+	}
+	return nil
+}
+
+// lockedError describes why a locked worktree can't be removed
+func lockedError(wt *Worktree) error {
+	if wt.LockReason != "" {
+		return fmt.Errorf("%w (%s), unlock it or force the removal", ErrWorktreeLocked, wt.LockReason)
+	}
+	return fmt.Errorf("%w, unlock it or force the removal", ErrWorktreeLocked)
+}
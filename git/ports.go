@@ -0,0 +1,66 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coollabsio/jean/config"
+)
+
+// SetConfigManager lets the manager persist per-worktree data (like port blocks) in the jean config
+func (m *Manager) SetConfigManager(configManager *config.Manager) {
+	m.configManager = configManager
+}
+
+// PortBlock returns the first port of a worktree's port block, or 0 if it has none
+func (m *Manager) PortBlock(worktreePath string) int {
+	if m.configManager == nil {
+		return 0
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return 0
+	}
+	return m.configManager.GetPortBlock(repoRoot, absPath(worktreePath))
+}
+
+// AllocatePortBlock returns the port block of a worktree, allocating one if needed.
+// Returns 0 if no config manager is set or no block could be allocated.
+func (m *Manager) AllocatePortBlock(worktreePath string) int {
+	if m.configManager == nil {
+		return 0
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return 0
+	}
+	port, err := m.configManager.AllocatePortBlock(repoRoot, absPath(worktreePath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to allocate ports for '%s': %v\n", worktreePath, err)
+		return 0
+	}
+	return port
+}
+
+// releasePortBlock frees the port block of a removed worktree
+func (m *Manager) releasePortBlock(worktreePath string) {
+	if m.configManager == nil {
+		return
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return
+	}
+	if err := m.configManager.ReleasePortBlock(repoRoot, absPath(worktreePath)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to release ports for '%s': %v\n", worktreePath, err)
+	}
+}
+
+// absPath returns the cleaned absolute form of a path so config keys match `git worktree list`
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
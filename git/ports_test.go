@@ -0,0 +1,70 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+)
+
+// initRepo creates a git repository with one commit and returns its (symlink-free) root
+func initRepo(t *testing.T) string {
+	t.Helper()
+	repoRoot, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitCmd(t, repoRoot, "init", "-q", "-b", "main")
+	gitCmd(t, repoRoot, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial")
+	return repoRoot
+}
+
+// gitCmd runs git in dir and fails the test on error
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+func TestPortBlock_ReleasedWhenRemovedAndReused(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	repoRoot := initRepo(t)
+	m := NewManager(repoRoot)
+
+	a := filepath.Join(repoRoot, ".workspaces", "a")
+	b := filepath.Join(repoRoot, ".workspaces", "b")
+	if port := m.AllocatePortBlock(a); port != 0 {
+		t.Fatalf("expected no port without a config manager, got %d", port)
+	}
+	m.SetConfigManager(configManager)
+
+	gitCmd(t, repoRoot, "worktree", "add", "-q", "-b", "a", a)
+	if port := m.AllocatePortBlock(a); port != config.PortRangeStart {
+		t.Fatalf("expected a to get %d, got %d", config.PortRangeStart, port)
+	}
+	if port := m.AllocatePortBlock(b); port != config.PortRangeStart+config.PortBlockSize {
+		t.Fatalf("expected b to get the next block, got %d", port)
+	}
+	// Paths are keyed in their clean absolute form
+	if port := m.PortBlock(filepath.Join(repoRoot, ".workspaces", "x", "..", "a")); port != config.PortRangeStart {
+		t.Errorf("expected the block of a for an unclean path, got %d", port)
+	}
+
+	if err := m.Remove(a, false); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if port := m.PortBlock(a); port != 0 {
+		t.Errorf("expected the block of a removed worktree to be released, got %d", port)
+	}
+	if port := m.AllocatePortBlock(filepath.Join(repoRoot, ".workspaces", "c")); port != config.PortRangeStart {
+		t.Errorf("expected the released block to be reused, got %d", port)
+	}
+}
@@ -0,0 +1,188 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RebaseBranch rebases the branch checked out in the worktree onto another branch or ref
+// On conflicts the rebase is left in progress and an ErrMergeConflict error is returned,
+// so it can be finished with ContinueRebase or rolled back with AbortRebase
+func (m *Manager) RebaseBranch(worktreePath, onto string) error {
+	// Check if the target exists
+	if _, err := m.run(worktreePath, "rev-parse", "--verify", onto); err != nil {
+		return fmt.Errorf("base branch '%s' does not exist", onto)
+	}
+
+	if _, err := m.run(worktreePath, "rebase", onto); err != nil {
+		return rebaseError(err)
+	}
+	return nil
+}
+
+// ContinueRebase stages the resolved files and continues a rebase stopped on conflicts
+// Returns an ErrMergeConflict error if the next commit conflicts too
+func (m *Manager) ContinueRebase(worktreePath string) error {
+	if !m.IsRebaseInProgress(worktreePath) {
+		return fmt.Errorf("no rebase in progress")
+	}
+
+	// Conflicted files count as resolved once their conflict markers are gone
+	conflicts, err := m.Conflicts(worktreePath)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		paths = append(paths, conflict.Path)
+	}
+	if unresolved := filesWithConflictMarkers(worktreePath, paths); len(unresolved) > 0 {
+		return fmt.Errorf("%w: resolve %s first", ErrMergeConflict, strings.Join(unresolved, ", "))
+	}
+
+	if err := m.stageResolved(worktreePath, paths); err != nil {
+		return err
+	}
+
+	// An empty commit after resolving (changes already in base) is skipped like git would suggest
+	if _, err := m.run(worktreePath, "rebase", "--continue"); err != nil {
+		if errors.Is(err, ErrMergeConflict) {
+			return rebaseError(err)
+		}
+		if !m.hasStagedChanges(worktreePath) {
+			if _, err := m.run(worktreePath, "rebase", "--skip"); err != nil {
+				return rebaseError(err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to continue rebase: %w", err)
+	}
+	return nil
+}
+
+// AbortRebase aborts a rebase in progress, restoring the branch to where it was
+func (m *Manager) AbortRebase(worktreePath string) error {
+	if _, err := m.run(worktreePath, "rebase", "--abort"); err != nil {
+		return fmt.Errorf("failed to abort rebase: %w", err)
+	}
+	return nil
+}
+
+// IsRebaseInProgress checks if the worktree is in the middle of a rebase
+func (m *Manager) IsRebaseInProgress(worktreePath string) bool {
+	return m.rebaseDir(worktreePath) != ""
+}
+
+// RebasingBranch returns the branch being rebased in the worktree (HEAD is detached meanwhile),
+// or "" if no rebase is in progress
+func (m *Manager) RebasingBranch(worktreePath string) string {
+	dir := m.rebaseDir(worktreePath)
+	if dir == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "head-name"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(data)), "refs/heads/")
+}
+
+// rebaseDir returns the state directory of the rebase in progress, "" if there is none
+func (m *Manager) rebaseDir(worktreePath string) string {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		res, err := m.run(worktreePath, "rev-parse", "--git-path", dir)
+		if err != nil {
+			continue
+		}
+		path := res.Trimmed()
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(worktreePath, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// filesWithConflictMarkers returns the files that still contain conflict markers
+func filesWithConflictMarkers(worktreePath string, files []string) []string {
+	var unresolved []string
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(worktreePath, file))
+		if err != nil {
+			// Deleted files (e.g. "deleted by them") have no markers
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+				unresolved = append(unresolved, file)
+				break
+			}
+		}
+	}
+	return unresolved
+}
+
+// HasUpstream checks if the branch checked out in the worktree tracks a remote branch
+func (m *Manager) HasUpstream(worktreePath string) bool {
+	res, err := m.run(worktreePath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	return err == nil && res.Trimmed() != ""
+}
+
+// RemoteBranchHead returns the commit of the branch on the push remote as last fetched, "" if
+// it was never pushed. Taken before fetching and rewriting the branch, it is the lease of
+// ForcePushWithLease.
+func (m *Manager) RemoteBranchHead(worktreePath, branch string) string {
+	res, err := m.run(worktreePath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+m.PushRemote()+"/"+branch)
+	if err != nil {
+		return ""
+	}
+	return res.Trimmed()
+}
+
+// ForcePushWithLease pushes a rewritten branch, refusing to overwrite commits on the remote
+// that are not part of it. lease is the commit the remote branch was at before the rewrite
+// (see RemoteBranchHead): a fetch since then may have brought in commits pushed by someone
+// else, a bare --force-with-lease would compare against them and overwrite them. Without a
+// lease, --force-if-includes has git check that the remote-tracking branch was integrated.
+func (m *Manager) ForcePushWithLease(worktreePath, branch, lease string) error {
+	remote := m.PushRemote()
+	if !m.hasRemote(worktreePath, remote) {
+		return fmt.Errorf("%w: '%s' does not exist", ErrNoRemote, remote)
+	}
+
+	args := []string{"push", "--force-with-lease", "--force-if-includes", remote, branch}
+	if lease != "" {
+		if current := m.RemoteBranchHead(worktreePath, branch); current != "" && current != lease {
+			// Pushed to since the lease was taken, only fine if those commits are in the branch now
+			if _, err := m.run(worktreePath, "merge-base", "--is-ancestor", current, "HEAD"); err != nil {
+				return fmt.Errorf("failed to push: %s/%s has commits the branch doesn't include, pull them first", remote, branch)
+			}
+			lease = current
+		}
+		args = []string{"push", fmt.Sprintf("--force-with-lease=%s:%s", branch, lease), remote, branch}
+	}
+
+	if _, err := m.run(worktreePath, args...); err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+	return nil
+}
+
+// hasStagedChanges checks if the index differs from HEAD
+func (m *Manager) hasStagedChanges(worktreePath string) bool {
+	_, err := m.run(worktreePath, "diff", "--cached", "--quiet")
+	return err != nil
+}
+
+// rebaseError wraps a failed rebase, turning conflicts into ErrMergeConflict
+func rebaseError(err error) error {
+	if errors.Is(err, ErrMergeConflict) {
+		return fmt.Errorf("%w occurred while rebasing. Resolve the conflicts, then continue or abort the rebase", ErrMergeConflict)
+	}
+	return fmt.Errorf("failed to rebase: %w", err)
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRebaseBranch_ConflictIsErrMergeConflict(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.OnError(1, "CONFLICT (content): Merge conflict in a.go\nerror: could not apply 1234567... feat\n", "rebase", "origin/main")
+
+	m := NewManagerWithRunner("/repo", runner)
+	if err := m.RebaseBranch("/repo/.workspaces/feat", "origin/main"); !errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected ErrMergeConflict, got %v", err)
+	}
+}
+
+func TestFilesWithConflictMarkers(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"conflicted.go": "package a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feat\n",
+		"resolved.go":   "package a\nours and theirs\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	unresolved := filesWithConflictMarkers(dir, []string{"conflicted.go", "resolved.go", "deleted.go"})
+	if len(unresolved) != 1 || unresolved[0] != "conflicted.go" {
+		t.Errorf("expected only conflicted.go, got %v", unresolved)
+	}
+}
+
+func TestForcePushWithLease(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("git@github.com:me/app.git\n", "remote", "get-url", "origin")
+	runner.On("aaa\n", "rev-parse", "--verify", "--quiet", "refs/remotes/origin/feat")
+	m := NewManagerWithRunner("/repo", runner)
+
+	// The remote branch is where it was before the fetch, the lease is explicit
+	if err := m.ForcePushWithLease("/repo", "feat", "aaa"); err != nil {
+		t.Fatalf("ForcePushWithLease: %v", err)
+	}
+	if !runner.Ran("push", "--force-with-lease=feat:aaa", "origin", "feat") {
+		t.Error("expected the push to lease the commit taken before the fetch")
+	}
+
+	// The fetch brought in a commit someone else pushed, which the rebased branch doesn't have
+	runner.On("bbb\n", "rev-parse", "--verify", "--quiet", "refs/remotes/origin/feat")
+	runner.OnError(1, "", "merge-base", "--is-ancestor", "bbb", "HEAD")
+	if err := m.ForcePushWithLease("/repo", "feat", "aaa"); err == nil {
+		t.Fatal("expected the push to be refused")
+	}
+	if runner.Ran("push", "--force-with-lease=feat:bbb", "origin", "feat") {
+		t.Error("the commits fetched since the lease must not be overwritten")
+	}
+
+	// Without a lease git checks the remote-tracking branch was integrated locally
+	if err := m.ForcePushWithLease("/repo", "feat", ""); err != nil {
+		t.Fatalf("ForcePushWithLease: %v", err)
+	}
+	if !runner.Ran("push", "--force-with-lease", "--force-if-includes", "origin", "feat") {
+		t.Error("expected --force-if-includes without a lease")
+	}
+}
+
+func TestContinueRebase_StagesOnlyResolvedFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "rebase-merge"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\nours and theirs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewFakeRunner()
+	runner.On(filepath.Join(dir, "rebase-merge")+"\n", "rev-parse", "--git-path", "rebase-merge")
+	runner.On("UU a.go\x00 M notes.txt\x00?? scratch.txt\x00", "status", "--porcelain", "-z")
+
+	m := NewManagerWithRunner(dir, runner)
+	if err := m.ContinueRebase(dir); err != nil {
+		t.Fatalf("ContinueRebase: %v", err)
+	}
+	for _, call := range runner.Calls() {
+		if len(call.Args) > 0 && call.Args[0] == "add" && !reflect.DeepEqual(call.Args, []string{"add", "-A", "--", "a.go"}) {
+			t.Errorf("expected only the resolved file to be staged, got git %v", call.Args)
+		}
+	}
+	if !runner.Ran("add", "-A", "--", "a.go") || !runner.Ran("rebase", "--continue") {
+		t.Error("expected the resolved file to be staged and the rebase continued")
+	}
+}
@@ -0,0 +1,161 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRemote is the remote branches are pushed to and the base branch comes from, unless
+// the repository is configured otherwise (see config.Manager.SetRemotes)
+const DefaultRemote = "origin"
+
+// Remotes returns the remote branches are pushed to and the remote of the base branch. In a
+// fork-based workflow they differ, e.g. "fork" and "upstream".
+func (m *Manager) Remotes() (push, base string) {
+	push, base = DefaultRemote, DefaultRemote
+	if m.configManager == nil {
+		return push, base
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return push, base
+	}
+	configuredPush, configuredBase := m.configManager.GetRemotes(repoRoot)
+	if configuredPush != "" {
+		push = configuredPush
+	}
+	if configuredBase != "" {
+		base = configuredBase
+	}
+	return push, base
+}
+
+// PushRemote returns the remote branches are pushed to
+func (m *Manager) PushRemote() string {
+	push, _ := m.Remotes()
+	return push
+}
+
+// BaseRemote returns the remote the base branch is fetched from and PRs are opened against
+func (m *Manager) BaseRemote() string {
+	_, base := m.Remotes()
+	return base
+}
+
+// ListRemotes returns the names of the repository's remotes
+func (m *Manager) ListRemotes() ([]string, error) {
+	res, err := m.run(m.repoPath, "remote")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	return strings.Fields(res.Stdout), nil
+}
+
+// BaseRef returns the base branch on the base remote (e.g. upstream/main) when it was fetched,
+// so updates use the latest base rather than a stale local copy, or else baseBranch itself
+func (m *Manager) BaseRef(worktreePath, baseBranch string) string {
+	base := m.BaseRemote()
+	if strings.HasPrefix(baseBranch, base+"/") {
+		return baseBranch
+	}
+	if m.remoteBranchExists(worktreePath, base, baseBranch) {
+		return base + "/" + baseBranch
+	}
+	return baseBranch
+}
+
+// PRRepos returns what a PR needs to be opened from a fork: the repository it targets
+// (owner/name of the base remote) and the owner of the fork the branch was pushed to.
+// Both are "" when branches are pushed to the base remote.
+func (m *Manager) PRRepos() (baseRepo, headOwner string, err error) {
+	push, base := m.Remotes()
+	if push == base {
+		return "", "", nil
+	}
+
+	baseURL, err := m.remoteURL(base)
+	if err != nil {
+		return "", "", err
+	}
+	pushURL, err := m.remoteURL(push)
+	if err != nil {
+		return "", "", err
+	}
+	baseRepo, ok := repoFromURL(baseURL)
+	if !ok {
+		return "", "", fmt.Errorf("failed to find the repository of remote '%s' in %s", base, baseURL)
+	}
+	pushRepo, ok := repoFromURL(pushURL)
+	if !ok {
+		return "", "", fmt.Errorf("failed to find the repository of remote '%s' in %s", push, pushURL)
+	}
+	headOwner, _, _ = strings.Cut(pushRepo, "/")
+	return baseRepo, headOwner, nil
+}
+
+// repoFromURL returns the owner/name of a GitHub remote URL, in any of the
+// git@github.com:owner/name.git, ssh://git@github.com/owner/name.git and
+// https://github.com/owner/name forms
+func repoFromURL(url string) (string, bool) {
+	url = convertSSHToHTTPS(strings.TrimSuffix(url, "/"))
+	parts := strings.Split(url, "/")
+	if len(parts) < 2 {
+		return "", false
+	}
+	owner, name := parts[len(parts)-2], parts[len(parts)-1]
+	if owner == "" || name == "" || strings.Contains(owner, ":") {
+		return "", false
+	}
+	return owner + "/" + name, true
+}
+
+// LocalBranchName returns the local branch name of a branch reference: "feature" for a remote
+// tracking branch like upstream/feature, the branch as-is otherwise
+func (m *Manager) LocalBranchName(branch string) string {
+	_, local, _ := m.splitRemoteBranch(branch)
+	return local
+}
+
+// splitRemoteBranch splits a remote tracking branch like upstream/feature into the remote and
+// the local branch name, ok is false for local branches
+func (m *Manager) splitRemoteBranch(branch string) (remote, local string, ok bool) {
+	remotes, err := m.ListRemotes()
+	if err != nil {
+		return "", branch, false
+	}
+	for _, name := range remotes {
+		// The longest match wins, remote names may contain slashes
+		if strings.HasPrefix(branch, name+"/") && len(name) > len(remote) {
+			remote = name
+		}
+	}
+	if remote == "" {
+		return "", branch, false
+	}
+	return remote, strings.TrimPrefix(branch, remote+"/"), true
+}
+
+// remoteURL returns the URL of a remote
+func (m *Manager) remoteURL(remote string) (string, error) {
+	res, err := m.run(m.repoPath, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote '%s': %w", remote, err)
+	}
+	return res.Trimmed(), nil
+}
+
+// remoteBranchExists checks if the remote-tracking branch <remote>/<branch> was fetched
+func (m *Manager) remoteBranchExists(dir, remote, branch string) bool {
+	_, err := m.run(dir, "rev-parse", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch)
+	return err == nil
+}
+
+// pullRemote returns the remote a branch is pulled from: the push remote once the branch was
+// pushed there, the base remote otherwise (the base branch itself in a fork-based workflow)
+func (m *Manager) pullRemote(dir, branch string) string {
+	push, base := m.Remotes()
+	if push == base || m.remoteBranchExists(dir, push, branch) {
+		return push
+	}
+	return base
+}
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+)
+
+func TestRemotes_ForkWorkflow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	if err := configManager.SetRemotes("/repo", "fork", "upstream"); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	runner.On("git@github.com:me/jean.git\n", "remote", "get-url", "fork")
+	runner.On("https://github.com/coollabsio/jean.git\n", "remote", "get-url", "upstream")
+	runner.OnError(1, "", "rev-parse", "--verify", "--quiet", "refs/remotes/fork/main")
+	m := NewManagerWithRunner("/repo", runner)
+	m.SetConfigManager(configManager)
+
+	if err := m.Push("/repo", "feature"); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if !runner.Ran("push", "-u", "fork", "feature") {
+		t.Error("expected the branch to be pushed to the fork")
+	}
+
+	if err := m.FetchRemote(); err != nil {
+		t.Fatalf("FetchRemote: %v", err)
+	}
+	if !runner.Ran("fetch", "upstream") || !runner.Ran("fetch", "fork") {
+		t.Error("expected both remotes to be fetched")
+	}
+
+	// The base branch only lives upstream, pushed branches are pulled from the fork
+	if _, err := m.PullBranch("/repo", "main"); err != nil {
+		t.Fatalf("PullBranch: %v", err)
+	}
+	if !runner.Ran("pull", "upstream", "main") {
+		t.Error("expected the base branch to be pulled from upstream")
+	}
+	if _, err := m.PullBranch("/repo", "feature"); err != nil {
+		t.Fatalf("PullBranch: %v", err)
+	}
+	if !runner.Ran("pull", "fork", "feature") {
+		t.Error("expected a pushed branch to be pulled from the fork")
+	}
+
+	repo, headOwner, err := m.PRRepos()
+	if err != nil {
+		t.Fatalf("PRRepos: %v", err)
+	}
+	if repo != "coollabsio/jean" || headOwner != "me" {
+		t.Errorf("expected a PR from me against coollabsio/jean, got %q and %q", headOwner, repo)
+	}
+
+	// Without a configuration both are origin and PRs need nothing more
+	m = NewManagerWithRunner("/repo", runner)
+	if push, base := m.Remotes(); push != DefaultRemote || base != DefaultRemote {
+		t.Errorf("expected origin for both remotes, got %q and %q", push, base)
+	}
+	if repo, headOwner, _ := m.PRRepos(); repo != "" || headOwner != "" {
+		t.Errorf("expected no PR repositories, got %q and %q", repo, headOwner)
+	}
+}
+
+func TestCreateWithOptions_RemoteBranch(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+	runner.On("fork\nupstream\n", "remote")
+	runner.On("refs/heads/main\nrefs/remotes/upstream/HEAD\nrefs/remotes/upstream/main\nrefs/remotes/fork/feature\n",
+		"branch", "-a", "--format=%(refname)")
+	runner.OnError(128, "fatal: Needed a single revision", "rev-parse", "--verify", "feature")
+	m := NewManagerWithRunner("/repo", runner)
+
+	branches, err := m.ListBranches()
+	if err != nil {
+		t.Fatalf("ListBranches: %v", err)
+	}
+	if want := []string{"main", "upstream/main", "fork/feature"}; !reflect.DeepEqual(branches, want) {
+		t.Errorf("expected branches %v, got %v", want, branches)
+	}
+
+	path, err := m.GetDefaultPath("fork/feature")
+	if err != nil {
+		t.Fatalf("GetDefaultPath: %v", err)
+	}
+	if path != filepath.Join("/repo", DefaultWorktreeRoot, "feature") {
+		t.Errorf("expected the remote to be left out of the directory, got %s", path)
+	}
+	if err := m.CreateWithOptions(path, "fork/feature", false, "", CreateOptions{}); err != nil {
+		t.Fatalf("CreateWithOptions: %v", err)
+	}
+	if !runner.Ran("worktree", "add", "--track", "-b", "feature", path, "fork/feature") {
+		t.Error("expected a local branch tracking fork/feature")
+	}
+}
+
+func TestCreateWithOptions_RemoteBranchWithExistingLocalBranch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	if err := configManager.SetWorktreeLocation("/src/app", "../trees", "{repo}-{branch}"); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("/src/app\n", "rev-parse", "--show-toplevel")
+	runner.On("origin\n", "remote")
+	m := NewManagerWithRunner("/src/app", runner)
+	m.SetConfigManager(configManager)
+
+	// feature is already checked out locally, the new worktree gets a unique branch
+	path, err := m.GetDefaultPath("origin/feature")
+	if err != nil {
+		t.Fatalf("GetDefaultPath: %v", err)
+	}
+	if err := m.CreateWithOptions(path, "origin/feature", false, "", CreateOptions{}); err != nil {
+		t.Fatalf("CreateWithOptions: %v", err)
+	}
+	for _, call := range runner.Calls() {
+		if len(call.Args) < 2 || call.Args[0] != "worktree" || call.Args[1] != "add" {
+			continue
+		}
+		branch, dir := call.Args[len(call.Args)-3], call.Args[len(call.Args)-2]
+		if !strings.HasPrefix(branch, "feature-") || dir != "/src/trees/app-"+branch {
+			t.Errorf("expected a unique branch in the configured location, got %s in %s", branch, dir)
+		}
+		return
+	}
+	t.Fatal("expected a worktree to be added")
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Errors that git failures are classified into. Match them with errors.Is.
+var (
+	ErrMergeConflict = errors.New("merge conflict")
+	ErrNoRemote      = errors.New("no remote configured")
+	ErrBranchExists  = errors.New("branch already exists")
+)
+
+// Runner executes git commands. The default runner shells out to the git binary;
+// FakeRunner lets Manager be unit-tested without a real repository.
+type Runner interface {
+	// Run runs `git -C dir args...`. A non-zero exit returns a *CommandError.
+	Run(ctx context.Context, dir string, args ...string) (Result, error)
+}
+
+// Result holds the captured output of a git command
+type Result struct {
+	Stdout string
+	Stderr string
+}
+
+// Combined returns stdout followed by stderr, like a terminal would show them
+func (r Result) Combined() string {
+	if r.Stdout == "" || r.Stderr == "" {
+		return r.Stdout + r.Stderr
+	}
+	return strings.TrimRight(r.Stdout, "\n") + "\n" + r.Stderr
+}
+
+// Trimmed returns stdout without surrounding whitespace
+func (r Result) Trimmed() string {
+	return strings.TrimSpace(r.Stdout)
+}
+
+// CommandError is returned when a git command fails
+type CommandError struct {
+	Args     []string
+	Dir      string
+	ExitCode int // -1 if git could not be started or was killed
+	Result
+	Err error
+}
+
+// Error returns git's own message (stderr, or stdout when stderr is empty)
+func (e *CommandError) Error() string {
+	if output := strings.TrimSpace(e.Combined()); output != "" {
+		return output
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return "git " + strings.Join(e.Args, " ") + " failed"
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Is classifies the failure from git's output so callers can use errors.Is with
+// ErrMergeConflict, ErrNoRemote and ErrBranchExists
+func (e *CommandError) Is(target error) bool {
+	output := e.Combined()
+	switch target {
+	case ErrMergeConflict:
+		return strings.Contains(output, "CONFLICT") || strings.Contains(output, "Automatic merge failed")
+	case ErrNoRemote:
+		return strings.Contains(output, "No such remote") ||
+			strings.Contains(output, "does not appear to be a git repository") ||
+			strings.Contains(output, "No configured push destination")
+	case ErrBranchExists:
+		return strings.Contains(output, "a branch named") && strings.Contains(output, "already exists")
+	}
+	return false
+}
+
+// execRunner runs the git binary
+type execRunner struct{}
+
+func (execRunner) Run(ctx context.Context, dir string, args ...string) (Result, error) {
+	fullArgs := args
+	if dir != "" {
+		fullArgs = append([]string{"-C", dir}, args...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", fullArgs...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// git runs without a terminal here; never wait on an editor (e.g. for `rebase --continue`)
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+
+	err := cmd.Run()
+	result := Result{Stdout: stdout.String(), Stderr: stderr.String()}
+	if err != nil {
+		exitCode := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return result, &CommandError{Args: args, Dir: dir, ExitCode: exitCode, Result: result, Err: err}
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CreateOptions holds the optional settings of a new worktree
+type CreateOptions struct {
+	SparseProfile string            // Sparse-checkout profile of jean.json to check out, "" for a full checkout
+	Progress      func(step string) // Called before each long step (StepSubmodules, StepLFS), may be nil
+}
+
+// sparseProfile returns the directories of a sparse-checkout profile of jean.json
+func (m *Manager) sparseProfile(name string) ([]string, error) {
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	scriptConfig, err := loadScripts(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	dirs, ok := scriptConfig.SparseProfile(name)
+	if !ok {
+		names := scriptConfig.SparseProfileNames()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown sparse-checkout profile '%s': jean.json has no \"sparse\" profiles", name)
+		}
+		return nil, fmt.Errorf("unknown sparse-checkout profile '%s' (expected one of %s)", name, strings.Join(names, ", "))
+	}
+	return dirs, nil
+}
+
+// checkoutSparse checks out the directories of a worktree added with --no-checkout. Nothing
+// outside them is written to disk, and in a partial clone only their blobs are fetched.
+// The sparse-checkout settings are per worktree, the other worktrees keep their checkout.
+func (m *Manager) checkoutSparse(worktreePath string, dirs []string) error {
+	args := append([]string{"sparse-checkout", "set", "--cone"}, dirs...)
+	if _, err := m.run(worktreePath, args...); err != nil {
+		return fmt.Errorf("failed to set up sparse checkout: %w", err)
+	}
+	// --no-checkout left the index empty, fill it and the working tree within the cone
+	if _, err := m.run(worktreePath, "read-tree", "-mu", "HEAD"); err != nil {
+		return fmt.Errorf("failed to check out worktree: %w", err)
+	}
+	return nil
+}
+
+// SparseCheckoutDirs returns the directories checked out in a sparse worktree,
+// nil if the worktree has a full checkout
+func (m *Manager) SparseCheckoutDirs(worktreePath string) []string {
+	return m.sparseCheckoutDirs(context.Background(), worktreePath)
+}
+
+// sparseCheckoutDirs is SparseCheckoutDirs with cancellation
+func (m *Manager) sparseCheckoutDirs(ctx context.Context, worktreePath string) []string {
+	// Fails with "this worktree is not sparse" for a full checkout
+	res, err := m.runContext(ctx, worktreePath, "sparse-checkout", "list")
+	if err != nil || res.Trimmed() == "" {
+		return nil
+	}
+	return strings.Split(res.Trimmed(), "\n")
+}
+
+// WidenSparseCheckout adds the directories of a sparse-checkout profile to a sparse worktree
+func (m *Manager) WidenSparseCheckout(worktreePath, profile string) error {
+	dirs, err := m.sparseProfile(profile)
+	if err != nil {
+		return err
+	}
+	args := append([]string{"sparse-checkout", "add"}, dirs...)
+	if _, err := m.run(worktreePath, args...); err != nil {
+		return fmt.Errorf("failed to widen sparse checkout: %w", err)
+	}
+	return nil
+}
+
+// DisableSparseCheckout turns a sparse worktree into a full checkout
+func (m *Manager) DisableSparseCheckout(worktreePath string) error {
+	if _, err := m.run(worktreePath, "sparse-checkout", "disable"); err != nil {
+		return fmt.Errorf("failed to disable sparse checkout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateWithOptions_SparseProfile(t *testing.T) {
+	repoRoot := t.TempDir()
+	jeanJSON := `{"sparse": {"web": ["packages/web", "packages/shared"]}}`
+	if err := os.WriteFile(filepath.Join(repoRoot, "jean.json"), []byte(jeanJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	m := NewManagerWithRunner(repoRoot, runner)
+
+	path := filepath.Join(repoRoot, ".workspaces", "feature")
+	if err := m.CreateWithOptions(path, "feature", true, "", CreateOptions{SparseProfile: "web"}); err != nil {
+		t.Fatalf("CreateWithOptions: %v", err)
+	}
+	if !runner.Ran("worktree", "add", "--no-checkout", "-b", "feature", path) {
+		t.Error("expected the worktree to be added without a checkout")
+	}
+	if !runner.Ran("sparse-checkout", "set", "--cone", "packages/web", "packages/shared") {
+		t.Error("expected the profile's directories to be set as the sparse-checkout cone")
+	}
+	if !runner.Ran("read-tree", "-mu", "HEAD") {
+		t.Error("expected the cone to be checked out")
+	}
+
+	err := m.CreateWithOptions(path, "other", true, "", CreateOptions{SparseProfile: "api"})
+	if err == nil || !strings.Contains(err.Error(), "expected one of web") {
+		t.Fatalf("expected an unknown profile error, got %v", err)
+	}
+}
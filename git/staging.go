@@ -0,0 +1,199 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Hunk is one "@@" section of a file diff
+type Hunk struct {
+	Header string   // e.g. "@@ -10,7 +10,8 @@ func main() {"
+	Lines  []string // Context, added and removed lines
+}
+
+// ChangedFile is a file with uncommitted changes relative to HEAD
+type ChangedFile struct {
+	Path   string
+	Status string // "M" modified, "A" added, "D" deleted, "?" untracked
+	Binary bool
+	Hunks  []Hunk
+
+	header []string // "diff --git", "index", "---" and "+++" lines, to rebuild a partial patch
+}
+
+// HunkSelectable returns true if single hunks of the file can be staged.
+// New, deleted, untracked and binary files are always staged as a whole.
+func (f ChangedFile) HunkSelectable() bool {
+	return f.Status == "M" && !f.Binary && len(f.Hunks) > 0
+}
+
+// Selection picks what of a changed file goes into the next commit
+type Selection struct {
+	File  ChangedFile
+	Hunks []int // Indexes into File.Hunks, nil for the whole file
+}
+
+// Changes returns the uncommitted changes of the worktree (staged or not) relative to HEAD,
+// followed by the untracked files
+func (m *Manager) Changes(worktreePath string) ([]ChangedFile, error) {
+	files, err := m.diffFiles(worktreePath, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := m.run(worktreePath, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, path := range strings.Split(untracked.Stdout, "\x00") {
+		if path != "" {
+			files = append(files, ChangedFile{Path: path, Status: "?"})
+		}
+	}
+	return files, nil
+}
+
+// diffFiles returns the changes of the working tree relative to rev, by file. The output format
+// is pinned so user settings (external diff tools, prefixes, colors) can't break parsing.
+func (m *Manager) diffFiles(worktreePath, rev string) ([]ChangedFile, error) {
+	res, err := m.run(worktreePath, "-c", "core.quotepath=false", "diff", rev,
+		"--no-renames", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %w", err)
+	}
+	return parseDiff(res.Stdout), nil
+}
+
+// parseDiff splits `git diff` output into files and hunks
+func parseDiff(output string) []ChangedFile {
+	var files []ChangedFile
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			file := ChangedFile{Status: "M", header: []string{line}}
+			// Fallback for diffs without ---/+++ lines (binary files, mode changes)
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				file.Path = line[i+len(" b/"):]
+			}
+			files = append(files, file)
+			continue
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		file := &files[len(files)-1]
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			file.Hunks = append(file.Hunks, Hunk{Header: line})
+		case len(file.Hunks) > 0:
+			hunk := &file.Hunks[len(file.Hunks)-1]
+			hunk.Lines = append(hunk.Lines, line)
+		default:
+			// Header lines before the first hunk
+			file.header = append(file.header, line)
+			switch {
+			case strings.HasPrefix(line, "new file mode"):
+				file.Status = "A"
+			case strings.HasPrefix(line, "deleted file mode"):
+				file.Status = "D"
+			case strings.HasPrefix(line, "Binary files"):
+				file.Binary = true
+			case strings.HasPrefix(line, "+++ b/"):
+				// git appends a tab to names with spaces
+				file.Path = strings.TrimSuffix(strings.TrimPrefix(line, "+++ b/"), "\t")
+			case strings.HasPrefix(line, "--- a/") && file.Status == "D":
+				file.Path = strings.TrimSuffix(strings.TrimPrefix(line, "--- a/"), "\t")
+			}
+		}
+	}
+	return files
+}
+
+// patch rebuilds the diff of the file with only the given hunks
+func (f ChangedFile) patch(hunks []int) string {
+	var b strings.Builder
+	for _, line := range f.header {
+		b.WriteString(line + "\n")
+	}
+	for _, i := range hunks {
+		b.WriteString(f.Hunks[i].Header + "\n")
+		for _, line := range f.Hunks[i].Lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// StageSelection replaces the staged changes with the selected files and hunks, so CommitStaged
+// commits only those. The working tree is left untouched.
+func (m *Manager) StageSelection(worktreePath string, selections []Selection) error {
+	// Resetting the index would also drop the state of a merge in progress
+	if m.IsMergeInProgress(worktreePath) {
+		return fmt.Errorf("a merge is in progress, it has to be committed as a whole")
+	}
+
+	if _, err := m.run(worktreePath, "reset", "-q"); err != nil {
+		return fmt.Errorf("failed to reset staged changes: %w", err)
+	}
+
+	for _, selection := range selections {
+		file := selection.File
+		if selection.Hunks == nil || !file.HunkSelectable() || len(selection.Hunks) == len(file.Hunks) {
+			if _, err := m.run(worktreePath, "add", "-A", "--", file.Path); err != nil {
+				return fmt.Errorf("failed to stage %s: %w", file.Path, err)
+			}
+			continue
+		}
+		if len(selection.Hunks) == 0 {
+			continue
+		}
+		if err := m.applyToIndex(worktreePath, file.patch(selection.Hunks)); err != nil {
+			return fmt.Errorf("failed to stage hunks of %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
+
+// applyToIndex applies a patch to the index only (git apply --cached)
+func (m *Manager) applyToIndex(worktreePath, patch string) error {
+	tmp, err := os.CreateTemp("", "jean-stage-*.patch")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(patch); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// --recount: hunks are taken out of a larger diff, so the new-side line numbers are off
+	_, err = m.run(worktreePath, "apply", "--cached", "--recount", tmp.Name())
+	return err
+}
+
+// GetStagedStatus returns the names and statuses of the staged files (git diff --cached --name-status)
+func (m *Manager) GetStagedStatus(worktreePath string) (string, error) {
+	res, err := m.run(worktreePath, "diff", "--cached", "--name-status")
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged files: %w", err)
+	}
+	return res.Stdout, nil
+}
+
+// GetStagedDiff returns the diff of the staged changes only, as context for AI-generated commit
+// messages of a partial commit
+func (m *Manager) GetStagedDiff(worktreePath string) (string, error) {
+	res, err := m.run(worktreePath, "diff", "--cached", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged diff: %w", err)
+	}
+	if res.Stdout == "" {
+		return "", fmt.Errorf("no changes staged")
+	}
+	return res.Stdout, nil
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+const stagingDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var a = 1
++var a = 2
+ 
+@@ -20,3 +20,4 @@ func main() {
+ 	run()
++	debug()
+ }
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 3333333..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+diff --git a/logo.png b/logo.png
+index 4444444..5555555 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParseDiff_FilesAndHunks(t *testing.T) {
+	files := parseDiff(stagingDiff)
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %+v", files)
+	}
+
+	if files[0].Path != "main.go" || files[0].Status != "M" || len(files[0].Hunks) != 2 || !files[0].HunkSelectable() {
+		t.Errorf("unexpected main.go entry %+v", files[0])
+	}
+	if got := files[0].Hunks[1].Lines; len(got) != 3 || got[1] != "+\tdebug()" {
+		t.Errorf("unexpected second hunk lines %q", got)
+	}
+	if files[1].Path != "old.txt" || files[1].Status != "D" || files[1].HunkSelectable() {
+		t.Errorf("unexpected old.txt entry %+v", files[1])
+	}
+	if files[2].Path != "logo.png" || !files[2].Binary || files[2].HunkSelectable() {
+		t.Errorf("unexpected logo.png entry %+v", files[2])
+	}
+}
+
+func TestPatch_OnlySelectedHunks(t *testing.T) {
+	file := parseDiff(stagingDiff)[0]
+
+	patch := file.patch([]int{1})
+	if !strings.HasPrefix(patch, "diff --git a/main.go b/main.go\nindex 1111111..2222222 100644\n--- a/main.go\n+++ b/main.go\n@@ -20,3 +20,4 @@") {
+		t.Errorf("unexpected patch header:\n%s", patch)
+	}
+	if strings.Contains(patch, "var a = 2") {
+		t.Errorf("patch contains an unselected hunk:\n%s", patch)
+	}
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Stash is an entry of `git stash list`. Stashes are shared by all worktrees of a repository,
+// Branch tells which one a stash was made on.
+type Stash struct {
+	Ref     string // e.g. "stash@{0}"
+	Branch  string // Branch the stash was created on, "" for a detached HEAD
+	Message string
+	Date    string // Relative date, e.g. "2 hours ago"
+}
+
+// ListStashes returns the stashes of the repository, newest first
+func (m *Manager) ListStashes(worktreePath string) ([]Stash, error) {
+	res, err := m.run(worktreePath, "stash", "list", "--format=%gd%x1f%gs%x1f%cr")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	return parseStashes(res.Stdout), nil
+}
+
+// parseStashes parses `git stash list --format=%gd%x1f%gs%x1f%cr` output
+func parseStashes(output string) []Stash {
+	var stashes []Stash
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 3 {
+			continue
+		}
+
+		// The subject is "WIP on <branch>: <commit>" or "On <branch>: <message>"
+		stash := Stash{Ref: fields[0], Message: fields[1], Date: fields[2]}
+		subject := strings.TrimPrefix(strings.TrimPrefix(fields[1], "WIP on "), "On ")
+		if branch, message, ok := strings.Cut(subject, ": "); ok {
+			if branch != "(no branch)" {
+				stash.Branch = branch
+			}
+			stash.Message = message
+		}
+		stashes = append(stashes, stash)
+	}
+	return stashes
+}
+
+// PushStash stashes the uncommitted changes of the worktree, including untracked files
+// Returns false if there was nothing to stash
+func (m *Manager) PushStash(worktreePath, message string) (bool, error) {
+	args := []string{"stash", "push", "--include-untracked"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+
+	res, err := m.run(worktreePath, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to stash changes: %w", err)
+	}
+	return !strings.Contains(res.Combined(), "No local changes to save"), nil
+}
+
+// PopStash applies a stash to the worktree and drops it
+// On conflicts git keeps the stash and an ErrMergeConflict error is returned
+func (m *Manager) PopStash(worktreePath, ref string) error {
+	if _, err := m.run(worktreePath, "stash", "pop", ref); err != nil {
+		return stashError("pop", ref, err)
+	}
+	return nil
+}
+
+// ApplyStash applies a stash to the worktree and keeps it
+func (m *Manager) ApplyStash(worktreePath, ref string) error {
+	if _, err := m.run(worktreePath, "stash", "apply", ref); err != nil {
+		return stashError("apply", ref, err)
+	}
+	return nil
+}
+
+// DropStash deletes a stash
+func (m *Manager) DropStash(worktreePath, ref string) error {
+	if _, err := m.run(worktreePath, "stash", "drop", ref); err != nil {
+		return fmt.Errorf("failed to drop %s: %w", ref, err)
+	}
+	return nil
+}
+
+// stashError wraps a failed pop/apply, turning conflicts into ErrMergeConflict
+func stashError(action, ref string, err error) error {
+	if errors.Is(err, ErrMergeConflict) {
+		return fmt.Errorf("%w while applying %s. Resolve the conflicts, the stash was kept", ErrMergeConflict, ref)
+	}
+	return fmt.Errorf("failed to %s %s: %w", action, ref, err)
+}
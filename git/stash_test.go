@@ -0,0 +1,38 @@
+package git
+
+import "testing"
+
+func TestParseStashes_BranchAndMessage(t *testing.T) {
+	output := "stash@{0}\x1fOn feature/x: half-done refactor\x1f5 minutes ago\n" +
+		"stash@{1}\x1fWIP on main: abc1234 Fix typo\x1f2 days ago\n" +
+		"stash@{2}\x1fWIP on (no branch): def5678 Detached work\x1f3 weeks ago\n"
+
+	stashes := parseStashes(output)
+	want := []Stash{
+		{Ref: "stash@{0}", Branch: "feature/x", Message: "half-done refactor", Date: "5 minutes ago"},
+		{Ref: "stash@{1}", Branch: "main", Message: "abc1234 Fix typo", Date: "2 days ago"},
+		{Ref: "stash@{2}", Branch: "", Message: "def5678 Detached work", Date: "3 weeks ago"},
+	}
+	if len(stashes) != len(want) {
+		t.Fatalf("expected %d stashes, got %+v", len(want), stashes)
+	}
+	for i := range want {
+		if stashes[i] != want[i] {
+			t.Errorf("stash %d: expected %+v, got %+v", i, want[i], stashes[i])
+		}
+	}
+}
+
+func TestPushStash_NothingToStash(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("No local changes to save\n", "stash", "push")
+
+	m := NewManagerWithRunner("/repo", runner)
+	stashed, err := m.PushStash("/repo", "")
+	if err != nil {
+		t.Fatalf("PushStash: %v", err)
+	}
+	if stashed {
+		t.Error("expected nothing to be stashed")
+	}
+}
@@ -0,0 +1,152 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusTimeout bounds the git commands run for a single worktree's status
+	StatusTimeout = 10 * time.Second
+	// maxStatusWorkers caps the number of worktrees whose status is computed concurrently
+	maxStatusWorkers = 8
+)
+
+// WorktreeStatus is the status of a worktree relative to the base branch
+type WorktreeStatus struct {
+	Path           string
+	HasUncommitted bool
+	AheadCount     int
+	BehindCount    int
+	SparseDirs     []string // Directories of a sparse checkout, nil for a full checkout
+	Err            error    // Set if the status could not be (fully) computed, e.g. on timeout
+}
+
+// LoadStatuses computes the status of each worktree in a bounded pool of workers and sends
+// every result on the returned channel as soon as it is ready, in completion order.
+// Each worktree gets StatusTimeout; cancelling ctx stops the remaining work.
+// The channel is closed once all workers are done.
+func (m *Manager) LoadStatuses(ctx context.Context, worktrees []Worktree, baseBranch string) <-chan WorktreeStatus {
+	results := make(chan WorktreeStatus, len(worktrees))
+	jobs := make(chan Worktree)
+
+	workers := min(max(runtime.NumCPU(), 2), maxStatusWorkers, max(len(worktrees), 1))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for wt := range jobs {
+				results <- m.WorktreeStatus(ctx, wt, baseBranch)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, wt := range worktrees {
+			select {
+			case jobs <- wt:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// WorktreeStatus computes the uncommitted changes and ahead/behind counts of a single worktree
+// Detached HEADs and an empty base branch only get the uncommitted changes check.
+func (m *Manager) WorktreeStatus(ctx context.Context, wt Worktree, baseBranch string) WorktreeStatus {
+	ctx, cancel := context.WithTimeout(ctx, StatusTimeout)
+	defer cancel()
+
+	status := WorktreeStatus{Path: wt.Path}
+
+	hasUncommitted, err := m.hasUncommittedChanges(ctx, wt.Path)
+	if err != nil {
+		status.Err = statusError(ctx, err)
+		return status
+	}
+	status.HasUncommitted = hasUncommitted
+	status.SparseDirs = m.sparseCheckoutDirs(ctx, wt.Path)
+
+	if baseBranch == "" || wt.Detached {
+		return status
+	}
+
+	ahead, behind, err := m.getBranchStatus(ctx, wt.Path, wt.Branch, baseBranch)
+	if err != nil {
+		// Base branch might not exist locally; only timeouts and cancellation are reported
+		if ctx.Err() != nil {
+			status.Err = statusError(ctx, err)
+		}
+		return status
+	}
+	status.AheadCount = ahead
+	status.BehindCount = behind
+	return status
+}
+
+// statusError replaces a killed command's error with the reason it was killed
+func statusError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("status timed out after %s", StatusTimeout)
+	case context.Canceled:
+		return ctx.Err()
+	}
+	return err
+}
+
+// hasUncommittedChanges is HasUncommittedChanges with cancellation
+func (m *Manager) hasUncommittedChanges(ctx context.Context, worktreePath string) (bool, error) {
+	// Check for staged and unstaged changes
+	res, err := m.runContext(ctx, worktreePath, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to check git status: %w", err)
+	}
+
+	// If output is not empty, there are uncommitted changes
+	return res.Trimmed() != "", nil
+}
+
+// getBranchStatus is GetBranchStatus with cancellation
+func (m *Manager) getBranchStatus(ctx context.Context, worktreePath, branch, baseBranch string) (int, int, error) {
+	if baseBranch == "" {
+		return 0, 0, fmt.Errorf("base branch not specified")
+	}
+
+	// Check if base branch exists
+	if _, err := m.runContext(ctx, worktreePath, "rev-parse", "--verify", baseBranch); err != nil {
+		return 0, 0, fmt.Errorf("base branch '%s' does not exist", baseBranch)
+	}
+
+	// Get ahead count (commits in current branch not in base)
+	res, err := m.runContext(ctx, worktreePath, "rev-list", "--count", fmt.Sprintf("%s..%s", baseBranch, branch))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get ahead count: %w", err)
+	}
+	aheadCount := 0
+	fmt.Sscanf(res.Trimmed(), "%d", &aheadCount)
+
+	// Get behind count (commits in base not in current branch)
+	res, err = m.runContext(ctx, worktreePath, "rev-list", "--count", fmt.Sprintf("%s..%s", branch, baseBranch))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get behind count: %w", err)
+	}
+	behindCount := 0
+	fmt.Sscanf(res.Trimmed(), "%d", &behindCount)
+
+	return aheadCount, behindCount, nil
+}
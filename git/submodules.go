@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Long steps of a worktree creation, reported to CreateOptions.Progress
+const (
+	StepSubmodules = "Updating submodules"
+	StepLFS        = "Pulling LFS files"
+)
+
+// SubmoduleError is returned when a worktree was created but initializing its submodules or
+// pulling its LFS files failed. Like a *CopyConflictError it is a warning, the worktree exists.
+type SubmoduleError struct {
+	Step string // The git command that failed, e.g. "git lfs pull"
+	Err  error
+}
+
+func (e *SubmoduleError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.Step, e.Err)
+}
+
+func (e *SubmoduleError) Unwrap() error {
+	return e.Err
+}
+
+// DetectSubmodulesAndLFS checks if a worktree has submodules (a .gitmodules file) and files
+// stored with Git LFS (a filter=lfs attribute in one of its .gitattributes files)
+func (m *Manager) DetectSubmodulesAndLFS(worktreePath string) (submodules, lfs bool) {
+	if _, err := os.Stat(filepath.Join(worktreePath, ".gitmodules")); err == nil {
+		submodules = true
+	}
+
+	res, err := m.run(worktreePath, "ls-files", "--", "*.gitattributes")
+	if err != nil {
+		return submodules, false
+	}
+	for _, file := range strings.Split(res.Trimmed(), "\n") {
+		if filepath.Base(file) != ".gitattributes" {
+			continue
+		}
+		// Missing outside the cone of a sparse checkout
+		data, err := os.ReadFile(filepath.Join(worktreePath, file))
+		if err == nil && strings.Contains(string(data), "filter=lfs") {
+			return submodules, true
+		}
+	}
+	return submodules, false
+}
+
+// SubmoduleHint tells what a worktree with submodules or LFS files (see DetectSubmodulesAndLFS)
+// still needs when they were not set up on creation, "" if it has neither
+func SubmoduleHint(submodules, lfs bool) string {
+	switch {
+	case submodules && lfs:
+		return "the worktree has submodules and LFS files, run 'git submodule update --init --recursive' and 'git lfs pull' in it"
+	case submodules:
+		return "the worktree has submodules, run 'git submodule update --init --recursive' in it"
+	case lfs:
+		return "the worktree has LFS files, run 'git lfs pull' in it"
+	}
+	return ""
+}
+
+// initSubmodulesEnabled checks the repository setting for initializing submodules and pulling
+// LFS files in new worktrees
+func (m *Manager) initSubmodulesEnabled() bool {
+	if m.configManager == nil {
+		return false
+	}
+	repoRoot, err := m.GetRepoRoot()
+	if err != nil {
+		return false
+	}
+	return m.configManager.GetInitSubmodules(repoRoot)
+}
+
+// initSubmodules initializes the submodules and pulls the LFS files of a new worktree, when
+// the repository setting is enabled and the worktree has any. progress (may be nil) is called
+// before each step. Returns a *SubmoduleError if a step failed.
+func (m *Manager) initSubmodules(worktreePath string, progress func(step string)) error {
+	if !m.initSubmodulesEnabled() {
+		return nil
+	}
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	submodules, lfs := m.DetectSubmodulesAndLFS(worktreePath)
+	if submodules {
+		progress(StepSubmodules)
+		if _, err := m.run(worktreePath, "submodule", "update", "--init", "--recursive"); err != nil {
+			return &SubmoduleError{Step: "git submodule update", Err: err}
+		}
+	}
+	if lfs {
+		progress(StepLFS)
+		if _, err := m.run(worktreePath, "lfs", "pull"); err != nil {
+			return &SubmoduleError{Step: "git lfs pull", Err: err}
+		}
+	}
+	return nil
+}
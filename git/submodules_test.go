@@ -0,0 +1,73 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+)
+
+func TestCreateWithOptions_InitSubmodules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	repoRoot := t.TempDir()
+
+	// git worktree add is faked, the checkout's files are written beforehand
+	path := filepath.Join(repoRoot, ".workspaces", "feature")
+	if err := os.MkdirAll(filepath.Join(path, "assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, ".gitmodules"), []byte("[submodule \"vendor/lib\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "assets", ".gitattributes"), []byte("*.png filter=lfs diff=lfs merge=lfs -text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	runner.On("assets/.gitattributes\n", "ls-files", "--", "*.gitattributes")
+	m := NewManagerWithRunner(repoRoot, runner)
+	m.SetConfigManager(configManager)
+
+	if submodules, lfs := m.DetectSubmodulesAndLFS(path); !submodules || !lfs {
+		t.Fatalf("expected submodules and LFS files to be detected, got %v and %v", submodules, lfs)
+	}
+
+	// Off by default
+	if err := m.CreateWithOptions(path, "feature", true, "", CreateOptions{}); err != nil {
+		t.Fatalf("CreateWithOptions: %v", err)
+	}
+	if runner.Ran("submodule") || runner.Ran("lfs") {
+		t.Error("submodules and LFS files should only be set up when the setting is enabled")
+	}
+
+	if err := configManager.SetInitSubmodules(repoRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	var steps []string
+	opts := CreateOptions{Progress: func(step string) { steps = append(steps, step) }}
+	if err := m.CreateWithOptions(path, "feature", true, "", opts); err != nil {
+		t.Fatalf("CreateWithOptions: %v", err)
+	}
+	if !runner.Ran("submodule", "update", "--init", "--recursive") || !runner.Ran("lfs", "pull") {
+		t.Error("expected the submodules to be updated and the LFS files pulled")
+	}
+	if want := []string{StepSubmodules, StepLFS}; !reflect.DeepEqual(steps, want) {
+		t.Errorf("expected the progress steps %v, got %v", want, steps)
+	}
+
+	// A failure is a warning, the worktree was created
+	runner.OnError(1, "git: 'lfs' is not a git command", "lfs", "pull")
+	err = m.CreateWithOptions(path, "feature", true, "", CreateOptions{})
+	var submoduleErr *SubmoduleError
+	if !errors.As(err, &submoduleErr) || submoduleErr.Step != "git lfs pull" {
+		t.Fatalf("expected a *SubmoduleError for git lfs pull, got %v", err)
+	}
+}
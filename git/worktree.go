@@ -1,13 +1,15 @@
 package git
 
 import (
+	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,53 +28,75 @@ type Worktree struct {
 	HasUncommitted    bool             // Whether the worktree has uncommitted changes
 	PRs               interface{}      // []config.PRInfo - Pull requests for this branch (loaded from config)
 	LastModified      time.Time        // Last modification time of the worktree directory
+	Prunable          bool             // Directory was deleted outside jean, only git's metadata is left
+	Locked            bool             // Locked with `git worktree lock`, only removed when forced
+	LockReason        string           // Optional reason given when locking
+	Detached          bool             // HEAD is not on a branch (worktree at a tag or commit), Branch is ""
+	Managed           bool             // Created by jean (see IsManaged), not the main worktree or one added by hand
+	Ref               string           // Detached worktrees: tag at HEAD, or the short commit hash
+	SparseDirs        []string         // Directories of a sparse checkout (see CreateOptions), nil for a full checkout
 	ClaudeSessionName string           // Sanitized tmux session name for Claude (e.g., "jean-feature-add-status")
 }
 
 // Manager handles Git worktree operations
 type Manager struct {
-	repoPath string
+	repoPath      string
+	runner        Runner          // Executes git commands
+	configManager *config.Manager // Optional, used to persist port blocks
 }
 
 // NewManager creates a new worktree manager
 func NewManager(repoPath string) *Manager {
-	return &Manager{repoPath: repoPath}
+	return NewManagerWithRunner(repoPath, execRunner{})
+}
+
+// NewManagerWithRunner creates a worktree manager that runs git through the given runner
+// (e.g. a FakeRunner in tests)
+func NewManagerWithRunner(repoPath string, runner Runner) *Manager {
+	return &Manager{repoPath: repoPath, runner: runner}
+}
+
+// run runs a git command in dir
+func (m *Manager) run(dir string, args ...string) (Result, error) {
+	return m.runner.Run(context.Background(), dir, args...)
+}
+
+// runContext runs a git command in dir, killing it when ctx is done
+func (m *Manager) runContext(ctx context.Context, dir string, args ...string) (Result, error) {
+	return m.runner.Run(ctx, dir, args...)
 }
 
 // List returns all worktrees in the repository with status relative to the base branch
 func (m *Manager) List(baseBranch string) ([]Worktree, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "worktree", "list", "--porcelain")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	return m.parseWorktrees(string(output), baseBranch, false)
+	return m.parseWorktrees(res.Stdout, baseBranch, false)
 }
 
 // ListWithLightweight returns all worktrees with optional lightweight mode
 // When lightweight=true, skips expensive status checks (uncommitted changes, ahead/behind counts)
 // for faster initial loading. Status can be loaded asynchronously afterwards.
 func (m *Manager) ListWithLightweight(baseBranch string, lightweight bool) ([]Worktree, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "worktree", "list", "--porcelain")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	return m.parseWorktrees(string(output), baseBranch, lightweight)
+	return m.parseWorktrees(res.Stdout, baseBranch, lightweight)
 }
 
 // ListLightweight returns all worktrees without expensive status checks (for quick refreshes)
 func (m *Manager) ListLightweight() ([]Worktree, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "worktree", "list", "--porcelain")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
 	// Pass empty baseBranch to skip status calculations
-	return m.parseWorktrees(string(output), "", true)
+	return m.parseWorktrees(res.Stdout, "", true)
 }
 
 // parseWorktrees parses the output of 'git worktree list --porcelain' and calculates branch status
@@ -95,13 +119,8 @@ func (m *Manager) parseWorktrees(output string, baseBranch string, lightweight b
 			continue
 		}
 
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) < 2 {
-			continue
-		}
-
-		key := parts[0]
-		value := parts[1]
+		// Attributes like "locked" may come without a value
+		key, value, _ := strings.Cut(line, " ")
 
 		switch key {
 		case "worktree":
@@ -111,8 +130,13 @@ func (m *Manager) parseWorktrees(output string, baseBranch string, lightweight b
 		case "branch":
 			// Remove "refs/heads/" prefix
 			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "prunable":
+			current.Prunable = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
 		case "detached":
-			current.Branch = fmt.Sprintf("(detached at %s)", current.Commit[:7])
+			current.Detached = true
 		}
 	}
 
@@ -125,6 +149,19 @@ func (m *Manager) parseWorktrees(output string, baseBranch string, lightweight b
 		worktrees = append(worktrees, current)
 	}
 
+	// Name the tag or commit of detached worktrees
+	for i := range worktrees {
+		if worktrees[i].Detached {
+			worktrees[i].Ref = m.detachedRef(worktrees[i])
+		}
+	}
+
+	if repoRoot, err := m.GetRepoRoot(); err == nil {
+		for i := range worktrees {
+			worktrees[i].Managed = m.isManaged(repoRoot, worktrees[i].Path)
+		}
+	}
+
 	// Mark current worktree and check for uncommitted changes
 	currentPath, err := m.getCurrentPath()
 	if err == nil {
@@ -135,34 +172,20 @@ func (m *Manager) parseWorktrees(output string, baseBranch string, lightweight b
 		}
 	}
 
-	// Check for uncommitted changes in each worktree (skip if lightweight mode)
+	// Load uncommitted changes and ahead/behind counts concurrently (skip if lightweight mode)
 	if !lightweight {
+		index := make(map[string]int, len(worktrees))
 		for i := range worktrees {
-			hasUncommitted, err := m.HasUncommittedChanges(worktrees[i].Path)
-			if err == nil {
-				worktrees[i].HasUncommitted = hasUncommitted
-			}
+			index[worktrees[i].Path] = i
 		}
-	}
-
-	// Calculate branch status relative to base branch (skip if lightweight mode)
-	if !lightweight && baseBranch != "" {
-		for i := range worktrees {
-			// Skip detached HEAD and main repo worktrees
-			if strings.HasPrefix(worktrees[i].Branch, "(detached") {
-				continue
-			}
-
-			// Skip if we can't get branch status (base branch might not exist locally)
-			aheadCount, behindCount, err := m.GetBranchStatus(worktrees[i].Path, worktrees[i].Branch, baseBranch)
-			if err != nil {
-				// Silent skip - base branch might not exist locally or there might be other issues
-				continue
-			}
-
-			worktrees[i].AheadCount = aheadCount
-			worktrees[i].BehindCount = behindCount
-			worktrees[i].IsOutdated = behindCount > 0
+		for status := range m.LoadStatuses(context.Background(), worktrees, baseBranch) {
+			// Silent skip on errors - base branch might not exist locally or git timed out
+			wt := &worktrees[index[status.Path]]
+			wt.HasUncommitted = status.HasUncommitted
+			wt.AheadCount = status.AheadCount
+			wt.BehindCount = status.BehindCount
+			wt.IsOutdated = status.BehindCount > 0
+			wt.SparseDirs = status.SparseDirs
 		}
 	}
 
@@ -180,22 +203,20 @@ func (m *Manager) getWorktreeModTime(path string) (time.Time, error) {
 
 // GetCurrentBranch returns the name of the current branch
 func (m *Manager) GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "branch", "--show-current")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "branch", "--show-current")
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
 // GetDefaultBranch tries to determine the default branch (main, master, etc.)
 func (m *Manager) GetDefaultBranch() (string, error) {
 	// First try to get the default branch from remote
-	cmd := exec.Command("git", "-C", m.repoPath, "symbolic-ref", "refs/remotes/origin/HEAD")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "symbolic-ref", "refs/remotes/origin/HEAD")
 	if err == nil {
 		// Extract branch name from refs/remotes/origin/HEAD -> refs/remotes/origin/main
-		branch := strings.TrimSpace(string(output))
+		branch := res.Trimmed()
 		branch = strings.TrimPrefix(branch, "refs/remotes/origin/")
 		if branch != "" {
 			return branch, nil
@@ -204,20 +225,18 @@ func (m *Manager) GetDefaultBranch() (string, error) {
 
 	// Fallback: check if main or master exists locally
 	for _, branch := range []string{"main", "master"} {
-		cmd := exec.Command("git", "-C", m.repoPath, "rev-parse", "--verify", branch)
-		if err := cmd.Run(); err == nil {
+		if _, err := m.run(m.repoPath, "rev-parse", "--verify", branch); err == nil {
 			return branch, nil
 		}
 	}
 
 	// Last resort: get the first branch
-	cmd = exec.Command("git", "-C", m.repoPath, "branch", "--format=%(refname:short)")
-	output, err = cmd.Output()
+	res, err = m.run(m.repoPath, "branch", "--format=%(refname:short)")
 	if err != nil {
 		return "", fmt.Errorf("failed to get any branch: %w", err)
 	}
 
-	branches := strings.Split(strings.TrimSpace(string(output)), "\n")
+	branches := strings.Split(res.Trimmed(), "\n")
 	if len(branches) > 0 && branches[0] != "" {
 		return branches[0], nil
 	}
@@ -227,70 +246,78 @@ func (m *Manager) GetDefaultBranch() (string, error) {
 
 // getCurrentPath returns the current worktree path
 func (m *Manager) getCurrentPath() (string, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "rev-parse", "--show-toplevel")
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
 // sanitizeBranchForPath converts a branch name to a safe directory name
-// Strips origin/ prefix and replaces slashes with hyphens
+// Replaces slashes with hyphens, the remote of remote tracking branches is stripped beforehand
+// (see LocalBranchName)
 func sanitizeBranchForPath(branch string) string {
-	// Strip origin/ prefix for remote tracking branches
-	path := strings.TrimPrefix(branch, "origin/")
-	// Replace remaining slashes with hyphens to avoid nested directories
-	path = strings.ReplaceAll(path, "/", "-")
-	return path
-}
-
-// getLocalBranchName extracts the local branch name from a branch reference
-// For remote branches like "origin/next", returns "next"
-// For local branches, returns the branch as-is
-func getLocalBranchName(branch string) string {
-	return strings.TrimPrefix(branch, "origin/")
-}
-
-// isRemoteBranch checks if a branch reference is a remote tracking branch
-func isRemoteBranch(branch string) bool {
-	return strings.HasPrefix(branch, "origin/")
+	// Replace slashes with hyphens to avoid nested directories
+	return strings.ReplaceAll(branch, "/", "-")
 }
 
 // branchExists checks if a local branch exists in the repository
 func (m *Manager) branchExists(branch string) bool {
-	cmd := exec.Command("git", "-C", m.repoPath, "rev-parse", "--verify", branch)
-	return cmd.Run() == nil
+	_, err := m.run(m.repoPath, "rev-parse", "--verify", branch)
+	return err == nil
 }
 
 // Create creates a new worktree
 func (m *Manager) Create(path, branch string, newBranch bool, baseBranch string) error {
+	return m.CreateWithOptions(path, branch, newBranch, baseBranch, CreateOptions{})
+}
+
+// CreateWithOptions is Create with a sparse-checkout profile
+func (m *Manager) CreateWithOptions(path, branch string, newBranch bool, baseBranch string, opts CreateOptions) error {
 	// Validate base branch exists if specified
 	if newBranch && baseBranch != "" {
-		cmd := exec.Command("git", "-C", m.repoPath, "rev-parse", "--verify", baseBranch)
-		if err := cmd.Run(); err != nil {
+		if _, err := m.run(m.repoPath, "rev-parse", "--verify", baseBranch); err != nil {
 			return fmt.Errorf("base branch '%s' does not exist. Use 'c' to change the base branch", baseBranch)
 		}
 	}
 
-	args := []string{"-C", m.repoPath, "worktree", "add"}
-	workspacePath := path // May be adjusted below
+	var sparseDirs []string
+	if opts.SparseProfile != "" {
+		dirs, err := m.sparseProfile(opts.SparseProfile)
+		if err != nil {
+			return err
+		}
+		sparseDirs = dirs
+	}
+
+	args := []string{"worktree", "add"}
+	if sparseDirs != nil {
+		// Checked out once the sparse-checkout cone is set, a full checkout is what we avoid
+		args = append(args, "--no-checkout")
+	}
+	workspacePath := path   // May be adjusted below
+	createdBranch := branch // Local branch name, may be adjusted below
 
 	if newBranch {
 		args = append(args, "-b", branch)
-	} else if isRemoteBranch(branch) {
+	} else if _, localBranch, ok := m.splitRemoteBranch(branch); ok {
 		// For remote branches, check if local branch already exists
-		localBranch := getLocalBranchName(branch)
 		if m.branchExists(localBranch) {
 			// Local branch already exists, generate a unique name
 			// e.g., "next" -> "next-happy-panda-42"
 			uniqueSuffix := generateRandomName()
 			localBranch = fmt.Sprintf("%s-%s", localBranch, uniqueSuffix)
-			// Also update the workspace path to be unique
-			workspacePath = filepath.Join(filepath.Dir(path), localBranch)
+			// Also update the workspace path to be unique, placed like any other worktree
+			repoRoot, err := m.GetRepoRoot()
+			if err != nil {
+				return err
+			}
+			root, template := m.worktreeLocation(repoRoot)
+			workspacePath = WorktreePath(repoRoot, root, template, localBranch)
 		}
 		// Use --track flag to create local tracking branch (either new or unique name)
 		args = append(args, "--track", "-b", localBranch)
+		createdBranch = localBranch
 	}
 
 	args = append(args, workspacePath)
@@ -302,61 +329,127 @@ func (m *Manager) Create(path, branch string, newBranch bool, baseBranch string)
 		args = append(args, baseBranch)
 	}
 
-	cmd := exec.Command("git", args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to create worktree: %s", string(output))
+	hookCtx := HookContext{
+		WorkspacePath: workspacePath,
+		Branch:        createdBranch,
+		BaseBranch:    baseBranch,
+	}
+
+	// pre_create hook can veto the creation
+	if _, err := m.RunHook(config.HookPreCreate, hookCtx); err != nil {
+		return err
+	}
+
+	if _, err := m.run(m.repoPath, args...); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	if sparseDirs != nil {
+		if err := m.checkoutSparse(workspacePath, sparseDirs); err != nil {
+			// Don't leave an empty worktree behind
+			_, _ = m.run(m.repoPath, "worktree", "remove", "--force", workspacePath)
+			return err
+		}
+	}
+
+	return m.setupWorktree(hookCtx, opts.Progress)
+}
+
+// SetupError is returned when a worktree was created but its setup script or post_create hook
+// failed. Like a *CopyConflictError it is a warning, the worktree exists.
+type SetupError struct {
+	Stage string // "setup" (the setup script of jean.json) or "post_create" (the hook)
+	Err   error  // Joined with the copy conflicts and submodule errors of the creation, if any
+}
+
+func (e *SetupError) Error() string {
+	if e.Stage == "post_create" {
+		// The *HookError names the hook itself
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("setup script failed:\n%v", e.Err)
+}
+
+func (e *SetupError) Unwrap() error {
+	return e.Err
+}
+
+// setupWorktree prepares a worktree that was just added: port block, submodules and LFS files,
+// copied files, setup script and post_create hook
+func (m *Manager) setupWorktree(hookCtx HookContext, progress func(step string)) error {
+	workspacePath := hookCtx.WorkspacePath
+	m.markManaged(workspacePath)
+
+	// Give the worktree its own port block so dev servers of parallel worktrees don't collide
+	hookCtx.Port = m.AllocatePortBlock(workspacePath)
+
+	// Builds (and the setup script) usually need the submodules and the real LFS files.
+	// A failure doesn't stop the creation, it is returned with the copy conflicts.
+	submoduleErr := m.initSubmodules(workspacePath, progress)
+
+	// Bring over untracked files (.env, local config, caches) listed in jean.json before setup runs.
+	// Conflicts don't stop the creation, they are returned once everything else ran.
+	copyErr := m.applyWorktreeFiles(workspacePath)
+	if submoduleErr != nil {
+		copyErr = errors.Join(submoduleErr, copyErr)
 	}
 
 	// Execute setup script if configured (non-blocking - errors are returned but don't prevent worktree usage)
-	if err := m.executeSetupScript(workspacePath); err != nil {
-		return fmt.Errorf("setup script failed: %w", err)
+	if err := m.executeSetupScript(hookCtx); err != nil {
+		return &SetupError{Stage: "setup", Err: errors.Join(err, copyErr)}
 	}
 
-	return nil
+	// post_create runs after setup; the worktree already exists so failures are warnings too
+	if _, err := m.RunHook(config.HookPostCreate, hookCtx); err != nil {
+		return &SetupError{Stage: "post_create", Err: errors.Join(err, copyErr)}
+	}
+
+	return copyErr
 }
 
 // executeSetupScript runs the setup script from jean.json if configured
 // Returns error if script execution fails, nil if no script configured or script succeeds
-func (m *Manager) executeSetupScript(workspacePath string) error {
+func (m *Manager) executeSetupScript(ctx HookContext) error {
 	// Load script config from repository root
 	repoRoot, err := m.GetRepoRoot()
 	if err != nil {
 		return fmt.Errorf("failed to get repo root: %w", err)
 	}
 
-	scriptConfig, err := config.LoadScripts(repoRoot)
+	scriptConfig, err := loadScripts(repoRoot)
 	if err != nil {
-		return fmt.Errorf("failed to load jean.json: %w", err)
+		return err
 	}
 
-	// Get the setup script
-	script := scriptConfig.GetScript("setup")
-	if script == "" {
+	// Get the setup script (always runs in the background, "interactive" is ignored)
+	script, ok := scriptConfig.LookupScript("setup")
+	if !ok {
 		// No setup script configured, skip
 		return nil
 	}
 
-	// Set environment variables for the script
-	cmd := exec.Command("sh", "-c", script)
-	cmd.Dir = workspacePath // Run script in the worktree directory
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("JEAN_WORKSPACE_PATH=%s", workspacePath),
-		fmt.Sprintf("JEAN_ROOT_PATH=%s", repoRoot),
-	)
-
-	// Capture both stdout and stderr for error reporting
-	output, err := cmd.CombinedOutput()
+	// Run script in the worktree directory with the JEAN_* environment
+	output, err := m.runScript(script, repoRoot, ctx)
 	if err != nil {
 		// Script failed - return error with output for user debugging
-		return fmt.Errorf("%s\n\nScript output:\n%s", err.Error(), string(output))
+		return fmt.Errorf("%s\n\nScript output:\n%s", err.Error(), output)
 	}
 
 	return nil
 }
 
 // Remove removes a worktree and automatically deletes the associated branch
-// Protects common base branches (main, master, develop, etc.) from deletion
+// Protected branches (see IsProtectedBranch) are kept
+// A locked worktree is only removed when forced
 func (m *Manager) Remove(path string, force bool) error {
+	locked := false
+	if wt := m.findWorktree(path); wt != nil && wt.Locked {
+		if !force {
+			return lockedError(wt)
+		}
+		locked = true
+	}
+
 	// Get the branch name before removing the worktree
 	branchName, err := m.GetCurrentBranchForWorktree(path)
 	if err != nil {
@@ -366,21 +459,27 @@ func (m *Manager) Remove(path string, force bool) error {
 	}
 
 	// Remove the worktree
-	args := []string{"-C", m.repoPath, "worktree", "remove"}
+	args := []string{"worktree", "remove"}
 
 	if force {
 		args = append(args, "--force")
 	}
+	if locked {
+		// git needs a second --force for locked worktrees
+		args = append(args, "--force")
+	}
 
 	args = append(args, path)
 
-	cmd := exec.Command("git", args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to remove worktree: %s", string(output))
+	if _, err := m.run(m.repoPath, args...); err != nil {
+		return fmt.Errorf("failed to remove worktree: %w", err)
 	}
 
+	m.releasePortBlock(path)
+	m.forgetManaged(path)
+
 	// Delete the branch if it's not a protected base branch
-	if branchName != "" && !isProtectedBranch(branchName) {
+	if branchName != "" && !m.IsProtectedBranch(branchName) {
 		// Attempt to delete the branch - don't fail the operation if this fails
 		if err := m.DeleteBranch(branchName); err != nil {
 			// Log the warning but don't return error - worktree was already removed successfully
@@ -391,32 +490,42 @@ func (m *Manager) Remove(path string, force bool) error {
 	return nil
 }
 
-// isProtectedBranch checks if a branch name is a common base branch that should not be deleted
-func isProtectedBranch(branchName string) bool {
-	protectedBranches := []string{
-		"main",
-		"master",
-		"develop",
-		"development",
-		"staging",
-		"production",
+// ErrProtectedBranch is returned when an operation would delete, rename or merge away a protected branch
+var ErrProtectedBranch = errors.New("branch is protected")
+
+// IsProtectedBranch checks if a branch matches the "protected" patterns of jean.json
+// (main, master, develop, development, staging and production when none are configured).
+// Remote tracking branches of any remote (upstream/main) match like their local name.
+func (m *Manager) IsProtectedBranch(branchName string) bool {
+	var scriptConfig *config.ScriptConfig
+	if repoRoot, err := m.GetRepoRoot(); err == nil {
+		// An unreadable jean.json falls back to the default patterns, invalid entries
+		// elsewhere in the file don't (LoadScripts keeps the valid "protected" patterns)
+		scriptConfig, _ = config.LoadScripts(repoRoot)
 	}
+	return scriptConfig.IsProtectedBranch(m.LocalBranchName(branchName))
+}
 
-	for _, protected := range protectedBranches {
-		if branchName == protected {
-			return true
-		}
+// checkNotProtected returns an ErrProtectedBranch error if the branch may not be modified
+func (m *Manager) checkNotProtected(action, branchName string) error {
+	if m.IsProtectedBranch(branchName) {
+		return fmt.Errorf("cannot %s '%s': %w", action, branchName, ErrProtectedBranch)
 	}
-	return false
+	return nil
 }
 
 // MoveWorktree moves a worktree to a new location using git worktree move
 // This is used to rename the worktree directory when a branch is renamed
 func (m *Manager) MoveWorktree(oldPath, newPath string) error {
-	cmd := exec.Command("git", "-C", m.repoPath, "worktree", "move", oldPath, newPath)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to move worktree: %s", string(output))
+	if _, err := m.run(m.repoPath, "worktree", "move", oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to move worktree: %w", err)
+	}
+
+	// The worktree keeps its port block and stays managed
+	if m.configManager != nil {
+		if repoRoot, err := m.GetRepoRoot(); err == nil {
+			_ = m.configManager.MoveWorktree(repoRoot, absPath(oldPath), absPath(newPath))
+		}
 	}
 	return nil
 }
@@ -431,14 +540,17 @@ func (m *Manager) EnsureWorktreeExists(path, branch string) error {
 	}
 
 	// Directory doesn't exist, recreate it
-	args := []string{"-C", m.repoPath, "worktree", "add", path, branch}
-	cmd := exec.Command("git", args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to recreate worktree: %s", string(output))
+	if _, err := m.run(m.repoPath, "worktree", "add", path, branch); err != nil {
+		return fmt.Errorf("failed to recreate worktree: %w", err)
 	}
 
 	// Execute setup script if configured (non-blocking)
-	if err := m.executeSetupScript(path); err != nil {
+	if err := m.applyWorktreeFiles(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	hookCtx := HookContext{WorkspacePath: path, Branch: branch, Port: m.AllocatePortBlock(path)}
+	if err := m.executeSetupScript(hookCtx); err != nil {
 		// Log the error but don't fail - worktree is still usable
 		fmt.Fprintf(os.Stderr, "Warning: setup script failed during worktree recreation: %v\n", err)
 	}
@@ -448,29 +560,29 @@ func (m *Manager) EnsureWorktreeExists(path, branch string) error {
 
 // RenameBranch renames the current branch
 func (m *Manager) RenameBranch(oldName, newName string) error {
-	cmd := exec.Command("git", "-C", m.repoPath, "branch", "-m", oldName, newName)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to rename branch: %s", string(output))
+	if err := m.checkNotProtected("rename", oldName); err != nil {
+		return err
+	}
+	if _, err := m.run(m.repoPath, "branch", "-m", oldName, newName); err != nil {
+		return fmt.Errorf("failed to rename branch: %w", err)
 	}
 	return nil
 }
 
 // RenameBranchInWorktree renames a branch in a specific worktree
 func (m *Manager) RenameBranchInWorktree(worktreePath, oldName, newName string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "branch", "-m", oldName, newName)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to rename branch: %s", string(output))
+	if err := m.checkNotProtected("rename", oldName); err != nil {
+		return err
+	}
+	if _, err := m.run(worktreePath, "branch", "-m", oldName, newName); err != nil {
+		return fmt.Errorf("failed to rename branch: %w", err)
 	}
 	return nil
 }
 
 // BranchExists checks if a branch exists locally in the worktree at the given path
 func (m *Manager) BranchExists(worktreePath, branchName string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--verify", branchName)
-	err := cmd.Run()
-	if err == nil {
+	if _, err := m.run(worktreePath, "rev-parse", "--verify", branchName); err == nil {
 		return true, nil
 	}
 	// Branch doesn't exist
@@ -483,12 +595,13 @@ func (m *Manager) DeleteBranch(branchName string) error {
 	if branchName == "" {
 		return fmt.Errorf("branch name cannot be empty")
 	}
+	if err := m.checkNotProtected("delete", branchName); err != nil {
+		return err
+	}
 
 	// Use -D to force delete even if not fully merged
-	cmd := exec.Command("git", "-C", m.repoPath, "branch", "-D", branchName)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to delete branch: %s", string(output))
+	if _, err := m.run(m.repoPath, "branch", "-D", branchName); err != nil {
+		return fmt.Errorf("failed to delete branch: %w", err)
 	}
 	return nil
 }
@@ -531,30 +644,32 @@ func SanitizeBranchName(name string) string {
 
 // CheckoutBranch checks out a branch in the main repository
 func (m *Manager) CheckoutBranch(branch string) error {
-	cmd := exec.Command("git", "-C", m.repoPath, "checkout", branch)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to checkout branch: %s", string(output))
+	if _, err := m.run(m.repoPath, "checkout", branch); err != nil {
+		return fmt.Errorf("failed to checkout branch: %w", err)
 	}
 	return nil
 }
 
 // ListBranches returns all branches in the repository
+// Remote tracking branches are listed as <remote>/<branch>
 func (m *Manager) ListBranches() ([]string, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "branch", "-a", "--format=%(refname:short)")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "branch", "-a", "--format=%(refname)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list branches: %w", err)
 	}
 
-	branches := strings.Split(strings.TrimSpace(string(output)), "\n")
+	branches := strings.Split(res.Trimmed(), "\n")
 
-	// Filter out current branch marker (origin/HEAD)
+	// Filter out the default branch markers of the remotes (<remote>/HEAD)
 	var filtered []string
 	for _, b := range branches {
 		b = strings.TrimSpace(b)
-		if b != "" && !strings.HasPrefix(b, "origin/HEAD") {
-			filtered = append(filtered, b)
+		if remoteBranch, ok := strings.CutPrefix(b, "refs/remotes/"); ok {
+			if !strings.HasSuffix(remoteBranch, "/HEAD") {
+				filtered = append(filtered, remoteBranch)
+			}
+		} else if b != "" {
+			filtered = append(filtered, strings.TrimPrefix(b, "refs/heads/"))
 		}
 	}
 
@@ -563,39 +678,37 @@ func (m *Manager) ListBranches() ([]string, error) {
 
 // GetRepoRoot returns the root path of the repository
 func (m *Manager) GetRepoRoot() (string, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "rev-parse", "--show-toplevel")
 	if err != nil {
 		return "", fmt.Errorf("not a git repository or git is not installed")
 	}
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
-// GetDefaultPath returns a default path for a new worktree in .workspaces directory
+// GetDefaultPath returns the path of a new worktree for a branch, from the configured worktree
+// root and path template (default: .workspaces/<branch>)
 func (m *Manager) GetDefaultPath(branch string) (string, error) {
-	root, err := m.GetRepoRoot()
+	repoRoot, err := m.GetRepoRoot()
 	if err != nil {
 		return "", err
 	}
 
-	// Use .workspaces directory inside repo root
-	workspacesDir := filepath.Join(root, ".workspaces")
-
-	// Sanitize branch name to create safe directory name
-	sanitized := sanitizeBranchForPath(branch)
-	return filepath.Join(workspacesDir, sanitized), nil
+	root, template := m.worktreeLocation(repoRoot)
+	return WorktreePath(repoRoot, root, template, m.LocalBranchName(branch)), nil
 }
 
-// GetWorkspacesDir returns the .workspaces directory path
+// GetWorkspacesDir returns the directory new worktrees are created in (default: .workspaces)
 func (m *Manager) GetWorkspacesDir() (string, error) {
-	root, err := m.GetRepoRoot()
+	repoRoot, err := m.GetRepoRoot()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(root, ".workspaces"), nil
+
+	root, _ := m.worktreeLocation(repoRoot)
+	return expandWorktreeRoot(repoRoot, root), nil
 }
 
-// EnsureWorkspacesDir creates the .workspaces directory if it doesn't exist
+// EnsureWorkspacesDir creates the directory new worktrees are created in if it doesn't exist
 func (m *Manager) EnsureWorkspacesDir() error {
 	dir, err := m.GetWorkspacesDir()
 	if err != nil {
@@ -603,7 +716,7 @@ func (m *Manager) EnsureWorkspacesDir() error {
 	}
 
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create .workspaces directory: %w", err)
+		return fmt.Errorf("failed to create worktree directory %s: %w", dir, err)
 	}
 
 	return nil
@@ -689,18 +802,16 @@ func (m *Manager) Push(worktreePath, branch string) error {
 
 	// Log git config
 	debugLog += "\n--- Git Config (in worktree) ---\n"
-	configCmd := exec.Command("git", "-C", worktreePath, "config", "--list")
-	if configOutput, err := configCmd.CombinedOutput(); err == nil {
-		debugLog += string(configOutput)
+	if res, err := m.run(worktreePath, "config", "--list"); err == nil {
+		debugLog += res.Stdout
 	} else {
 		debugLog += fmt.Sprintf("Error getting config: %v\n", err)
 	}
 
 	// Log global git config
 	debugLog += "\n--- Git Config (global) ---\n"
-	globalConfigCmd := exec.Command("git", "config", "--global", "--list")
-	if globalOutput, err := globalConfigCmd.CombinedOutput(); err == nil {
-		debugLog += string(globalOutput)
+	if res, err := m.run("", "config", "--global", "--list"); err == nil {
+		debugLog += res.Stdout
 	} else {
 		debugLog += fmt.Sprintf("Error getting global config: %v\n", err)
 	}
@@ -712,28 +823,26 @@ func (m *Manager) Push(worktreePath, branch string) error {
 	}
 
 	// First check if remote exists
-	cmd := exec.Command("git", "-C", worktreePath, "remote", "get-url", "origin")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("no remote 'origin' configured")
+	remote := m.PushRemote()
+	if !m.hasRemote(worktreePath, remote) {
+		return fmt.Errorf("%w: '%s' does not exist", ErrNoRemote, remote)
 	}
 
 	// Push with --set-upstream to create remote branch if it doesn't exist
-	cmd = exec.Command("git", "-C", worktreePath, "push", "-u", "origin", branch)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to push: %s", string(output))
+	if _, err := m.run(worktreePath, "push", "-u", remote, branch); err != nil {
+		return fmt.Errorf("failed to push: %w", err)
 	}
 
 	return nil
 }
 
-// RemoteBranchExists checks if a branch exists on the remote
+// RemoteBranchExists checks if a branch exists on the push remote
 func (m *Manager) RemoteBranchExists(worktreePath, branch string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--verify", fmt.Sprintf("origin/%s", branch))
-	err := cmd.Run()
+	_, err := m.run(worktreePath, "rev-parse", "--verify", fmt.Sprintf("%s/%s", m.PushRemote(), branch))
 	if err != nil {
 		// Check if it's an actual error or just branch not found
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && cmdErr.ExitCode == 128 {
 			return false, nil
 		}
 		return false, fmt.Errorf("failed to check remote branch: %w", err)
@@ -741,25 +850,24 @@ func (m *Manager) RemoteBranchExists(worktreePath, branch string) (bool, error)
 	return true, nil
 }
 
-// DeleteRemoteBranch deletes a branch from the remote repository
+// DeleteRemoteBranch deletes a branch from the push remote
 func (m *Manager) DeleteRemoteBranch(worktreePath, branch string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "push", "origin", "--delete", branch)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to delete remote branch: %s", string(output))
+	if err := m.checkNotProtected("delete remote branch", branch); err != nil {
+		return err
+	}
+	if _, err := m.run(worktreePath, "push", m.PushRemote(), "--delete", branch); err != nil {
+		return fmt.Errorf("failed to delete remote branch: %w", err)
 	}
 	return nil
 }
 
 // HasCommits checks if the current branch has any commits
 func (m *Manager) HasCommits(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-list", "--count", "HEAD")
-	output, err := cmd.Output()
+	res, err := m.run(worktreePath, "rev-list", "--count", "HEAD")
 	if err != nil {
 		return false, fmt.Errorf("failed to count commits: %w", err)
 	}
-	commitCount := strings.TrimSpace(string(output))
-	return commitCount != "0", nil
+	return res.Trimmed() != "0", nil
 }
 
 // HasUnpushedCommits checks if there are commits that haven't been pushed
@@ -776,24 +884,21 @@ func (m *Manager) HasUnpushedCommits(worktreePath, branch string) (bool, error)
 	}
 
 	// Remote branch exists, check if we're ahead
-	cmd := exec.Command("git", "-C", worktreePath, "rev-list", "--count", fmt.Sprintf("origin/%s..HEAD", branch))
-	output, err := cmd.Output()
+	res, err := m.run(worktreePath, "rev-list", "--count", fmt.Sprintf("%s/%s..HEAD", m.PushRemote(), branch))
 	if err != nil {
 		return false, fmt.Errorf("failed to check unpushed commits: %w", err)
 	}
 
-	commitCount := strings.TrimSpace(string(output))
-	return commitCount != "0", nil
+	return res.Trimmed() != "0", nil
 }
 
-// GetRemoteURL returns the URL of the origin remote
+// GetRemoteURL returns the URL of the base remote, the repository PRs are opened against
 func (m *Manager) GetRemoteURL() (string, error) {
-	cmd := exec.Command("git", "-C", m.repoPath, "remote", "get-url", "origin")
-	output, err := cmd.Output()
+	res, err := m.run(m.repoPath, "remote", "get-url", m.BaseRemote())
 	if err != nil {
 		return "", fmt.Errorf("failed to get remote URL: %w", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
 // IsGitHubRepo checks if the repository is hosted on GitHub
@@ -809,36 +914,30 @@ func (m *Manager) IsGitHubRepo() (bool, error) {
 
 // HasUncommittedChanges checks if there are uncommitted changes in a worktree
 func (m *Manager) HasUncommittedChanges(worktreePath string) (bool, error) {
-	// Check for staged and unstaged changes
-	cmd := exec.Command("git", "-C", worktreePath, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return false, fmt.Errorf("failed to check git status: %w", err)
-	}
-
-	// If output is not empty, there are uncommitted changes
-	return len(strings.TrimSpace(string(output))) > 0, nil
+	return m.hasUncommittedChanges(context.Background(), worktreePath)
 }
 
-// FetchRemote fetches updates from the remote repository without merging
+// FetchRemote fetches updates from the base remote (and the push remote when it differs)
+// without merging
 // Returns nil if remote doesn't exist (graceful skip) or if fetch succeeds
 // Returns error only if remote exists but fetch fails
 func (m *Manager) FetchRemote() error {
-	// Check if 'origin' remote exists
-	checkCmd := exec.Command("git", "-C", m.repoPath, "remote", "get-url", "origin")
-	checkOutput, err := checkCmd.CombinedOutput()
-	checkOutputStr := strings.TrimSpace(string(checkOutput))
-
-	// If remote doesn't exist or check fails, skip fetch gracefully
-	if err != nil || checkOutputStr == "" {
-		return nil // No remote configured, skip fetch
+	push, base := m.Remotes()
+	remotes := []string{base}
+	if push != base {
+		remotes = append(remotes, push)
 	}
 
-	// Remote exists, attempt fetch
-	cmd := exec.Command("git", "-C", m.repoPath, "fetch", "origin")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to fetch from remote: %s", string(output))
+	for _, remote := range remotes {
+		// If remote doesn't exist or check fails, skip fetch gracefully
+		if !m.hasRemote(m.repoPath, remote) {
+			continue // No remote configured, skip fetch
+		}
+
+		// Remote exists, attempt fetch
+		if _, err := m.run(m.repoPath, "fetch", remote); err != nil {
+			return fmt.Errorf("failed to fetch from remote: %w", err)
+		}
 	}
 	return nil
 }
@@ -846,35 +945,7 @@ func (m *Manager) FetchRemote() error {
 // GetBranchStatus returns the ahead and behind counts for a branch compared to the base branch
 // Returns (aheadCount, behindCount, error)
 func (m *Manager) GetBranchStatus(worktreePath, branch, baseBranch string) (int, int, error) {
-	if baseBranch == "" {
-		return 0, 0, fmt.Errorf("base branch not specified")
-	}
-
-	// Check if base branch exists
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--verify", baseBranch)
-	if err := cmd.Run(); err != nil {
-		return 0, 0, fmt.Errorf("base branch '%s' does not exist", baseBranch)
-	}
-
-	// Get ahead count (commits in current branch not in base)
-	cmd = exec.Command("git", "-C", worktreePath, "rev-list", "--count", fmt.Sprintf("%s..%s", baseBranch, branch))
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get ahead count: %w", err)
-	}
-	aheadCount := 0
-	fmt.Sscanf(strings.TrimSpace(string(output)), "%d", &aheadCount)
-
-	// Get behind count (commits in base not in current branch)
-	cmd = exec.Command("git", "-C", worktreePath, "rev-list", "--count", fmt.Sprintf("%s..%s", branch, baseBranch))
-	output, err = cmd.Output()
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get behind count: %w", err)
-	}
-	behindCount := 0
-	fmt.Sscanf(strings.TrimSpace(string(output)), "%d", &behindCount)
-
-	return aheadCount, behindCount, nil
+	return m.getBranchStatus(context.Background(), worktreePath, branch, baseBranch)
 }
 
 // MergeBranch merges the specified base branch into the current branch in the worktree
@@ -884,21 +955,13 @@ func (m *Manager) MergeBranch(worktreePath, baseBranch string) error {
 	}
 
 	// Check if base branch exists
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--verify", baseBranch)
-	if err := cmd.Run(); err != nil {
+	if _, err := m.run(worktreePath, "rev-parse", "--verify", baseBranch); err != nil {
 		return fmt.Errorf("base branch '%s' does not exist", baseBranch)
 	}
 
 	// Perform the merge
-	cmd = exec.Command("git", "-C", worktreePath, "merge", baseBranch, "--no-edit")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		outputStr := string(output)
-		// Check if it's a merge conflict
-		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "Automatic merge failed") {
-			return fmt.Errorf("merge conflict occurred. Use 'git merge --abort' to abort the merge")
-		}
-		return fmt.Errorf("failed to merge: %s", outputStr)
+	if _, err := m.run(worktreePath, "merge", baseBranch, "--no-edit"); err != nil {
+		return mergeError("failed to merge", err)
 	}
 
 	return nil
@@ -906,256 +969,107 @@ func (m *Manager) MergeBranch(worktreePath, baseBranch string) error {
 
 // AbortMerge aborts an in-progress merge and returns to a clean state
 func (m *Manager) AbortMerge(worktreePath string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "merge", "--abort")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to abort merge: %s", string(output))
+	if _, err := m.run(worktreePath, "merge", "--abort"); err != nil {
+		return fmt.Errorf("failed to abort merge: %w", err)
 	}
 	return nil
 }
 
-// PullCurrentBranch pulls the current branch from origin
+// PullCurrentBranch pulls the current branch from its remote (see pullRemote)
 // For repositories without a remote, falls back to no-op
 func (m *Manager) PullCurrentBranch(worktreePath, branch string) error {
-	// Check if 'origin' remote exists
-	checkCmd := exec.Command("git", "-C", worktreePath, "remote", "get-url", "origin")
-	checkOutput, err := checkCmd.CombinedOutput()
-	checkOutputStr := strings.TrimSpace(string(checkOutput))
-	hasRemote := err == nil && checkOutputStr != ""
-
 	// If no remote, skip pull
-	if !hasRemote {
+	remote := m.pullRemote(worktreePath, branch)
+	if !m.hasRemote(worktreePath, remote) {
 		return nil
 	}
 
-	cmd := exec.Command("git", "-C", worktreePath, "pull", "origin", branch)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		outputStr := string(output)
-		// Check if it's a merge conflict
-		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "Automatic merge failed") {
-			return fmt.Errorf("merge conflict occurred. Use 'git merge --abort' to abort the merge")
-		}
-		return fmt.Errorf("failed to pull: %s", outputStr)
+	if _, err := m.run(worktreePath, "pull", remote, branch); err != nil {
+		return mergeError("failed to pull", err)
 	}
 	return nil
 }
 
-// PullBranchInPath pulls a specific branch from origin in the given directory
+// PullBranchInPath pulls a specific branch from its remote in the given directory
 // For repositories without a remote, falls back to local merge
 func (m *Manager) PullBranchInPath(path, branch string) error {
-	// Check if 'origin' remote exists
-	checkCmd := exec.Command("git", "-C", path, "remote", "get-url", "origin")
-	checkOutput, err := checkCmd.CombinedOutput()
-	checkOutputStr := strings.TrimSpace(string(checkOutput))
-	hasRemote := err == nil && checkOutputStr != ""
-
-	var cmd *exec.Cmd
-	if hasRemote {
-		// Remote exists, use git pull
-		cmd = exec.Command("git", "-C", path, "pull", "origin", branch)
-	} else {
-		// No remote, use local merge instead
-		cmd = exec.Command("git", "-C", path, "merge", branch, "--no-edit")
-	}
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		outputStr := string(output)
-		// Check if it's a merge conflict
-		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "Automatic merge failed") {
-			return fmt.Errorf("merge conflict occurred. Use 'git merge --abort' to abort the merge")
-		}
-		return fmt.Errorf("failed to pull: %s", outputStr)
-	}
-	return nil
+	_, err := m.PullBranch(path, branch)
+	return err
 }
 
-// PullCurrentBranchWithOutput pulls current branch and returns the git output and error
-// For repositories without a remote, falls back to local merge
-func (m *Manager) PullCurrentBranchWithOutput(worktreePath, branch string) (string, error) {
-	// Check if 'origin' remote exists
-	checkCmd := exec.Command("git", "-C", worktreePath, "remote", "get-url", "origin")
-	checkOutput, err := checkCmd.CombinedOutput()
-	checkOutputStr := strings.TrimSpace(string(checkOutput))
-	hasRemote := err == nil && checkOutputStr != ""
-
-	var cmd *exec.Cmd
-	if hasRemote {
-		// Remote exists, use git pull
-		cmd = exec.Command("git", "-C", worktreePath, "pull", "origin", branch)
-	} else {
-		// No remote, use local merge instead
-		cmd = exec.Command("git", "-C", worktreePath, "merge", branch, "--no-edit")
-	}
-
-	output, err := cmd.CombinedOutput()
-	outputStr := string(output)
-	if err != nil {
-		// Check if it's a merge conflict
-		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "Automatic merge failed") {
-			return outputStr, fmt.Errorf("merge conflict occurred. Use 'git merge --abort' to abort the merge")
-		}
-		return outputStr, fmt.Errorf("failed to pull: %s", outputStr)
-	}
-	return outputStr, nil
+// PullResult describes what a pull brought into a worktree
+type PullResult struct {
+	Output   string // git output, for display
+	UpToDate bool   // Nothing was pulled
+	Commits  int    // Number of commits the branch moved forward by
 }
 
-// PullBranchInPathWithOutput pulls a specific branch and returns the git output and error
+// PullBranch pulls a branch from its remote (see pullRemote) in the given directory and reports
+// how many commits came in, measured by comparing HEAD before and after rather than parsing
+// git's output.
 // For repositories without a remote, falls back to local merge
-func (m *Manager) PullBranchInPathWithOutput(path, branch string) (string, error) {
-	// Check if 'origin' remote exists
-	checkCmd := exec.Command("git", "-C", path, "remote", "get-url", "origin")
-	checkOutput, err := checkCmd.CombinedOutput()
-	checkOutputStr := strings.TrimSpace(string(checkOutput))
-	hasRemote := err == nil && checkOutputStr != ""
+func (m *Manager) PullBranch(path, branch string) (PullResult, error) {
+	before, _ := m.run(path, "rev-parse", "HEAD")
 
-	var cmd *exec.Cmd
-	if hasRemote {
+	var res Result
+	var err error
+	if remote := m.pullRemote(path, branch); m.hasRemote(path, remote) {
 		// Remote exists, use git pull
-		cmd = exec.Command("git", "-C", path, "pull", "origin", branch)
+		res, err = m.run(path, "pull", remote, branch)
 	} else {
 		// No remote, use local merge instead
-		cmd = exec.Command("git", "-C", path, "merge", branch, "--no-edit")
+		res, err = m.run(path, "merge", branch, "--no-edit")
 	}
-
-	output, err := cmd.CombinedOutput()
-	outputStr := string(output)
 	if err != nil {
-		// Check if it's a merge conflict
-		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "Automatic merge failed") {
-			return outputStr, fmt.Errorf("merge conflict occurred. Use 'git merge --abort' to abort the merge")
-		}
-		return outputStr, fmt.Errorf("failed to pull: %s", outputStr)
-	}
-	return outputStr, nil
-}
-
-// ParsePullOutput extracts commit count information from git pull output
-// Returns (upToDate, commitsCount)
-func (m *Manager) ParsePullOutput(output string) (bool, int) {
-	// Check if everything was already up to date
-	if strings.Contains(output, "Already up to date") || strings.Contains(output, "Already up-to-date") {
-		return true, 0
+		return PullResult{Output: res.Combined()}, mergeError("failed to pull", err)
 	}
 
-	// Try to find "X files changed" pattern which indicates commits were pulled
-	// Example output: "... (3 commits) ... 5 files changed, 100 insertions(+), 20 deletions(-)"
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		// Look for commit count info in parentheses like "(3 commits)"
-		if strings.Contains(line, "commits") {
-			// Try to extract number: "* commits", "1 commits", "(3 commits)", etc.
-			parts := strings.Fields(line)
-			for i, part := range parts {
-				if strings.Contains(part, "commit") && i > 0 {
-					// Previous part should be the number
-					prevPart := strings.Trim(parts[i-1], "(),")
-					if count, err := parseCount(prevPart); err == nil && count > 0 {
-						return false, count
-					}
-				}
+	result := PullResult{Output: res.Combined(), UpToDate: true}
+	after, _ := m.run(path, "rev-parse", "HEAD")
+	if before.Trimmed() != "" && after.Trimmed() != "" && before.Trimmed() != after.Trimmed() {
+		result.UpToDate = false
+		result.Commits = 1 // At least one commit if the count below fails
+		if count, err := m.run(path, "rev-list", "--count", before.Trimmed()+".."+after.Trimmed()); err == nil {
+			if n, err := strconv.Atoi(count.Trimmed()); err == nil && n > 0 {
+				result.Commits = n
 			}
 		}
-
-		// Also look for "# commits"  pattern in fetch output
-		if strings.HasPrefix(line, "* [new branch]") || strings.HasPrefix(line, "* [new tag]") {
-			continue
-		}
-
-		// If we see "Fast-forward" or "merge made", commits were definitely pulled
-		if strings.Contains(line, "Fast-forward") || strings.Contains(line, "Merge made") {
-			return false, 1 // At least 1 commit
-		}
 	}
-
-	// If merge happened but we can't count, assume at least 1 commit
-	if strings.Contains(output, "merge") || strings.Contains(output, "Merge") {
-		return false, 1
-	}
-
-	return false, 0
+	return result, nil
 }
 
-// parseCount tries to parse a string as an integer
-func parseCount(s string) (int, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return 0, fmt.Errorf("empty string")
-	}
-
-	var num string
-	for _, c := range s {
-		if c >= '0' && c <= '9' {
-			num += string(c)
-		}
-	}
-
-	if num == "" {
-		return 0, fmt.Errorf("no digits found")
-	}
+// hasRemote checks if a remote is configured
+func (m *Manager) hasRemote(dir, remote string) bool {
+	res, err := m.run(dir, "remote", "get-url", remote)
+	return err == nil && res.Trimmed() != ""
+}
 
-	// Convert string to int
-	result := 0
-	for _, c := range num {
-		result = result*10 + int(c-'0')
+// mergeError wraps a failed merge/pull, turning conflicts into ErrMergeConflict
+func mergeError(message string, err error) error {
+	if errors.Is(err, ErrMergeConflict) {
+		return fmt.Errorf("%w occurred. Use 'git merge --abort' to abort the merge", ErrMergeConflict)
 	}
-
-	return result, nil
+	return fmt.Errorf("%s: %w", message, err)
 }
 
-// CreateCommit stages all changes and creates a commit with the given subject and body
+// CreateCommit stages all changes and creates a commit with the given subject
 // Returns the commit hash on success or an error
 func (m *Manager) CreateCommit(worktreePath, subject string) (string, error) {
+	return m.CreateCommitWithOptions(worktreePath, subject, CommitOptions{})
+}
+
+// CreateCommitWithOptions is CreateCommit with a message body, trailers and signing preferences
+func (m *Manager) CreateCommitWithOptions(worktreePath, subject string, opts CommitOptions) (string, error) {
 	if subject == "" {
 		return "", fmt.Errorf("commit subject cannot be empty")
 	}
 
 	// First, stage all changes (git add -A)
-	addCmd := exec.Command("git", "-C", worktreePath, "add", "-A")
-	if output, err := addCmd.CombinedOutput(); err != nil {
-		return "", fmt.Errorf("failed to stage changes: %s", string(output))
-	}
-
-	// Build the commit command with only the subject
-	args := []string{"-C", worktreePath, "commit", "-m", subject}
-
-	commitCmd := exec.Command("git", args...)
-	output, err := commitCmd.CombinedOutput()
-	outputStr := string(output)
-
-	if err != nil {
-		return "", fmt.Errorf("failed to create commit: %s", outputStr)
-	}
-
-	// Parse the commit hash from the output
-	// Output format: "[branch_name hash] commit subject"
-	lines := strings.Split(outputStr, "\n")
-	for _, line := range lines {
-		// Look for hash in square brackets like "[main abc1234]"
-		if strings.Contains(line, "[") && strings.Contains(line, "]") {
-			start := strings.Index(line, "[")
-			end := strings.Index(line, "]")
-			if start < end {
-				content := line[start+1 : end]
-				parts := strings.Fields(content)
-				if len(parts) >= 2 {
-					// Last part should be the hash
-					return parts[len(parts)-1], nil
-				}
-			}
-		}
-	}
-
-	// If we can't parse the hash, run git rev-parse to get it
-	hashCmd := exec.Command("git", "-C", worktreePath, "rev-parse", "HEAD")
-	hashOutput, err := hashCmd.Output()
-	if err == nil {
-		return strings.TrimSpace(string(hashOutput)), nil
+	if _, err := m.run(worktreePath, "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
 	}
 
-	// Fallback: return empty string (commit was successful but we couldn't get the hash)
-	return "", nil
+	return m.CommitStaged(worktreePath, subject, opts)
 }
 
 // GetDiff returns the git diff output for uncommitted changes in the worktree
@@ -1165,29 +1079,26 @@ func (m *Manager) GetDiff(worktreePath string) (string, error) {
 	var result strings.Builder
 
 	// Get staged changes (git add -A staging area)
-	stagingCmd := exec.Command("git", "-C", worktreePath, "diff", "--cached")
-	stagingOutput, _ := stagingCmd.Output()
-	if len(stagingOutput) > 0 {
+	staged, _ := m.run(worktreePath, "diff", "--cached")
+	if staged.Stdout != "" {
 		result.WriteString("=== STAGED CHANGES ===\n")
-		result.Write(stagingOutput)
+		result.WriteString(staged.Stdout)
 		result.WriteString("\n")
 	}
 
 	// Get unstaged changes (modified tracked files not yet staged)
-	unstageCmd := exec.Command("git", "-C", worktreePath, "diff")
-	unstageOutput, _ := unstageCmd.Output()
-	if len(unstageOutput) > 0 {
+	unstaged, _ := m.run(worktreePath, "diff")
+	if unstaged.Stdout != "" {
 		result.WriteString("=== UNSTAGED CHANGES ===\n")
-		result.Write(unstageOutput)
+		result.WriteString(unstaged.Stdout)
 		result.WriteString("\n")
 	}
 
 	// Get untracked files status
-	statusCmd := exec.Command("git", "-C", worktreePath, "status", "--porcelain")
-	statusOutput, _ := statusCmd.Output()
-	if len(statusOutput) > 0 {
+	status, _ := m.run(worktreePath, "status", "--porcelain")
+	if status.Stdout != "" {
 		result.WriteString("=== FILE STATUS ===\n")
-		result.Write(statusOutput)
+		result.WriteString(status.Stdout)
 		result.WriteString("\n")
 	}
 
@@ -1207,16 +1118,14 @@ func (m *Manager) GetDiffFromBase(worktreePath, baseBranch string) (string, erro
 	}
 
 	// First, ensure the base branch is fetched from remote
-	fetchCmd := exec.Command("git", "-C", worktreePath, "fetch", "origin", baseBranch)
-	_ = fetchCmd.Run() // Ignore errors, base branch might be local-only
+	_, _ = m.run(worktreePath, "fetch", m.BaseRemote(), baseBranch) // Ignore errors, base branch might be local-only
 
 	// Get diff between current branch and base branch
-	cmd := exec.Command("git", "-C", worktreePath, "diff", baseBranch)
-	output, err := cmd.Output()
+	res, err := m.run(worktreePath, "diff", baseBranch)
 	if err != nil {
 		return "", fmt.Errorf("failed to get diff from base: %w", err)
 	}
-	return string(output), nil
+	return res.Stdout, nil
 }
 
 // GetBranchRemoteURL constructs a GitHub URL for a given branch
@@ -1239,11 +1148,14 @@ func (m *Manager) GetBranchRemoteURL(branchName string) (string, error) {
 	// Convert SSH URL to HTTPS if needed
 	url = convertSSHToHTTPS(url)
 
-	// Check if branch exists on remote
-	checkCmd := exec.Command("git", "-C", m.repoPath, "ls-remote", "--heads", "origin", branchName)
-	output, err := checkCmd.Output()
-	if err == nil && len(strings.TrimSpace(string(output))) > 0 {
+	// Check if branch exists on remote (a fork in a fork-based workflow)
+	push := m.PushRemote()
+	res, err := m.run(m.repoPath, "ls-remote", "--heads", push, branchName)
+	if err == nil && res.Trimmed() != "" {
 		// Branch exists on remote, return branch URL
+		if pushURL, err := m.remoteURL(push); err == nil {
+			url = convertSSHToHTTPS(pushURL)
+		}
 		return fmt.Sprintf("%s/tree/%s", url, branchName), nil
 	}
 
@@ -1253,46 +1165,42 @@ func (m *Manager) GetBranchRemoteURL(branchName string) (string, error) {
 
 // GetCurrentUser returns the current git user name
 func (m *Manager) GetCurrentUser(worktreePath string) (string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "config", "user.name")
-	output, err := cmd.Output()
+	res, err := m.run(worktreePath, "config", "user.name")
 	if err != nil {
 		return "", fmt.Errorf("failed to get git user: %w", err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
 // GetStatus returns the git status for a worktree
 func (m *Manager) GetStatus(worktreePath string) (string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "status")
-	output, err := cmd.Output()
+	res, err := m.run(worktreePath, "status")
 	if err != nil {
 		return "", fmt.Errorf("failed to get git status: %w", err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
 // GetCurrentBranchForWorktree returns the current branch name for a specific worktree
 func (m *Manager) GetCurrentBranchForWorktree(worktreePath string) (string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "branch", "--show-current")
-	output, err := cmd.Output()
+	res, err := m.run(worktreePath, "branch", "--show-current")
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
 // GetRecentCommits returns the recent commit log (last 10 commits, one line each)
 func (m *Manager) GetRecentCommits(worktreePath string) (string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "log", "--oneline", "-10")
-	output, err := cmd.Output()
+	res, err := m.run(worktreePath, "log", "--oneline", "-10")
 	if err != nil {
 		return "", fmt.Errorf("failed to get recent commits: %w", err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return res.Trimmed(), nil
 }
 
 // convertSSHToHTTPS converts SSH git URL to HTTPS format
@@ -0,0 +1,286 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coollabsio/jean/config"
+)
+
+func TestListLightweight_ParsesPorcelain(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/feature\nHEAD 2222222222222222222222222222222222222222\nbranch refs/heads/feature/x\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+
+	m := NewManagerWithRunner("/repo", runner)
+	worktrees, err := m.ListLightweight()
+	if err != nil {
+		t.Fatalf("ListLightweight: %v", err)
+	}
+
+	if len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees, got %d", len(worktrees))
+	}
+	if worktrees[0].Branch != "main" || !worktrees[0].IsCurrent {
+		t.Errorf("expected current main worktree, got %+v", worktrees[0])
+	}
+	if worktrees[1].Branch != "feature/x" || worktrees[1].IsCurrent {
+		t.Errorf("expected feature/x worktree, got %+v", worktrees[1])
+	}
+	if runner.Ran("status") {
+		t.Error("lightweight listing should not compute status")
+	}
+}
+
+func TestMergeBranch_ConflictIsErrMergeConflict(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.OnError(1, "CONFLICT (content): Merge conflict in main.go\nAutomatic merge failed; fix conflicts and then commit the result.\n",
+		"merge", "main")
+
+	m := NewManagerWithRunner("/repo", runner)
+	err := m.MergeBranch("/repo/.workspaces/feature", "main")
+	if !errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected ErrMergeConflict, got %v", err)
+	}
+}
+
+func TestMergeBranch_OtherFailureKeepsGitOutput(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.OnError(128, "fatal: refusing to merge unrelated histories\n", "merge", "main")
+
+	m := NewManagerWithRunner("/repo", runner)
+	err := m.MergeBranch("/repo", "main")
+	if err == nil || errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected a plain merge error, got %v", err)
+	}
+	if got, want := err.Error(), "failed to merge: fatal: refusing to merge unrelated histories"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestPush_WithoutRemoteIsErrNoRemote(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.OnError(2, "error: No such remote 'origin'\n", "remote", "get-url", "origin")
+
+	m := NewManagerWithRunner("/repo", runner)
+	err := m.Push("/repo", "feature")
+	if !errors.Is(err, ErrNoRemote) {
+		t.Fatalf("expected ErrNoRemote, got %v", err)
+	}
+	if runner.Ran("push") {
+		t.Error("push should not run without a remote")
+	}
+}
+
+func TestCreate_ExistingBranchIsErrBranchExists(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On(t.TempDir()+"\n", "rev-parse", "--show-toplevel")
+	runner.OnError(255, "fatal: a branch named 'feature' already exists\n", "worktree", "add")
+
+	m := NewManagerWithRunner("/repo", runner)
+	err := m.Create("/repo/.workspaces/feature", "feature", true, "")
+	if !errors.Is(err, ErrBranchExists) {
+		t.Fatalf("expected ErrBranchExists, got %v", err)
+	}
+}
+
+func TestCreateCommit_ReturnsHashFromRevParse(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("[feature abc1234] Add thing\n 1 file changed\n", "commit")
+	runner.On("abc1234def5678abc1234def5678abc1234def56\n", "rev-parse", "HEAD")
+
+	m := NewManagerWithRunner("/repo", runner)
+	hash, err := m.CreateCommit("/repo", "Add thing")
+	if err != nil {
+		t.Fatalf("CreateCommit: %v", err)
+	}
+	if hash != "abc1234def5678abc1234def5678abc1234def56" {
+		t.Errorf("unexpected hash %q", hash)
+	}
+	if !runner.Ran("add", "-A") {
+		t.Error("expected changes to be staged")
+	}
+}
+
+func TestPullBranch_WithoutRemoteMergesLocally(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.OnError(2, "error: No such remote 'origin'\n", "remote", "get-url", "origin")
+	runner.On("abc1234\n", "rev-parse", "HEAD")
+
+	m := NewManagerWithRunner("/repo", runner)
+	result, err := m.PullBranch("/repo", "main")
+	if err != nil {
+		t.Fatalf("PullBranch: %v", err)
+	}
+	if !result.UpToDate || result.Commits != 0 {
+		t.Errorf("expected up to date result, got %+v", result)
+	}
+	if !runner.Ran("merge", "main", "--no-edit") || runner.Ran("pull") {
+		t.Error("expected a local merge instead of a pull")
+	}
+}
+
+func TestPullBranch_ConflictIsErrMergeConflict(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("git@github.com:owner/repo.git\n", "remote", "get-url", "origin")
+	runner.OnError(1, "CONFLICT (content): Merge conflict in main.go\n", "pull", "origin", "main")
+
+	m := NewManagerWithRunner("/repo", runner)
+	if _, err := m.PullBranch("/repo", "main"); !errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected ErrMergeConflict, got %v", err)
+	}
+}
+
+func TestProtectedBranches_FromJeanJSON(t *testing.T) {
+	repoRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoRoot, "jean.json"), []byte(`{"protected": ["trunk", "release/*"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	runner.On("origin\nupstream\n", "remote")
+	m := NewManagerWithRunner(repoRoot, runner)
+
+	for branch, want := range map[string]bool{
+		"trunk": true, "release/1.2": true, "release/1.2/hotfix": false, "main": false, "feature": false,
+		"upstream/trunk": true, "origin/release/1.2": true, "upstream/feature": false,
+	} {
+		if got := m.IsProtectedBranch(branch); got != want {
+			t.Errorf("IsProtectedBranch(%q) = %v, want %v", branch, got, want)
+		}
+	}
+
+	if err := m.DeleteBranch("release/1.2"); !errors.Is(err, ErrProtectedBranch) {
+		t.Errorf("expected DeleteBranch to refuse a protected branch, got %v", err)
+	}
+	if err := m.RenameBranch("trunk", "other"); !errors.Is(err, ErrProtectedBranch) {
+		t.Errorf("expected RenameBranch to refuse a protected branch, got %v", err)
+	}
+	if runner.Ran("branch") {
+		t.Error("no branch command should run for a protected branch")
+	}
+}
+
+func TestInvalidJeanJSONEntries_AreSkipped(t *testing.T) {
+	repoRoot := t.TempDir()
+	data := `{"hooks": {"pre_push": "echo linted", "post_push": "make"}, "scripts": {"dev": 1}, "protected": ["trunk"]}`
+	if err := os.WriteFile(filepath.Join(repoRoot, "jean.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	m := NewManagerWithRunner(repoRoot, runner)
+
+	if !m.IsProtectedBranch("trunk") || m.IsProtectedBranch("main") {
+		t.Error("expected the protected list of jean.json to apply, not the defaults")
+	}
+	output, err := m.RunHook(config.HookPrePush, HookContext{})
+	if err != nil || strings.TrimSpace(output) != "linted" {
+		t.Errorf("expected the valid pre_push hook to run, got %q (%v)", output, err)
+	}
+	if _, err := m.RunScript("dev", HookContext{}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected the invalid dev script to be skipped, got %v", err)
+	}
+}
+
+func TestJeanHook_OnlySetForHooks(t *testing.T) {
+	repoRoot := t.TempDir()
+	data := `{"hooks": {"pre_push": "echo \"hook=$JEAN_HOOK\""}, "scripts": {"dev": "echo \"hook=$JEAN_HOOK\""}}`
+	if err := os.WriteFile(filepath.Join(repoRoot, "jean.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	m := NewManagerWithRunner(repoRoot, runner)
+
+	if output, err := m.RunHook(config.HookPrePush, HookContext{}); err != nil || strings.TrimSpace(output) != "hook=pre_push" {
+		t.Errorf("expected JEAN_HOOK=pre_push in the hook, got %q (%v)", output, err)
+	}
+	if output, err := m.RunScript("dev", HookContext{}); err != nil || strings.TrimSpace(output) != "hook=" {
+		t.Errorf("expected no JEAN_HOOK in a named script, got %q (%v)", output, err)
+	}
+}
+
+func TestRemove_KeepsProtectedBranch(t *testing.T) {
+	repoRoot := t.TempDir()
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	runner.On("main\n", "branch", "--show-current")
+
+	m := NewManagerWithRunner(repoRoot, runner)
+	if err := m.Remove(filepath.Join(repoRoot, ".workspaces", "main"), false); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if !runner.Ran("worktree", "remove") {
+		t.Error("expected the worktree to be removed")
+	}
+	if runner.Ran("branch", "-D") {
+		t.Error("protected branch should not be deleted")
+	}
+}
+
+func TestRemove_LockedWorktreeNeedsForce(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("worktree /repo\nHEAD 1111111111111111111111111111111111111111\nbranch refs/heads/main\n\n"+
+		"worktree /repo/.workspaces/agent\nHEAD 2222222222222222222222222222222222222222\nbranch refs/heads/agent\nlocked long-running agent\n\n",
+		"worktree", "list", "--porcelain")
+	runner.On("/repo\n", "rev-parse", "--show-toplevel")
+
+	m := NewManagerWithRunner("/repo", runner)
+	worktrees, err := m.ListLightweight()
+	if err != nil {
+		t.Fatalf("ListLightweight: %v", err)
+	}
+	if !worktrees[1].Locked || worktrees[1].LockReason != "long-running agent" {
+		t.Fatalf("expected a locked worktree with its reason, got %+v", worktrees[1])
+	}
+
+	if err := m.Remove("/repo/.workspaces/agent", false); !errors.Is(err, ErrWorktreeLocked) {
+		t.Fatalf("expected ErrWorktreeLocked, got %v", err)
+	}
+	if runner.Ran("worktree", "remove") {
+		t.Fatal("locked worktree should not be removed without force")
+	}
+
+	if err := m.Remove("/repo/.workspaces/agent", true); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if !runner.Ran("worktree", "remove", "--force", "--force", "/repo/.workspaces/agent") {
+		t.Errorf("expected a double forced removal, got %v", runner.Calls())
+	}
+}
+
+func TestCreateWithOptions_SetupErrorStage(t *testing.T) {
+	repoRoot := t.TempDir()
+	runner := NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+	m := NewManagerWithRunner(repoRoot, runner)
+	path := filepath.Join(repoRoot, DefaultWorktreeRoot, "feature")
+
+	for _, tc := range []struct {
+		jeanJSON string
+		stage    string
+	}{
+		{`{"scripts": {"setup": "exit 3"}, "hooks": {"post_create": "true"}}`, "setup"},
+		{`{"scripts": {"setup": "true"}, "hooks": {"post_create": "echo nope; exit 1"}}`, "post_create"},
+	} {
+		if err := os.WriteFile(filepath.Join(repoRoot, "jean.json"), []byte(tc.jeanJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+		err := m.CreateWithOptions(path, "feature", true, "", CreateOptions{})
+		var setupErr *SetupError
+		if !errors.As(err, &setupErr) || setupErr.Stage != tc.stage {
+			t.Errorf("expected a *SetupError for the %s stage, got %v", tc.stage, err)
+			continue
+		}
+		var hookErr *HookError
+		if isHook := errors.As(err, &hookErr); isHook != (tc.stage == "post_create") {
+			t.Errorf("expected only a post_create failure to wrap a *HookError, got %v", err)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Manager handles GitHub operations using gh CLI
@@ -22,6 +24,13 @@ type PRInfo struct {
 	} `json:"author"`
 }
 
+// PROptions holds the optional settings of a new pull request
+type PROptions struct {
+	Draft     bool
+	Repo      string // Repository to open the PR against (owner/name), "" for gh's default
+	HeadOwner string // Owner of the fork the branch was pushed to (--head owner:branch), "" if it's in Repo
+}
+
 // NewManager creates a new GitHub manager
 func NewManager() *Manager {
 	return &Manager{}
@@ -47,8 +56,28 @@ func (m *Manager) IsAuthenticated() (bool, error) {
 	return true, nil
 }
 
+// TitleFromBranch returns the default PR title of a branch: "fix-login_page" becomes "Fix Login Page"
+func TitleFromBranch(branch string) string {
+	title := strings.ReplaceAll(branch, "-", " ")
+	title = strings.ReplaceAll(title, "_", " ")
+	words := strings.Split(title, " ")
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		if size == 0 {
+			continue
+		}
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
 // CreatePR creates a pull request (draft or ready for review)
 func (m *Manager) CreatePR(worktreePath, branch, baseBranch, title, description string, isDraft bool) (string, error) {
+	return m.CreatePRWithOptions(worktreePath, branch, baseBranch, title, description, PROptions{Draft: isDraft})
+}
+
+// CreatePRWithOptions is CreatePR with the repositories of a fork-based workflow
+func (m *Manager) CreatePRWithOptions(worktreePath, branch, baseBranch, title, description string, opts PROptions) (string, error) {
 	// Check if gh is installed
 	if !m.IsGhInstalled() {
 		return "", fmt.Errorf("gh CLI is not installed. Install it from https://cli.github.com")
@@ -63,17 +92,27 @@ func (m *Manager) CreatePR(worktreePath, branch, baseBranch, title, description
 		return "", fmt.Errorf("not authenticated with GitHub. Run 'gh auth login' to authenticate")
 	}
 
+	// A branch of a fork is referred to as owner:branch
+	head := branch
+	if opts.HeadOwner != "" {
+		head = opts.HeadOwner + ":" + branch
+	}
+
 	// Create PR with title and description
 	args := []string{
 		"pr", "create",
 		"--base", baseBranch,
-		"--head", branch,
+		"--head", head,
 		"--title", title,
 		"--body", description,
 	}
 
+	if opts.Repo != "" {
+		args = append(args, "--repo", opts.Repo)
+	}
+
 	// Add draft flag if requested
-	if isDraft {
+	if opts.Draft {
 		args = append(args, "--draft")
 	}
 
@@ -255,15 +294,13 @@ func (m *Manager) ListPRs(worktreePath string) ([]PRInfo, error) {
 	cmd.Dir = worktreePath
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		errMsg := fmt.Sprintf("failed to list PRs: %s", string(output))
-		return nil, fmt.Errorf(errMsg)
+		return nil, fmt.Errorf("failed to list PRs: %s", string(output))
 	}
 
 	// Parse JSON response
 	var prs []PRInfo
 	if err := json.Unmarshal(output, &prs); err != nil {
-		errMsg := fmt.Sprintf("failed to parse PR list: %v", err)
-		return nil, fmt.Errorf(errMsg)
+		return nil, fmt.Errorf("failed to parse PR list: %v", err)
 	}
 
 	return prs, nil
@@ -0,0 +1,22 @@
+package github
+
+import "testing"
+
+func TestTitleFromBranch(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{branch: "add-dark-mode", want: "Add Dark Mode"},
+		{branch: "fix-login_page", want: "Fix Login Page"},
+		{branch: "feature/user-API", want: "Feature/user API"},
+		{branch: "über-cache", want: "Über Cache"},
+		{branch: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := TitleFromBranch(tt.branch); got != tt.want {
+			t.Errorf("TitleFromBranch(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
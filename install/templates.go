@@ -89,6 +89,12 @@ jean() {
                 # Different session - fall through to switch to it
             fi
 
+            # Script windows (started from jean's script picker) are attached by name
+            if [[ "$target_window" == script-* ]] && tmux has-session -t "=$session_name" 2>/dev/null; then
+                tmux attach-session -t "$session_name:$target_window"
+                continue
+            fi
+
             # Set window index based on target window
             # Note: with base-index 1, windows are 1, 2, 3... instead of 0, 1, 2...
             local window_index="1"
@@ -245,6 +251,12 @@ function jean
                     return
                 end
 
+                # Script windows (started from jean's script picker) are attached by name
+                if string match -q 'script-*' -- "$target_window"; and tmux has-session -t "=$session_name" 2>/dev/null
+                    tmux attach-session -t "$session_name:$target_window"
+                    continue
+                end
+
                 # Set window index based on target window
                 # Note: with base-index 1, windows are 1, 2, 3... instead of 0, 1, 2...
                 set window_index "1"
@@ -53,11 +53,12 @@ func main() {
 	}
 
 	// Auto-initialize shell integration if not already done
-	// Skip this check for init, version, help, and if already attempted (prevent infinite loop)
+	// Skip this check for init, version, help, the scriptable subcommands (they must not
+	// re-exec through the user's shell in CI), and if already attempted (prevent infinite loop)
 	shouldCheckInit := true
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
-		case "init", "version", "help":
+		case "init", "version", "help", "list", "new", "rm", "gc", "lock", "unlock", "adopt", "push", "pr":
 			shouldCheckInit = false
 		}
 	}
@@ -78,6 +79,36 @@ func main() {
 		case "update":
 			handleUpdate()
 			return
+		case "list":
+			handleList()
+			return
+		case "new":
+			handleNew()
+			return
+		case "rm":
+			handleRm()
+			return
+		case "gc":
+			handleGC()
+			return
+		case "lock":
+			handleLock(true)
+			return
+		case "unlock":
+			handleLock(false)
+			return
+		case "adopt":
+			handleAdopt()
+			return
+		case "switch":
+			handleSwitch()
+			return
+		case "push":
+			handlePush()
+			return
+		case "pr":
+			handlePR()
+			return
 		case "version":
 			fmt.Printf("jean version %s\n", version.CliVersion)
 			os.Exit(0)
@@ -130,42 +161,51 @@ func main() {
 	if m, ok := finalModel.(tui.Model); ok {
 		switchInfo := m.GetSwitchInfo()
 		if switchInfo.Path != "" {
-			// Format: path|branch|auto-claude|target-window|script-command|session-name|is-claude-initialized
-			autoCl := "false"
-			if switchInfo.AutoClaude {
-				autoCl = "true"
-			}
-			targetWindow := switchInfo.TargetWindow
-			if targetWindow == "" {
-				targetWindow = "terminal" // Default to terminal window if not set
-			}
-			isInitialized := "false"
-			if switchInfo.IsClaudeInitialized {
-				isInitialized = "true"
-			}
-			switchData := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s", switchInfo.Path, switchInfo.Branch, autoCl, targetWindow, switchInfo.ScriptCommand, switchInfo.SessionName, isInitialized)
-
-			// Debug: log what we're writing
-			debugLog(fmt.Sprintf("DEBUG main: switchInfo={Path:%q Branch:%q AutoClaude:%v TargetWindow:%q SessionName:%q}", switchInfo.Path, switchInfo.Branch, switchInfo.AutoClaude, switchInfo.TargetWindow, switchInfo.SessionName))
-			debugLog(fmt.Sprintf("DEBUG main: switchData=%q (has %d fields)", switchData, strings.Count(switchData, "|")+1))
-
-			// Check if we should write to a file (for shell wrapper integration)
-			if switchFile := os.Getenv("JEAN_SWITCH_FILE"); switchFile != "" {
-				// Write to file for shell wrapper
-				if err := os.WriteFile(switchFile, []byte(switchData), 0600); err != nil {
-					debugLog(fmt.Sprintf("Warning: could not write switch file: %v", err))
-				}
-				// Verify what was written
-				contents, _ := os.ReadFile(switchFile)
-				debugLog(fmt.Sprintf("DEBUG main: file contents=%q", string(contents)))
-			} else {
-				// Print to stdout (legacy behavior)
-				fmt.Println(switchData)
+			// stderr, stdout may carry the switch data
+			if switchInfo.HookOutput != "" {
+				fmt.Fprintln(os.Stderr, switchInfo.HookOutput)
 			}
+			writeSwitchInfo(switchInfo)
 		}
 	}
 }
 
+// writeSwitchInfo hands the switch info to the shell wrapper (or prints it to stdout)
+func writeSwitchInfo(switchInfo tui.SwitchInfo) {
+	// Format: path|branch|auto-claude|target-window|script-command|session-name|is-claude-initialized
+	autoCl := "false"
+	if switchInfo.AutoClaude {
+		autoCl = "true"
+	}
+	targetWindow := switchInfo.TargetWindow
+	if targetWindow == "" {
+		targetWindow = "terminal" // Default to terminal window if not set
+	}
+	isInitialized := "false"
+	if switchInfo.IsClaudeInitialized {
+		isInitialized = "true"
+	}
+	switchData := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s", switchInfo.Path, switchInfo.Branch, autoCl, targetWindow, switchInfo.ScriptCommand, switchInfo.SessionName, isInitialized)
+
+	// Debug: log what we're writing
+	debugLog(fmt.Sprintf("DEBUG main: switchInfo={Path:%q Branch:%q AutoClaude:%v TargetWindow:%q SessionName:%q}", switchInfo.Path, switchInfo.Branch, switchInfo.AutoClaude, switchInfo.TargetWindow, switchInfo.SessionName))
+	debugLog(fmt.Sprintf("DEBUG main: switchData=%q (has %d fields)", switchData, strings.Count(switchData, "|")+1))
+
+	// Check if we should write to a file (for shell wrapper integration)
+	if switchFile := os.Getenv("JEAN_SWITCH_FILE"); switchFile != "" {
+		// Write to file for shell wrapper
+		if err := os.WriteFile(switchFile, []byte(switchData), 0600); err != nil {
+			debugLog(fmt.Sprintf("Warning: could not write switch file: %v", err))
+		}
+		// Verify what was written
+		contents, _ := os.ReadFile(switchFile)
+		debugLog(fmt.Sprintf("DEBUG main: file contents=%q", string(contents)))
+	} else {
+		// Print to stdout (legacy behavior)
+		fmt.Println(switchData)
+	}
+}
+
 // ensureShellIntegration checks if shell integration is installed and active.
 // Automatically installs or updates wrapper if needed using checksum comparison.
 // Returns nil if wrapper is already active, otherwise performs init/update and re-exec.
@@ -358,10 +398,21 @@ integration across multiple branches effortlessly.
 USAGE:
     jean [OPTIONS]
     jean init [FLAGS]
+    jean <command> [ARGS] [-path <path>] [-json]
 
 COMMANDS:
     init            Install or manage jean shell integration
     update          Update jean to the latest version
+    list            List worktrees with ahead/behind, uncommitted changes and PRs
+    new [name]      Create a worktree with a new branch (random name if omitted)
+    rm <branch>     Remove a worktree, its branch and its tmux session
+    gc              Remove worktrees with merged/closed PRs, merged branches or no recent changes
+    lock [branch]   Lock a worktree so rm and gc refuse to remove it (default: worktree in -path)
+    unlock [branch] Unlock a worktree
+    adopt [branch]  Manage a worktree added with git worktree add (branch or path, default: worktree in -path)
+    switch <branch> Switch to a worktree's tmux session (requires shell integration)
+    push [branch]   Push a worktree's branch (default: worktree in -path)
+    pr [branch]     Push and open a pull request for a worktree's branch
     help            Show this help message
     version         Print version and exit
 
@@ -371,6 +422,23 @@ MAIN OPTIONS:
     -help           Show this help message
     -version        Print version and exit
 
+WORKTREE COMMAND FLAGS:
+    -path <path>    Path to git repository (default: current directory)
+    -json           Print machine-readable JSON output
+    -base <branch>  (new) Base branch for the new branch
+    -at <ref>       (new) Create a worktree at a tag or commit (detached HEAD) instead of a branch
+    -sparse <name>  (new) Check out only the directories of a sparse-checkout profile of jean.json
+    -force          (rm, gc) Remove even with uncommitted changes or a lock
+    -reason <text>  (lock) Why the worktree is locked
+    -move           (adopt) Move the worktree to where jean creates worktrees
+    -days <n>       (gc) Days without changes before a worktree is stale (0 disables, default: 30)
+    -yes            (gc) Remove the stale worktrees instead of only listing them
+    -terminal       (switch) Attach to the terminal window instead of Claude
+    -no-claude      (switch) Don't auto-start Claude CLI
+    -title <title>  (pr) PR title (default: derived from branch name)
+    -body <text>    (pr) PR description
+    -draft/-ready   (pr) Override the repository's default PR state
+
 INIT COMMAND FLAGS:
     -update         Update existing jean integration
     -remove         Remove jean integration
@@ -425,6 +493,12 @@ EXAMPLES:
     # Remove shell integration
     jean init --remove
 
+    # Script worktrees from CI
+    jean new feature-login -json
+    jean new -at v1.2.0
+    jean list -json | jq '.[] | select(.has_uncommitted)'
+    jean pr feature-login -draft
+
 For more information, visit: https://github.com/coollabsio/jean
 `, version.CliVersion)
 }
@@ -57,10 +57,11 @@ func NewClient(apiKey, model string) *Client {
 }
 
 // GenerateCommitMessage generates a one-line conventional commit message based on git context
+// With withBody, the AI is also asked for a short body explaining the change (may still be empty)
 // If customPrompt is empty, uses the default prompt
-func (c *Client) GenerateCommitMessage(status, diff, branch, log, customPrompt string) (subject string, err error) {
+func (c *Client) GenerateCommitMessage(status, diff, branch, log, customPrompt string, withBody bool) (subject, body string, err error) {
 	if c.apiKey == "" {
-		return "", fmt.Errorf("OpenRouter API key not configured")
+		return "", "", fmt.Errorf("OpenRouter API key not configured")
 	}
 
 	// Limit diff to reasonable size to avoid token limits
@@ -79,19 +80,26 @@ func (c *Client) GenerateCommitMessage(status, diff, branch, log, customPrompt s
 	prompt = strings.ReplaceAll(prompt, "{diff}", diff)
 	prompt = strings.ReplaceAll(prompt, "{branch}", branch)
 	prompt = strings.ReplaceAll(prompt, "{log}", log)
+	if withBody {
+		prompt += "\n\n" + CommitBodyInstruction
+	}
 
 	response, err := c.callAPI(prompt)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	// Parse plain text response (no JSON)
-	subject = strings.TrimSpace(response)
+	// Parse plain text response (no JSON): subject line, then the optional body
+	lines := strings.SplitN(strings.TrimSpace(response), "\n", 2)
+	subject = strings.TrimSpace(lines[0])
 	if subject == "" {
-		return "", fmt.Errorf("AI generated empty commit subject")
+		return "", "", fmt.Errorf("AI generated empty commit subject")
+	}
+	if withBody && len(lines) == 2 {
+		body = strings.TrimSpace(lines[1])
 	}
 
-	return subject, nil
+	return subject, body, nil
 }
 
 // GenerateBranchName generates a semantic branch name based on git diff
@@ -34,6 +34,11 @@ Requirements:
 - Concise description in lowercase
 - No period at the end`
 
+	// CommitBodyInstruction is appended to the commit prompt (default or custom) when a body is wanted
+	CommitBodyInstruction = `Additionally, after the commit message line, add one empty line followed by a short body
+(1-4 lines, wrapped at 72 characters) explaining what changed and why. The single-line requirement
+applies to the first line only. Do not add trailers such as Signed-off-by or Co-authored-by.`
+
 	// DefaultBranchNamePrompt generates a semantic branch name from git diff
 	// The {diff} placeholder will be replaced with the actual git diff
 	DefaultBranchNamePrompt = `Generate a short, semantic git branch name for these changes.
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// scriptWindowPrefix names the windows of jean.json scripts. The shell wrappers in
+// install/templates.go match it (script-*) to select the window, keep them in sync.
+const scriptWindowPrefix = "script-"
+
+// ScriptWindow represents a jean.json script running in its own tmux window
+type ScriptWindow struct {
+	Name     string // tmux window name (script- prefix + sanitized script name)
+	Running  bool   // False once the command has exited (window kept by remain-on-exit)
+	ExitCode int    // Exit status of the command, only meaningful when not running
+}
+
+// ScriptWindowName returns the tmux window name used for a jean.json script
+func (m *Manager) ScriptWindowName(script string) string {
+	return scriptWindowPrefix + m.SanitizeBranchName(script)
+}
+
+// RunScript runs a command in a dedicated, named window of the worktree session.
+// The session is created (detached) in path if it doesn't exist yet, a previous window for the
+// same script is replaced, and remain-on-exit keeps the window and its output around
+// after the command ends so its exit status can be shown. The command runs in dir.
+func (m *Manager) RunScript(sessionName, path, dir, script, command string, env []string) error {
+	if !m.SessionExists(sessionName) {
+		cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", path, "-n", "terminal")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create session: %s", string(output))
+		}
+	}
+
+	windowName := m.ScriptWindowName(script)
+	target := sessionName + ":" + windowName
+
+	// Replace a previous run of the same script
+	_ = m.StopScript(sessionName, script)
+
+	// Create the window with a plain shell first so remain-on-exit is set before the
+	// command starts (a fast-failing command would otherwise close the window)
+	cmd := exec.Command("tmux", "new-window", "-d", "-t", sessionName+":", "-n", windowName, "-c", dir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create window: %s", string(output))
+	}
+
+	cmd = exec.Command("tmux", "set-option", "-w", "-t", target, "remain-on-exit", "on")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to configure window: %s", string(output))
+	}
+
+	args := []string{"respawn-pane", "-k", "-t", target, "-c", dir}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+	args = append(args, command)
+	cmd = exec.Command("tmux", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start script: %s", string(output))
+	}
+
+	return nil
+}
+
+// StopScript kills the window of a jean.json script
+func (m *Manager) StopScript(sessionName, script string) error {
+	cmd := exec.Command("tmux", "kill-window", "-t", sessionName+":"+m.ScriptWindowName(script))
+	return cmd.Run()
+}
+
+// ListScriptWindows returns the script windows of a session with their running/exited state
+// Returns an empty list if the session doesn't exist
+func (m *Manager) ListScriptWindows(sessionName string) ([]ScriptWindow, error) {
+	cmd := exec.Command("tmux", "list-windows", "-t", sessionName, "-F", "#{window_name}\t#{pane_dead}\t#{pane_dead_status}")
+	output, err := cmd.Output()
+	if err != nil {
+		// No such session
+		return []ScriptWindow{}, nil
+	}
+
+	return parseScriptWindows(string(output)), nil
+}
+
+// parseScriptWindows parses the output of tmux list-windows for ListScriptWindows, keeping the
+// windows of scripts only
+func parseScriptWindows(output string) []ScriptWindow {
+	var windows []ScriptWindow
+	// Only trim newlines, pane_dead_status is empty (trailing tab) for running panes
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 3 || !strings.HasPrefix(parts[0], scriptWindowPrefix) {
+			continue
+		}
+
+		window := ScriptWindow{
+			Name:    parts[0],
+			Running: parts[1] != "1",
+		}
+		if !window.Running {
+			window.ExitCode, _ = strconv.Atoi(parts[2])
+		}
+		windows = append(windows, window)
+	}
+
+	return windows
+}
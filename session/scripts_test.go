@@ -0,0 +1,46 @@
+package session
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScriptWindows(t *testing.T) {
+	output := "claude\t0\t\n" +
+		"terminal\t0\t\n" +
+		"script-dev\t0\t\n" +
+		"script-test\t1\t2\n" +
+		"script-lint\t1\t0\n" +
+		"script-broken\n"
+
+	want := []ScriptWindow{
+		{Name: "script-dev", Running: true},
+		{Name: "script-test", ExitCode: 2},
+		{Name: "script-lint"},
+	}
+	if got := parseScriptWindows(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected windows %+v, got %+v", want, got)
+	}
+	if got := parseScriptWindows(""); len(got) != 0 {
+		t.Errorf("expected no windows, got %+v", got)
+	}
+}
+
+func TestScriptWindowName_MatchesShellWrappers(t *testing.T) {
+	m := NewManager()
+	name := m.ScriptWindowName("dev server")
+	if !strings.HasPrefix(name, scriptWindowPrefix) {
+		t.Fatalf("expected the %s prefix, got %q", scriptWindowPrefix, name)
+	}
+
+	// The shell wrappers select the window of a script by this prefix
+	templates, err := os.ReadFile("../install/templates.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(templates), scriptWindowPrefix+"*") {
+		t.Errorf("expected the shell wrappers to match %s*", scriptWindowPrefix)
+	}
+}
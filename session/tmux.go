@@ -378,7 +378,9 @@ func (m *Manager) NewWindowAndAttach(sessionName, path string) error {
 
 // List returns all jean tmux sessions, optionally filtered by repository path
 // If repoPath is empty string, returns all jean sessions
-func (m *Manager) List(repoPath string) ([]Session, error) {
+// worktreePaths adds the sessions of worktrees living outside the repository directory
+// (custom worktree root, adopted worktrees)
+func (m *Manager) List(repoPath string, worktreePaths ...string) ([]Session, error) {
 	// List all sessions with format: name:windows:attached:activity:path
 	// activity is the maximum window_activity timestamp in the session
 	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}:#{session_windows}:#{session_attached}:#{session_activity}:#{session_path}")
@@ -388,7 +390,14 @@ func (m *Manager) List(repoPath string) ([]Session, error) {
 		return []Session{}, nil
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return parseSessions(string(output), repoPath, worktreePaths), nil
+}
+
+// parseSessions parses the output of tmux list-sessions for List. Sessions are kept when
+// rooted in the repository or one of the worktrees: a bare prefix match would also keep the
+// sessions of /code/app-web for /code/app, and Orphaned would have them killed.
+func parseSessions(output, repoPath string, worktreePaths []string) []Session {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
 	var sessions []Session
 
 	for _, line := range lines {
@@ -405,7 +414,7 @@ func (m *Manager) List(repoPath string) ([]Session, error) {
 		sessionPath := parts[4]
 
 		// Filter by repository path if provided
-		if repoPath != "" && !strings.HasPrefix(sessionPath, repoPath) {
+		if repoPath != "" && !isUnderAny(sessionPath, append([]string{repoPath}, worktreePaths...)) {
 			continue
 		}
 
@@ -434,7 +443,56 @@ func (m *Manager) List(repoPath string) ([]Session, error) {
 		})
 	}
 
-	return sessions, nil
+	return sessions
+}
+
+// isUnderAny checks if path is one of the given directories or inside one of them
+func isUnderAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateDetached creates a tmux session for a worktree without attaching to it, so it shows
+// up in jean right away. Does nothing if the session already exists.
+func (m *Manager) CreateDetached(sessionName, path string) error {
+	if m.SessionExists(sessionName) {
+		return nil
+	}
+	cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", path, "-n", "terminal")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create tmux session: %w", err)
+	}
+	return nil
+}
+
+// Orphaned returns the jean sessions of a repository that belong to none of the given branches,
+// e.g. because their worktree was deleted outside jean. Like List, sessions are matched by the
+// repository and the worktree paths, so worktrees outside the repository are included.
+func (m *Manager) Orphaned(repoPath string, branches []string, worktreePaths ...string) ([]Session, error) {
+	sessions, err := m.List(repoPath, worktreePaths...)
+	if err != nil {
+		return nil, err
+	}
+
+	repoName := filepath.Base(repoPath)
+	live := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		name := m.SanitizeName(repoName, branch)
+		live[name] = true
+		live[name+"-terminal"] = true // Older jean versions used a separate terminal session
+	}
+
+	var orphaned []Session
+	for _, sess := range sessions {
+		if !live[sess.Name] {
+			orphaned = append(orphaned, sess)
+		}
+	}
+	return orphaned, nil
 }
 
 // Kill terminates a tmux session and all its windows
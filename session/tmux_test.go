@@ -0,0 +1,75 @@
+package session
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSessions_FiltersByRepository(t *testing.T) {
+	output := "jean-app-main:1:0:1700000000:/code/app\n" +
+		"jean-app-feature:2:1:1700000000:/code/app/.workspaces/feature\n" +
+		"jean-app-web-main:1:0:1700000000:/code/app-web\n" +
+		"jean-app-hotfix:1:0:1700000000:/elsewhere/hotfix\n" +
+		"other:1:0:1700000000:/code/app\n"
+
+	sessions := parseSessions(output, "/code/app", []string{"/elsewhere/hotfix"})
+
+	var names []string
+	for _, s := range sessions {
+		names = append(names, s.Name)
+	}
+	want := []string{"jean-app-main", "jean-app-feature", "jean-app-hotfix"}
+	if len(names) != len(want) {
+		t.Fatalf("expected sessions %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected sessions %v, got %v", want, names)
+		}
+	}
+	if !sessions[1].Active || sessions[1].Windows != 2 {
+		t.Errorf("unexpected session %+v", sessions[1])
+	}
+}
+
+func TestOrphaned_WorktreesOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	// A private tmux server, torn down with its socket directory
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	repoPath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	elsewhere, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager()
+	repoName := filepath.Base(repoPath)
+	if err := m.CreateDetached(m.SanitizeName(repoName, "main"), repoPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CreateDetached(m.SanitizeName(repoName, "hotfix"), elsewhere); err != nil {
+		t.Fatal(err)
+	}
+
+	orphaned, err := m.Orphaned(repoPath, []string{"main"}, repoPath, elsewhere)
+	if err != nil {
+		t.Fatalf("Orphaned: %v", err)
+	}
+	if len(orphaned) != 1 || orphaned[0].Name != m.SanitizeName(repoName, "hotfix") {
+		t.Errorf("expected the session of hotfix outside the repository to be orphaned, got %+v", orphaned)
+	}
+
+	orphaned, err = m.Orphaned(repoPath, []string{"main", "hotfix"}, repoPath, elsewhere)
+	if err != nil || len(orphaned) != 0 {
+		t.Errorf("expected no orphaned sessions, got %+v (%v)", orphaned, err)
+	}
+}
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// syntax describes just enough of a language to color keywords, strings, comments and numbers
+// of single diff lines (no state is carried across lines, e.g. for block comments)
+type syntax struct {
+	lineComments []string
+	quotes       string
+	keywords     map[string]bool
+}
+
+func newSyntax(lineComments []string, quotes string, keywords string) *syntax {
+	s := &syntax{lineComments: lineComments, quotes: quotes, keywords: map[string]bool{}}
+	for _, keyword := range strings.Fields(keywords) {
+		s.keywords[keyword] = true
+	}
+	return s
+}
+
+var (
+	goSyntax = newSyntax([]string{"//", "/*"}, "\"'`",
+		"break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota")
+	jsSyntax = newSyntax([]string{"//", "/*"}, "\"'`",
+		"async await break case catch class const continue default delete do else export extends false finally for from function if import in instanceof interface let new null return static super switch this throw true try type typeof undefined var void while yield")
+	pythonSyntax = newSyntax([]string{"#"}, "\"'",
+		"and as assert async await break class continue def del elif else except False finally for from global if import in is lambda None nonlocal not or pass raise return self True try while with yield")
+	rustSyntax = newSyntax([]string{"//", "/*"}, "\"",
+		"as async await break const continue crate else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while")
+	cSyntax = newSyntax([]string{"//", "/*"}, "\"'",
+		"abstract auto break case catch char class const continue default do double else enum extends false final float for if implements import int long namespace new null nullptr package private protected public return short static struct super switch this throw true try typedef union unsigned val var void while")
+	shellSyntax = newSyntax([]string{"#"}, "\"'",
+		"case do done elif else esac export fi for function if in local return then until while")
+	rubySyntax = newSyntax([]string{"#"}, "\"'",
+		"begin class def do else elsif end ensure false if module nil require rescue return self then true unless until when while yield")
+	phpSyntax = newSyntax([]string{"//", "#", "/*"}, "\"'",
+		"array as break case catch class const continue default echo else elseif extends false foreach for function if implements interface namespace new null private protected public return static switch this throw true try use while")
+	configSyntax = newSyntax([]string{"#"}, "\"'", "true false null yes no on off")
+	jsonSyntax   = newSyntax(nil, "\"", "true false null")
+)
+
+// syntaxByExt maps file extensions to their syntax, files of other types are not highlighted
+var syntaxByExt = map[string]*syntax{
+	".go":    goSyntax,
+	".js":    jsSyntax,
+	".jsx":   jsSyntax,
+	".mjs":   jsSyntax,
+	".ts":    jsSyntax,
+	".tsx":   jsSyntax,
+	".vue":   jsSyntax,
+	".py":    pythonSyntax,
+	".rs":    rustSyntax,
+	".c":     cSyntax,
+	".h":     cSyntax,
+	".cc":    cSyntax,
+	".cpp":   cSyntax,
+	".hpp":   cSyntax,
+	".cs":    cSyntax,
+	".java":  cSyntax,
+	".kt":    cSyntax,
+	".swift": cSyntax,
+	".sh":    shellSyntax,
+	".bash":  shellSyntax,
+	".zsh":   shellSyntax,
+	".rb":    rubySyntax,
+	".php":   phpSyntax,
+	".yml":   configSyntax,
+	".yaml":  configSyntax,
+	".toml":  configSyntax,
+	".json":  jsonSyntax,
+}
+
+// syntaxForFile returns the syntax of a file by its extension, nil if unknown
+func syntaxForFile(path string) *syntax {
+	return syntaxByExt[strings.ToLower(filepath.Ext(path))]
+}
+
+// highlightDiffLine renders a line of a diff: hunk headers and the +/- markers in the theme's
+// colors, changed lines on a highlighted background and the code syntax-highlighted
+func highlightDiffLine(line string, lang *syntax) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	switch {
+	case strings.HasPrefix(line, "@@"):
+		return diffHunkHeaderStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffAddedStyle.Render("+") + highlightCode(line[1:], lang, diffChangedLineStyle)
+	case strings.HasPrefix(line, "-"):
+		return diffRemovedStyle.Render("-") + highlightCode(line[1:], lang, diffChangedLineStyle)
+	case strings.HasPrefix(line, "\\"):
+		// "\ No newline at end of file"
+		return syntaxCommentStyle.Render(line)
+	default:
+		return highlightCode(line, lang, diffContextStyle)
+	}
+}
+
+// highlightCode colors the keywords, strings, comments and numbers of a line of code
+func highlightCode(code string, lang *syntax, base lipgloss.Style) string {
+	if lang == nil {
+		return base.Render(code)
+	}
+
+	var b strings.Builder
+	runes := []rune(code)
+	plainStart := 0
+	flush := func(end int) {
+		if end > plainStart {
+			b.WriteString(base.Render(string(runes[plainStart:end])))
+		}
+	}
+	token := func(start, end int, style lipgloss.Style) int {
+		flush(start)
+		b.WriteString(style.Inherit(base).Render(string(runes[start:end])))
+		plainStart = end
+		return end
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case lang.isComment(runes[i:]):
+			// The comment runs to the end of the line
+			i = token(i, len(runes), syntaxCommentStyle)
+
+		case strings.ContainsRune(lang.quotes, r):
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			i = token(i, min(end+1, len(runes)), syntaxStringStyle)
+
+		case unicode.IsDigit(r) && (i == 0 || !isIdentRune(runes[i-1])):
+			end := i + 1
+			for end < len(runes) && (isIdentRune(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			i = token(i, end, syntaxNumberStyle)
+
+		case isIdentRune(r) && (i == 0 || !isIdentRune(runes[i-1])):
+			end := i + 1
+			for end < len(runes) && isIdentRune(runes[end]) {
+				end++
+			}
+			if lang.keywords[string(runes[i:end])] {
+				i = token(i, end, syntaxKeywordStyle)
+			} else {
+				i = end
+			}
+
+		default:
+			i++
+		}
+	}
+	flush(len(runes))
+	return b.String()
+}
+
+// isComment checks if a comment starts at the beginning of text
+func (s *syntax) isComment(text []rune) bool {
+	for _, prefix := range s.lineComments {
+		if strings.HasPrefix(string(text[:min(len(text), len(prefix))]), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"path/filepath"
@@ -24,10 +26,11 @@ type SwitchInfo struct {
 	Path                 string
 	Branch               string
 	AutoClaude           bool
-	TargetWindow         string // Which window to attach to: "terminal" or "claude"
+	TargetWindow         string // Which window to attach to: "terminal", "claude" or a "script-<name>" window
 	ScriptCommand        string // If set, run this script command instead of shell/Claude
 	SessionName          string // Custom name for Claude session (for --session flag)
 	IsClaudeInitialized  bool   // Whether this Claude session has been initialized before
+	HookOutput           string // Output of the post_switch hook, printed once the TUI exited
 }
 
 type modalType int
@@ -58,6 +61,19 @@ const (
 	prStateSettingsModal
 	onboardingModal
 	gitInitModal
+	scriptPickerModal
+	conflictModal
+	stashModal
+	stageModal
+	historyModal
+	diffModal
+	gcModal
+	lockModal
+	refSelectModal
+	worktreeLocationModal
+	adoptModal
+	sparseWidenModal
+	remotesModal
 )
 
 // NotificationType defines the type of notification
@@ -124,6 +140,11 @@ type Model struct {
 	searchInput            textinput.Model
 	sessionNameInput       textinput.Model // Session name input for new worktree
 	commitSubjectInput     textinput.Model // Subject line for commit message
+	commitWorktreePath     string          // Commit target if not the selected worktree (e.g. merge commit in main repo)
+	commitBodyInput        textarea.Model  // Optional commit body (paragraphs after the subject)
+	commitTrailersInput    textarea.Model  // Optional trailers, one per line (e.g. Co-authored-by, Refs #123)
+	commitStagedOnly       bool            // Commit what the staging modal staged instead of everything
+	commitSquashOnto       string          // Squash the commits ahead of this base branch instead (history modal)
 	prTitleInput           textinput.Model // PR title input
 	prDescriptionInput     textinput.Model // PR description input
 	prModalFocused         int             // Which field in PR modal is focused (0=title, 1=description, 2=create, 3=cancel)
@@ -132,6 +153,7 @@ type Model struct {
 	branchIndex            int
 	filteredBranches       []string // Filtered list of branches for search
 	createNewBranch        bool
+	createSparseProfile    string   // Sparse-checkout profile of jean.json for the new worktree, "" = full checkout
 	editorIndex            int      // Selected editor index
 	editors                []string // List of available editors
 	themeIndex             int      // Selected theme index
@@ -140,6 +162,7 @@ type Model struct {
 	settingsIndex          int           // Selected setting option index
 	deleteHasUncommitted   bool     // Whether worktree to delete has uncommitted changes
 	deleteConfirmForce     bool     // User acknowledged they want to delete despite uncommitted changes
+	deleteLocked           bool     // Whether worktree to delete is locked (also needs Force Delete)
 
 	// AI Settings modal state
 	aiSettingsIndex        int                    // Selected AI setting option index
@@ -219,6 +242,84 @@ type Model struct {
 	localMergeFocused    int    // Which button is focused (0=confirm, 1=cancel)
 	postMergeDeleteIndex int    // Selected option in post-merge cleanup (0=delete, 1=keep)
 
+	// Conflict modal state (merge or rebase stopped on conflicts)
+	conflictWorktree    string             // Worktree (or main repo) path with the conflicts
+	conflictOperation   string             // "merge" or "rebase"
+	conflictBranch      string             // Branch being updated
+	conflictOnto        string             // Ref merged in or rebased onto
+	conflictFiles       []git.ConflictFile // Files with unresolved conflicts
+	conflictResolutions map[string]string  // Path -> how it was resolved, summarized in the merge commit
+	conflictHadUpstream bool               // Rebase: force-push (with lease) once done
+	conflictLease       string             // Rebase: commit of the remote branch before fetching, "" if unknown
+	conflictCursor      int                // Selected file
+	conflictStash       string             // Message of the auto-stash holding uncommitted changes, "" if none
+	conflictLocalMerge  bool               // The conflicts stopped a local merge (L), completed once committed
+
+	// Stash modal state
+	stashes          []git.Stash     // All stashes of the repository
+	stashIndex       int             // Selected stash in the visible list
+	stashShowAll     bool            // Show the stashes of every branch, not only the worktree's
+	stashInput       textinput.Model // Message for a new stash
+	stashInputActive bool            // Typing the message for a new stash
+	stashDropConfirm bool            // 'd' was pressed once on the selected stash
+
+	// Staging modal state (pick the files and hunks of the next commit)
+	stageWorktree string            // Worktree whose changes are listed
+	stageFiles    []git.ChangedFile // Uncommitted changes relative to HEAD
+	stageSelected [][]bool          // Per file: selected hunks, a single entry for files staged as a whole
+	stageExpanded map[int]bool      // Files whose hunks are listed
+	stageCursor   int               // Selected row (file or hunk)
+
+	// Commit history modal state
+	historyWorktree      string          // Worktree whose commits are listed
+	historyBranch        string          // Branch of the worktree
+	historyCommits       []git.Commit    // Commits ahead of the base branch, newest first (nil while loading)
+	historyIndex         int             // Selected commit
+	historyHasUpstream   bool            // Branch was pushed, rewritten commits need a force-push
+	historyDiff          []string        // Lines of the commit shown in the diff pane, nil to show the list
+	historyDiffScroll    int             // First line shown in the diff pane
+	historyRewordInput   textinput.Model // New subject of the commit being reworded
+	historyRewording     bool            // Typing the new subject
+	historyRevertConfirm bool            // 'v' was pressed once on the selected commit
+
+	// Diff viewer state
+	diffWorktree    string            // Worktree whose changes are shown
+	diffAgainstBase bool              // Show the branch against the base branch instead of the uncommitted changes
+	diffFiles       []git.ChangedFile // Changed files (nil while loading)
+	diffFileIndex   int               // Selected file in the left pane
+	diffScroll      int               // First line shown in the right pane
+	diffFocusHunks  bool              // Keys scroll the hunks pane instead of selecting files
+
+	// Cleanup modal state (stale worktrees removed in one go)
+	gcWorktrees    []git.StaleWorktree // Stale worktrees found (nil while loading)
+	gcSelected     []bool              // Worktrees to remove, those with uncommitted changes or a lock start unselected
+	gcIndex        int                 // Selected row, the orphans row comes after the worktrees
+	gcRemoving     bool                // Removal in progress
+	gcConfirmForce bool                // Asking to force-remove the selected locked worktrees
+
+	gcOrphanBranches  []string // Branches with saved PRs/Claude state but no worktree
+	gcOrphanSessions  []string // jean tmux sessions of branches without a worktree
+	gcOrphansSelected bool     // Clean up the orphans too
+
+	// Lock modal state
+	lockReasonInput textinput.Model // Why the worktree is locked (optional)
+
+	// Adopt modal state (worktrees added outside jean)
+	adoptIndex    int    // Selected option (0=adopt in place, 1=move to the worktree directory)
+	adoptMovePath string // Where the worktree is moved to when adopting with the move option
+
+	// Sparse checkout widen modal state
+	sparseWidenIndex int // Selected option of sparseWidenOptions
+
+	// Worktree location settings
+	worktreeRootInput     textinput.Model // Directory of new worktrees, "" = default
+	worktreeTemplateInput textinput.Model // Path template of new worktrees, "" = default
+
+	// Remotes settings
+	pushRemoteInput textinput.Model // Remote branches are pushed to, "" = origin
+	baseRemoteInput textinput.Model // Remote of the base branch, "" = origin
+	remoteNames     []string        // Remotes of the repository, to check the inputs against
+
 	// PR state settings modal state
 	prStateSettingsCursor int // Selected PR state (0=draft, 1=ready for review)
 	prIsDraft    bool // Whether to create PR as draft (based on config setting)
@@ -238,11 +339,20 @@ type Model struct {
 	// Initialization state
 	isInitializing bool // Suppress notifications during app startup (before first successful worktree load)
 
+	// Background status loading (uncommitted changes, ahead/behind counts)
+	statusGeneration int                // Incremented on every worktree reload, stale results are dropped
+	statusCancel     context.CancelFunc // Cancels the status loading of the previous reload
+
 	// Onboarding state
 	onboardingFocused int // Which button is focused in onboarding modal (0=install, 1=skip)
 
 	// Git init modal state
 	gitInitError string // Error message for git initialization
+
+	// Script picker modal state
+	scriptConfig  *config.ScriptConfig              // Scripts loaded from jean.json
+	scriptIndex   int                               // Selected script in the picker
+	scriptWindows map[string][]session.ScriptWindow // Script windows per tmux session name (refreshed with activity checks)
 }
 
 // NewModel creates a new TUI model
@@ -273,6 +383,53 @@ func NewModel(repoPath string, autoClaude bool) Model {
 	commitSubjectInput.CharLimit = 72
 	commitSubjectInput.Width = 70
 
+	commitBodyInput := textarea.New()
+	commitBodyInput.Placeholder = "Body (optional, explain what and why)"
+	commitBodyInput.CharLimit = 2000
+	commitBodyInput.SetWidth(70)
+	commitBodyInput.SetHeight(4)
+
+	commitTrailersInput := textarea.New()
+	commitTrailersInput.Placeholder = "Trailers (optional, one per line): Co-authored-by: Name <email>, Refs #123"
+	commitTrailersInput.CharLimit = 1000
+	commitTrailersInput.SetWidth(70)
+	commitTrailersInput.SetHeight(2)
+
+	historyRewordInput := textinput.New()
+	historyRewordInput.Placeholder = "New commit subject"
+	historyRewordInput.CharLimit = 72
+	historyRewordInput.Width = 70
+
+	stashInput := textinput.New()
+	stashInput.Placeholder = "Stash message (optional)"
+	stashInput.CharLimit = 100
+	stashInput.Width = 50
+
+	lockReasonInput := textinput.New()
+	lockReasonInput.Placeholder = "Reason (optional), e.g. long-running agent"
+	lockReasonInput.CharLimit = 100
+	lockReasonInput.Width = 50
+
+	worktreeRootInput := textinput.New()
+	worktreeRootInput.Placeholder = git.DefaultWorktreeRoot + " (e.g. ~/worktrees/{repo})"
+	worktreeRootInput.CharLimit = 200
+	worktreeRootInput.Width = 50
+
+	worktreeTemplateInput := textinput.New()
+	worktreeTemplateInput.Placeholder = git.DefaultWorktreeTemplate + " (e.g. {repo}-{branch})"
+	worktreeTemplateInput.CharLimit = 100
+	worktreeTemplateInput.Width = 50
+
+	pushRemoteInput := textinput.New()
+	pushRemoteInput.Placeholder = git.DefaultRemote + " (e.g. fork)"
+	pushRemoteInput.CharLimit = 100
+	pushRemoteInput.Width = 50
+
+	baseRemoteInput := textinput.New()
+	baseRemoteInput.Placeholder = git.DefaultRemote + " (e.g. upstream)"
+	baseRemoteInput.CharLimit = 100
+	baseRemoteInput.Width = 50
+
 	prTitleInput := textinput.New()
 	prTitleInput.Placeholder = "PR title (required, max 72 characters)"
 	prTitleInput.CharLimit = 72
@@ -318,6 +475,9 @@ func NewModel(repoPath string, autoClaude bool) Model {
 
 	// Create git manager and get absolute repo root path
 	gitManager := git.NewManager(repoPath)
+	if configManager != nil {
+		gitManager.SetConfigManager(configManager)
+	}
 	absoluteRepoPath := repoPath
 	if root, err := gitManager.GetRepoRoot(); err == nil {
 		absoluteRepoPath = root
@@ -360,6 +520,15 @@ func NewModel(repoPath string, autoClaude bool) Model {
 		searchInput:        searchInput,
 		sessionNameInput:   sessionNameInput,
 		commitSubjectInput: commitSubjectInput,
+		commitBodyInput:    commitBodyInput,
+		commitTrailersInput: commitTrailersInput,
+		stashInput:         stashInput,
+		lockReasonInput:    lockReasonInput,
+		worktreeRootInput:     worktreeRootInput,
+		worktreeTemplateInput: worktreeTemplateInput,
+		pushRemoteInput:       pushRemoteInput,
+		baseRemoteInput:       baseRemoteInput,
+		historyRewordInput: historyRewordInput,
 		prTitleInput:       prTitleInput,
 		prDescriptionInput: prDescriptionInput,
 		aiAPIKeyInput:      aiAPIKeyInput,
@@ -429,6 +598,7 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadBaseBranch(),
 		m.loadSessions(),
+		m.loadScripts(),
 		m.scheduleActivityCheck(),
 		m.checkForUpdates(),
 		tea.EnterAltScreen,
@@ -469,6 +639,7 @@ type (
 		err    error
 		path   string
 		branch string
+		hint   string // Submodules or LFS files left to set up, see submoduleHint
 	}
 
 	worktreeCreatedWithSessionMsg struct {
@@ -476,26 +647,59 @@ type (
 		path        string
 		branch      string
 		sessionName string
+		hint        string // Submodules or LFS files left to set up, see submoduleHint
+	}
+
+	// createProgressMsg reports a long step of a worktree creation (submodules, LFS files)
+	createProgressMsg struct {
+		step   string
+		events <-chan tea.Msg // Remaining steps, then the creation's result
 	}
 
 	worktreeDeletedMsg struct {
-		err error
+		err        error
+		hookOutput string // Output of the pre_delete hook, see runHook
+	}
+
+	detachedWorktreeCreatedMsg struct {
+		path string
+		ref  string
+		err  error
+	}
+
+	worktreeLockedMsg struct {
+		branch string
+		locked bool
+		err    error
+	}
+
+	sparseCheckoutWidenedMsg struct {
+		branch  string
+		profile string // "" when the worktree got a full checkout
+		err     error
+	}
+
+	worktreeAdoptedMsg struct {
+		name  string
+		path  string
+		moved bool
+		err   error
 	}
 
 	worktreeStatusUpdatedMsg struct {
-		index    int  // Index of worktree in list
-		hasUncommitted bool
-		aheadCount int
-		behindCount int
-		err      error
+		generation int // statusGeneration the result belongs to
+		status     git.WorktreeStatus
+		results    <-chan git.WorktreeStatus // Remaining results of the same load
+		done       bool                      // No status, every worktree of the load is done
 	}
 
 	branchRenamedMsg struct {
-		oldBranch string
-		newBranch string
-		oldPath   string // Old worktree path before rename
-		newPath   string // New worktree path after rename
-		err       error
+		oldBranch   string
+		newBranch   string
+		oldPath     string // Old worktree path before rename
+		newPath     string // New worktree path after rename
+		createdFrom string // Detached worktree that got a branch: the tag/commit it was at
+		err         error
 	}
 
 	branchCheckedOutMsg struct {
@@ -526,11 +730,107 @@ type (
 		prTitle      string // PR title for storing in config
 		author       string // PR author for storing in config
 		isDraft      bool   // Whether the PR is a draft
+		hookOutput   string // Output of the pre_push hook, see runHook
 	}
 
 	branchPulledMsg struct {
-		err         error
-		hadConflict bool
+		worktreePath string
+		onto         string
+		files        []git.ConflictFile // Conflicted files when hadConflict is set
+		stash        string             // Message of the auto-stash made before the merge, "" if none
+		stashErr     error              // Re-applying the auto-stash failed (the stash is kept)
+		err          error
+		hadConflict  bool
+	}
+
+	branchRebasedMsg struct {
+		worktreePath string
+		branch       string
+		onto         string
+		files        []git.ConflictFile // Conflicted files when hadConflict is set
+		hadUpstream  bool               // Branch was pushed before, so the rebase needs a force-push
+		lease        string             // Commit of the remote branch before fetching, see git.Manager.ForcePushWithLease
+		pushed       bool               // Rebased branch was force-pushed (with lease)
+		pushErr      error
+		hookOutput   string // Output of the pre_push hook before the force-push, see runHook
+		stash        string // Message of the auto-stash made before the rebase, "" if none
+		stashErr     error  // Re-applying the auto-stash failed (the stash is kept)
+		err          error
+		hadConflict  bool
+	}
+
+	conflictsUpdatedMsg struct {
+		worktreePath string
+		files        []git.ConflictFile // Remaining conflicts
+		path         string             // File that was resolved
+		resolution   string             // How it was resolved, e.g. "took ours"
+		err          error
+	}
+
+	conflictAbortedMsg struct {
+		operation string
+		stashErr  error // Re-applying the auto-stash failed (the stash is kept)
+		err       error
+	}
+
+	stashesLoadedMsg struct {
+		stashes []git.Stash
+		err     error
+	}
+
+	diffLoadedMsg struct {
+		worktreePath string
+		againstBase  bool
+		files        []git.ChangedFile
+		err          error
+	}
+
+	historyLoadedMsg struct {
+		worktreePath string
+		commits      []git.Commit
+		hasUpstream  bool
+		err          error
+	}
+
+	commitDiffLoadedMsg struct {
+		hash string
+		diff string
+		err  error
+	}
+
+	staleWorktreesLoadedMsg struct {
+		worktrees      []git.StaleWorktree
+		orphanBranches []string
+		orphanSessions []string
+		err            error
+	}
+
+	staleWorktreesRemovedMsg struct {
+		removed     int
+		orphans     int      // Orphaned config entries and sessions cleaned up
+		failed      []string // "branch: error" for each worktree that could not be removed
+		hookOutputs []string // Output of the pre_delete hooks, see runHook
+	}
+
+	historyActionMsg struct {
+		worktreePath string
+		action       string // "amend", "reword", "revert", "squash" or "force-push"
+		rewritten    bool   // Commits that were already pushed got new hashes
+		hookOutput   string // Output of the pre_push hook (force-push), see runHook
+		err          error
+	}
+
+	stashActionMsg struct {
+		action  string // "push", "pop", "apply" or "drop"
+		ref     string
+		stashed bool // push: there were changes to stash
+		err     error
+	}
+
+	mergeReadyToCommitMsg struct {
+		worktreePath string
+		subject      string
+		err          error
 	}
 
 	localMergePreparedMsg struct {
@@ -543,10 +843,14 @@ type (
 	}
 
 	localMergeCompletedMsg struct {
-		branch       string // Branch that was merged
-		worktreePath string // Worktree path
+		branch       string             // Branch that was merged
+		worktreePath string             // Worktree path
+		mergePath    string             // Main repo path the merge ran in
+		files        []git.ConflictFile // Conflicted files when hadConflict is set
 		err          error
-		hadConflict  bool   // Whether there was a merge conflict
+		hadConflict  bool               // Whether there was a merge conflict
+		hookErr      error  // post_merge hook failure (merge itself succeeded)
+		hookOutput   string // Output of the post_merge hook, see runHook
 	}
 
 	refreshWithPullMsg struct {
@@ -561,14 +865,38 @@ type (
 	activityTickMsg time.Time
 
 	activityCheckedMsg struct {
-		sessions []session.Session
-		err      error
+		sessions      []session.Session
+		scriptWindows map[string][]session.ScriptWindow // Script windows per session name
+		err           error
+	}
+
+	scriptsLoadedMsg struct {
+		config *config.ScriptConfig
+		err    error
+	}
+
+	scriptStartedMsg struct {
+		script string
+		window string
+		err    error
+	}
+
+	scriptFinishedMsg struct {
+		script string
+		output string
+		err    error
+	}
+
+	scriptStoppedMsg struct {
+		script string
+		err    error
 	}
 
 	commitCreatedMsg struct {
-		err        error
-		commitHash string
-		subject    string // The commit message/subject used
+		err          error
+		commitHash   string
+		subject      string // The commit message/subject used
+		worktreePath string // Worktree (or main repo) the commit was created in
 	}
 
 	autoCommitBeforePRMsg struct {
@@ -594,9 +922,21 @@ type (
 
 	commitMessageGeneratedMsg struct {
 		subject string
+		body    string // Only generated for the commit modal
 		err     error
 	}
 
+	changesLoadedMsg struct {
+		worktreePath string
+		files        []git.ChangedFile
+		err          error
+	}
+
+	changesStagedMsg struct {
+		worktreePath string
+		err          error
+	}
+
 
 	apiKeyTestedMsg struct {
 		success bool
@@ -676,68 +1016,59 @@ type (
 	}
 
 	pushCompletedMsg struct {
-		branch string
-		err    error
+		branch     string
+		err        error
+		hookOutput string // Output of the pre_push hook, see runHook
 	}
 
 	worktreeEnsuredMsg struct {
-		err error
+		err        error
+		hookOutput string // Output of the post_switch hook, see runHook
 	}
 
 )
 
 // Commands
+// loadWorktrees lists the worktrees without their status for instant UI appearance
+// Status data (uncommitted changes, ahead/behind counts) is streamed in afterwards by loadWorktreeStatuses
 func (m Model) loadWorktrees() tea.Cmd {
 	return func() tea.Msg {
-		worktrees, err := m.gitManager.List(m.baseBranch)
+		worktrees, err := m.gitManager.ListLightweight()
 		// Calculate sanitized Claude session names for each worktree
 		repoName := filepath.Base(m.repoPath)
 		for i := range worktrees {
-			worktrees[i].ClaudeSessionName = m.sessionManager.SanitizeName(repoName, worktrees[i].Branch)
+			worktrees[i].ClaudeSessionName = m.sessionManager.SanitizeName(repoName, worktrees[i].Name())
 		}
 		return worktreesLoadedMsg{worktrees: worktrees, err: err}
 	}
 }
 
-func (m Model) loadWorktreesLightweight() tea.Cmd {
-	return func() tea.Msg {
-		worktrees, err := m.gitManager.ListLightweight()
-		// Calculate sanitized Claude session names for each worktree
-		repoName := filepath.Base(m.repoPath)
-		for i := range worktrees {
-			worktrees[i].ClaudeSessionName = m.sessionManager.SanitizeName(repoName, worktrees[i].Branch)
-		}
-		return worktreesLoadedMsg{worktrees: worktrees, err: err}
+// loadWorktreeStatuses starts loading the status of every worktree in a bounded worker pool
+// Results are delivered one by one as worktreeStatusUpdatedMsg; a previous load still in progress is cancelled
+func (m *Model) loadWorktreeStatuses() tea.Cmd {
+	if m.statusCancel != nil {
+		m.statusCancel()
 	}
-}
+	m.statusGeneration++
 
-// loadWorktreeStatus loads status (uncommitted changes, ahead/behind counts) for a single worktree
-// This is called asynchronously after the initial lightweight load
-func (m Model) loadWorktreeStatus(index int, worktree git.Worktree) tea.Cmd {
-	return func() tea.Msg {
-		// Check for uncommitted changes
-		hasUncommitted := false
-		if uncommitted, err := m.gitManager.HasUncommittedChanges(worktree.Path); err == nil {
-			hasUncommitted = uncommitted
-		}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.statusCancel = cancel
 
-		// Check branch status (ahead/behind counts)
-		aheadCount := 0
-		behindCount := 0
-		if m.baseBranch != "" && !strings.HasPrefix(worktree.Branch, "(detached") {
-			if ahead, behind, err := m.gitManager.GetBranchStatus(worktree.Path, worktree.Branch, m.baseBranch); err == nil {
-				aheadCount = ahead
-				behindCount = behind
-			}
-		}
+	worktrees := make([]git.Worktree, len(m.worktrees))
+	copy(worktrees, m.worktrees)
+	results := m.gitManager.LoadStatuses(ctx, worktrees, m.baseBranch)
+	return waitForWorktreeStatus(m.statusGeneration, results)
+}
 
-		return worktreeStatusUpdatedMsg{
-			index:          index,
-			hasUncommitted: hasUncommitted,
-			aheadCount:     aheadCount,
-			behindCount:    behindCount,
-			err:            nil,
+// waitForWorktreeStatus waits for the next status result of a load
+func waitForWorktreeStatus(generation int, results <-chan git.WorktreeStatus) tea.Cmd {
+	return func() tea.Msg {
+		status, ok := <-results
+		if !ok {
+			// All worktrees done
+			return worktreeStatusUpdatedMsg{generation: generation, done: true}
 		}
+		return worktreeStatusUpdatedMsg{generation: generation, status: status, results: results}
 	}
 }
 
@@ -803,8 +1134,11 @@ func (m Model) createWorktree(path, branch string, newBranch bool) tea.Cmd {
 			baseBranch = m.baseBranch
 		}
 
-		err := m.gitManager.Create(path, branch, newBranch, baseBranch)
-		return worktreeCreatedMsg{err: err, path: path, branch: branch}
+		return withCreateProgress(func(progress func(step string)) tea.Msg {
+			opts := git.CreateOptions{SparseProfile: m.createSparseProfile, Progress: progress}
+			err := m.gitManager.CreateWithOptions(path, branch, newBranch, baseBranch, opts)
+			return worktreeCreatedMsg{err: err, path: path, branch: branch, hint: m.submoduleHint(path, err)}
+		})
 	}
 }
 
@@ -821,31 +1155,235 @@ func (m Model) createWorktreeWithSession(path, sessionName string, newBranch boo
 			baseBranch = m.baseBranch
 		}
 
-		err := m.gitManager.Create(path, sessionName, newBranch, baseBranch)
-		return worktreeCreatedWithSessionMsg{err: err, path: path, branch: sessionName, sessionName: sessionName}
+		return withCreateProgress(func(progress func(step string)) tea.Msg {
+			opts := git.CreateOptions{SparseProfile: m.createSparseProfile, Progress: progress}
+			err := m.gitManager.CreateWithOptions(path, sessionName, newBranch, baseBranch, opts)
+			return worktreeCreatedWithSessionMsg{err: err, path: path, branch: sessionName, sessionName: sessionName, hint: m.submoduleHint(path, err)}
+		})
+	}
+}
+
+// withCreateProgress runs a worktree creation in the background and returns its first event:
+// a createProgressMsg for each long step (see waitForCreateProgress), then its result
+func withCreateProgress(create func(progress func(step string)) tea.Msg) tea.Msg {
+	events := make(chan tea.Msg, 4)
+	go func() {
+		defer close(events)
+		events <- create(func(step string) {
+			events <- createProgressMsg{step: step, events: events}
+		})
+	}()
+	return <-events
+}
+
+// waitForCreateProgress waits for the next step or the result of a worktree creation
+func waitForCreateProgress(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// submoduleHint tells how to set up the submodules and LFS files of a new worktree when the
+// repository setting to do it on creation is off, "" if there is nothing to do
+func (m Model) submoduleHint(path string, createErr error) string {
+	if createErr != nil {
+		return ""
+	}
+	if m.configManager != nil && m.configManager.GetInitSubmodules(m.repoPath) {
+		return ""
 	}
+	return git.SubmoduleHint(m.gitManager.DetectSubmodulesAndLFS(path))
 }
 
 func (m Model) deleteWorktree(path, branch string, force bool) tea.Cmd {
 	return func() tea.Msg {
-		// First remove the worktree
-		err := m.gitManager.Remove(path, force)
+		hookOutput, err := m.removeWorktree(path, branch, force)
+		return worktreeDeletedMsg{err: err, hookOutput: hookOutput}
+	}
+}
+
+// deleteNeedsForce checks if the worktree in the delete modal can only be force-deleted
+func (m Model) deleteNeedsForce() bool {
+	return m.deleteHasUncommitted || m.deleteLocked
+}
+
+// setWorktreeLock locks (with an optional reason) or unlocks a worktree
+func (m Model) setWorktreeLock(path, branch string, lock bool, reason string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if lock {
+			err = m.gitManager.Lock(path, reason)
+		} else {
+			err = m.gitManager.Unlock(path)
+		}
+		return worktreeLockedMsg{branch: branch, locked: lock, err: err}
+	}
+}
+
+// adoptWorktree makes a worktree added outside jean a managed one, optionally moving it to the
+// worktree directory, and creates its tmux session so it shows up like the others
+func (m Model) adoptWorktree(path, name string, move bool) tea.Cmd {
+	return func() tea.Msg {
+		newPath, err := m.gitManager.Adopt(path, move)
+		if err != nil {
+			return worktreeAdoptedMsg{name: name, err: err}
+		}
+		if m.sessionManager.IsTmuxAvailable() {
+			sessionName := m.sessionManager.SanitizeName(filepath.Base(m.repoPath), name)
+			_ = m.sessionManager.CreateDetached(sessionName, newPath) // Not critical, created on switch otherwise
+		}
+		return worktreeAdoptedMsg{name: name, path: newPath, moved: newPath != path}
+	}
+}
+
+// removeWorktree removes a worktree with its branch, config entries and tmux session
+// Returns the output of the pre_delete hook (see runHook)
+func (m Model) removeWorktree(path, branch string, force bool) (string, error) {
+	// pre_delete hook can veto the removal
+	hookOutput, err := m.runHook(config.HookPreDelete, m.hookContext(path, branch))
+	if err != nil {
+		return "", err
+	}
+
+	// Then remove the worktree
+	if err := m.gitManager.Remove(path, force); err != nil {
+		return hookOutput, err
+	}
+
+	m.cleanupBranchState(branch)
+	return hookOutput, nil
+}
+
+// cleanupBranchState drops the config entries and kills the tmux session of a branch whose
+// worktree is gone
+func (m Model) cleanupBranchState(branch string) {
+	// Clean up branch-specific config data (PRs, Claude initialization, etc.)
+	// This prevents config file bloat and removes stale references
+	if m.configManager != nil {
+		_ = m.configManager.CleanupBranch(m.repoPath, branch) // Ignore error, not critical
+	}
+
+	// Then kill the associated tmux session if it exists
+	repoName := filepath.Base(m.repoPath)
+	sessionName := m.sessionManager.SanitizeName(repoName, branch)
+	_ = m.sessionManager.Kill(sessionName) // Ignore error if session doesn't exist
+}
+
+// loadStaleWorktrees finds the worktrees the cleanup modal offers to remove
+func (m Model) loadStaleWorktrees() tea.Cmd {
+	return func() tea.Msg {
+		// Locked worktrees are listed unselected, removing them needs a separate confirmation
+		worktrees, err := m.gitManager.FindStaleWorktrees(m.baseBranch, git.DefaultStaleDays, true)
 		if err != nil {
-			return worktreeDeletedMsg{err: err}
+			return staleWorktreesLoadedMsg{err: err}
 		}
+		msg := staleWorktreesLoadedMsg{worktrees: worktrees}
 
-		// Clean up branch-specific config data (PRs, Claude initialization, etc.)
-		// This prevents config file bloat and removes stale references
+		// Config and sessions of branches whose worktree was removed outside jean. Worktrees whose
+		// directory was deleted still count, they are cleaned up with their worktree.
+		live, err := m.gitManager.ListLightweight()
+		if err != nil {
+			return msg
+		}
+		branches := make([]string, 0, len(live))
+		paths := make([]string, 0, len(live))
+		for _, wt := range live {
+			branches = append(branches, wt.Name())
+			paths = append(paths, wt.Path)
+		}
 		if m.configManager != nil {
-			_ = m.configManager.CleanupBranch(m.repoPath, branch) // Ignore error, not critical
+			msg.orphanBranches = m.configManager.OrphanedBranches(m.repoPath, branches)
 		}
+		if sessions, err := m.sessionManager.Orphaned(m.repoPath, branches, paths...); err == nil {
+			for _, sess := range sessions {
+				msg.orphanSessions = append(msg.orphanSessions, sess.Name)
+			}
+		}
+		return msg
+	}
+}
 
-		// Then kill the associated tmux session if it exists
-		repoName := filepath.Base(m.repoPath)
-		sessionName := m.sessionManager.SanitizeName(repoName, branch)
-		_ = m.sessionManager.Kill(sessionName) // Ignore error if session doesn't exist
+// pruneWorktree drops git's metadata of a worktree whose directory was deleted, and its config
+// entries and tmux session. Its branch is kept.
+func (m Model) pruneWorktree(wt git.StaleWorktree) error {
+	// git never prunes locked worktrees
+	if wt.Locked {
+		if err := m.gitManager.Unlock(wt.Path); err != nil {
+			return err
+		}
+	}
+	if err := m.gitManager.PruneWorktrees(); err != nil {
+		return err
+	}
+	m.cleanupBranchState(wt.Name())
+	return nil
+}
+
+// hasGCOrphans checks if the cleanup modal found config entries or sessions without a worktree
+func (m Model) hasGCOrphans() bool {
+	return len(m.gcOrphanBranches) > 0 || len(m.gcOrphanSessions) > 0
+}
+
+// gcLockedSelected returns the number of locked worktrees selected in the cleanup modal
+func (m Model) gcLockedSelected() int {
+	count := 0
+	for i, wt := range m.gcWorktrees {
+		if m.gcSelected[i] && wt.Locked {
+			count++
+		}
+	}
+	return count
+}
+
+// gcRowCount returns the number of rows of the cleanup modal: the worktrees and the orphans
+func (m Model) gcRowCount() int {
+	if m.hasGCOrphans() {
+		return len(m.gcWorktrees) + 1
+	}
+	return len(m.gcWorktrees)
+}
+
+// removeStaleWorktrees removes the given worktrees one after the other, forcing the removal of
+// those with uncommitted changes (the user selected them explicitly). Locked worktrees are only
+// removed with forceLocked, once the user confirmed it separately. Deleted directories are
+// pruned (keeping their branches), then the orphaned config entries and sessions are cleaned up.
+func (m Model) removeStaleWorktrees(worktrees []git.StaleWorktree, orphanBranches, orphanSessions []string, forceLocked bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := staleWorktreesRemovedMsg{}
+		for _, wt := range worktrees {
+			if wt.Locked && !forceLocked {
+				msg.failed = append(msg.failed, fmt.Sprintf("%s: locked", wt.Branch))
+				continue
+			}
+			if wt.Prunable {
+				if err := m.pruneWorktree(wt); err != nil {
+					msg.failed = append(msg.failed, fmt.Sprintf("%s: %v", wt.Branch, err))
+					continue
+				}
+				msg.removed++
+				continue
+			}
+			hookOutput, err := m.removeWorktree(wt.Path, wt.Name(), wt.HasUncommitted || wt.Locked)
+			if hookOutput != "" {
+				msg.hookOutputs = append(msg.hookOutputs, hookOutput)
+			}
+			if err != nil {
+				msg.failed = append(msg.failed, fmt.Sprintf("%s: %v", wt.Branch, err))
+				continue
+			}
+			msg.removed++
+		}
 
-		return worktreeDeletedMsg{err: nil}
+		for _, branch := range orphanBranches {
+			if m.configManager != nil {
+				_ = m.configManager.CleanupBranch(m.repoPath, branch)
+			}
+		}
+		for _, name := range orphanSessions {
+			_ = m.sessionManager.Kill(name)
+		}
+		msg.orphans = len(orphanBranches) + len(orphanSessions)
+		return msg
 	}
 }
 
@@ -884,9 +1422,40 @@ func (m Model) createWorktreeFromPR(branch string) tea.Cmd {
 
 func (m Model) ensureWorktreeExists(worktreePath, branch string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.gitManager.EnsureWorktreeExists(worktreePath, branch)
-		return worktreeEnsuredMsg{err: err}
+		if err := m.gitManager.EnsureWorktreeExists(worktreePath, branch); err != nil {
+			return worktreeEnsuredMsg{err: err}
+		}
+
+		// post_switch hook can cancel the switch before we hand off to tmux
+		hookOutput, err := m.runHook(config.HookPostSwitch, m.hookContext(worktreePath, branch))
+		return worktreeEnsuredMsg{err: err, hookOutput: hookOutput}
+	}
+}
+
+// hookContext builds the JEAN_* environment for a lifecycle hook on a worktree
+func (m Model) hookContext(worktreePath, branch string) git.HookContext {
+	ctx := git.HookContext{
+		WorkspacePath: worktreePath,
+		Branch:        branch,
+		BaseBranch:    m.baseBranch,
+	}
+	if m.configManager != nil {
+		if pr := m.configManager.GetLatestPR(m.repoPath, branch); pr != nil {
+			ctx.PRURL = pr.URL
+		}
+		ctx.Port = m.configManager.GetPortBlock(m.repoPath, worktreePath)
+	}
+	return ctx
+}
+
+// runHook runs a jean.json hook and returns its output labeled with the hook's name, to be
+// shown with the result of the operation (see withHookOutput). "" when the hook printed nothing.
+func (m Model) runHook(hook string, ctx git.HookContext) (string, error) {
+	output, err := m.gitManager.RunHook(hook, ctx)
+	if output = strings.TrimSpace(output); err != nil || output == "" {
+		return "", err
 	}
+	return fmt.Sprintf("%s hook:\n%s", hook, output), nil
 }
 
 func (m Model) renameBranch(oldName, newName, worktreePath string) tea.Cmd {
@@ -916,6 +1485,123 @@ func (m Model) renameBranch(oldName, newName, worktreePath string) tea.Cmd {
 	}
 }
 
+// createBranchHere turns a detached worktree into a normal one. Its session was named after the
+// worktree directory (oldName) and is renamed after the branch like for a branch rename.
+func (m Model) createBranchHere(worktreePath, oldName, ref, branch string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.gitManager.CreateBranchHere(worktreePath, branch)
+		return branchRenamedMsg{
+			oldBranch:   oldName,
+			newBranch:   branch,
+			oldPath:     worktreePath,
+			newPath:     worktreePath,
+			createdFrom: ref,
+			err:         err,
+		}
+	}
+}
+
+// createDetachedWorktree creates a worktree at a tag or commit
+func (m Model) createDetachedWorktree(ref string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.gitManager.EnsureWorkspacesDir(); err != nil {
+			return detachedWorktreeCreatedMsg{ref: ref, err: err}
+		}
+		path, err := m.gitManager.GetDefaultPath(ref)
+		if err != nil {
+			return detachedWorktreeCreatedMsg{ref: ref, err: err}
+		}
+		err = m.gitManager.CreateDetached(path, ref)
+		return detachedWorktreeCreatedMsg{path: path, ref: ref, err: err}
+	}
+}
+
+// worktreeLocation returns the configured root directory and path template of new worktrees,
+// "" for the defaults
+func (m Model) worktreeLocation() (root, template string) {
+	if m.configManager == nil {
+		return "", ""
+	}
+	return m.configManager.GetWorktreeLocation(m.repoPath)
+}
+
+// remotes returns the remote branches are pushed to and the remote of the base branch, like
+// git.Manager.Remotes but from the configuration alone so it can be used while rendering
+func (m Model) remotes() (push, base string) {
+	push, base = git.DefaultRemote, git.DefaultRemote
+	if m.configManager == nil {
+		return push, base
+	}
+	configuredPush, configuredBase := m.configManager.GetRemotes(m.repoPath)
+	if configuredPush != "" {
+		push = configuredPush
+	}
+	if configuredBase != "" {
+		base = configuredBase
+	}
+	return push, base
+}
+
+// previewWorktreePath shows where the worktree of an example branch would go, relative to the
+// repository when it is inside of it
+func (m Model) previewWorktreePath(root, template string) string {
+	path := git.WorktreePath(m.repoPath, root, template, "<branch>")
+	if rel, err := filepath.Rel(m.repoPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// nextSparseProfile cycles the sparse-checkout profile of a new worktree through the profiles
+// of jean.json, starting and ending with a full checkout
+func (m Model) nextSparseProfile() string {
+	names := m.scriptConfig.SparseProfileNames()
+	for i, name := range names {
+		if name == m.createSparseProfile {
+			if i+1 < len(names) {
+				return names[i+1]
+			}
+			return ""
+		}
+	}
+	if len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// sparseWidenOptions lists what a sparse worktree can be widened with: the profiles not fully
+// checked out yet, then "" for a full checkout
+func (m Model) sparseWidenOptions(wt *git.Worktree) []string {
+	var options []string
+	for _, name := range m.scriptConfig.SparseProfileNames() {
+		if !m.scriptConfig.SparseIncludes(wt.SparseDirs, name) {
+			options = append(options, name)
+		}
+	}
+	return append(options, "")
+}
+
+// widenSparseCheckout adds a profile's directories to a sparse worktree, or turns it into a
+// full checkout when profile is ""
+func (m Model) widenSparseCheckout(path, branch, profile string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if profile == "" {
+			err = m.gitManager.DisableSparseCheckout(path)
+		} else {
+			err = m.gitManager.WidenSparseCheckout(path, profile)
+		}
+		return sparseCheckoutWidenedMsg{branch: branch, profile: profile, err: err}
+	}
+}
+
+// loadTags lists the tags for the "create worktree at tag or commit" modal
+func (m Model) loadTags() tea.Msg {
+	tags, err := m.gitManager.ListTags()
+	return branchesLoadedMsg{branches: tags, err: err}
+}
+
 func (m Model) renameSessionsForBranch(oldBranch, newBranch string) tea.Cmd {
 	return func() tea.Msg {
 		// Sanitize both branch names for session names (including repo basename)
@@ -1025,42 +1711,47 @@ func (m Model) createPR(worktreePath, branch string, optionalTitle string, optio
 		}
 
 		// Only push if branch doesn't exist remotely or has unpushed commits
-		if !remoteBranchExists {
-			// Push the branch for the first time
-			if err := m.gitManager.Push(worktreePath, branch); err != nil {
-				return prCreatedMsg{err: fmt.Errorf("failed to push commits: %w", err), isDraft: m.prIsDraft}
-			}
-		} else {
+		needsPush := !remoteBranchExists
+		if remoteBranchExists {
 			// Branch exists remotely, check if we have unpushed commits
 			hasUnpushed, err := m.gitManager.HasUnpushedCommits(worktreePath, branch)
 			if err != nil {
 				return prCreatedMsg{err: fmt.Errorf("failed to check for unpushed commits: %w", err), isDraft: m.prIsDraft}
 			}
-			if hasUnpushed {
-				// Push new commits
-				if err := m.gitManager.Push(worktreePath, branch); err != nil {
-					return prCreatedMsg{err: fmt.Errorf("failed to push commits: %w", err), isDraft: m.prIsDraft}
-				}
-			}
 			// If no unpushed commits, branch is already up to date, continue to PR creation
+			needsPush = hasUnpushed
 		}
 
-		// Determine title: use provided title or generate from branch name
-		title := optionalTitle
+		var hookOutput string
+		if needsPush {
+			// pre_push hook can veto the push (and therefore the PR)
+			hookOutput, err = m.runHook(config.HookPrePush, m.hookContext(worktreePath, branch))
+			if err != nil {
+				return prCreatedMsg{err: err, isDraft: m.prIsDraft}
+			}
+			if err := m.gitManager.Push(worktreePath, branch); err != nil {
+				return prCreatedMsg{err: fmt.Errorf("failed to push commits: %w", err), isDraft: m.prIsDraft, hookOutput: hookOutput}
+			}
+		}
+
+		// Determine title: use provided title or generate from branch name
+		title := optionalTitle
 		if title == "" {
-			title = strings.ReplaceAll(branch, "-", " ")
-			title = strings.ReplaceAll(title, "_", " ")
-			title = strings.Title(title)
+			title = github.TitleFromBranch(branch)
 		}
 
 		// Use provided description or default to empty
 		description := optionalDescription
 
 		// Create PR (draft or ready for review based on user selection)
-		prURL, err := m.githubManager.CreatePR(worktreePath, branch, m.baseBranch, title, description, m.prIsDraft)
+		opts, err := m.prOptions()
 		if err != nil {
 			return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath}
 		}
+		prURL, err := m.githubManager.CreatePRWithOptions(worktreePath, branch, m.baseBranch, title, description, opts)
+		if err != nil {
+			return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath, hookOutput: hookOutput}
+		}
 
 		// Get current git user for author field
 		author := ""
@@ -1068,8 +1759,18 @@ func (m Model) createPR(worktreePath, branch string, optionalTitle string, optio
 			author = user
 		}
 
-		return prCreatedMsg{prURL: prURL, branch: branch, worktreePath: worktreePath, prTitle: title, author: author, isDraft: m.prIsDraft}
+		return prCreatedMsg{prURL: prURL, branch: branch, worktreePath: worktreePath, prTitle: title, author: author, isDraft: m.prIsDraft, hookOutput: hookOutput}
+	}
+}
+
+// prOptions returns the options of a new PR: draft or not, and when branches are pushed to a
+// fork, the base remote's repository and the fork's owner
+func (m Model) prOptions() (github.PROptions, error) {
+	repo, headOwner, err := m.gitManager.PRRepos()
+	if err != nil {
+		return github.PROptions{}, err
 	}
+	return github.PROptions{Draft: m.prIsDraft, Repo: repo, HeadOwner: headOwner}, nil
 }
 
 // createOrUpdatePR creates a new PR or updates existing one if it already exists
@@ -1105,7 +1806,11 @@ func (m Model) createOrUpdatePR(worktreePath, branch string, title string, descr
 		}
 
 		// PR doesn't exist, create a new one (draft or ready for review based on user selection)
-		prURL, err := m.githubManager.CreatePR(worktreePath, branch, m.baseBranch, title, description, m.prIsDraft)
+		opts, err := m.prOptions()
+		if err != nil {
+			return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath, isDraft: m.prIsDraft}
+		}
+		prURL, err := m.githubManager.CreatePRWithOptions(worktreePath, branch, m.baseBranch, title, description, opts)
 		if err != nil {
 			return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath, isDraft: m.prIsDraft}
 		}
@@ -1120,15 +1825,33 @@ func (m Model) createPRRetry(worktreePath, branch string, title string, descript
 	return m.createOrUpdatePR(worktreePath, branch, title, description)
 }
 
-// createCommit creates a commit with the given subject and body
-func (m Model) createCommit(worktreePath, subject string) tea.Cmd {
+// commitOptions builds the options of a commit, adding the sign-off and GPG preferences of the repository
+func (m Model) commitOptions(body string, trailers []string) git.CommitOptions {
+	return git.CommitOptions{
+		Body:     body,
+		Trailers: trailers,
+		Signoff:  m.configManager.GetCommitSignoff(m.repoPath),
+		GPGSign:  m.configManager.GetCommitGPGSign(m.repoPath),
+	}
+}
+
+// createCommit creates a commit with the given subject, body and trailers
+// With stagedOnly, only the changes staged in the staging modal are committed
+func (m Model) createCommit(worktreePath, subject, body string, trailers []string, stagedOnly bool) tea.Cmd {
+	opts := m.commitOptions(body, trailers)
 	return func() tea.Msg {
 		if subject == "" {
 			return commitCreatedMsg{err: fmt.Errorf("commit subject cannot be empty")}
 		}
 
-		commitHash, err := m.gitManager.CreateCommit(worktreePath, subject)
-		return commitCreatedMsg{err: err, commitHash: commitHash, subject: subject}
+		// Changes picked in the staging modal are already staged, don't add the rest
+		if stagedOnly {
+			commitHash, err := m.gitManager.CommitStaged(worktreePath, subject, opts)
+			return commitCreatedMsg{err: err, commitHash: commitHash, subject: subject, worktreePath: worktreePath}
+		}
+
+		commitHash, err := m.gitManager.CreateCommitWithOptions(worktreePath, subject, opts)
+		return commitCreatedMsg{err: err, commitHash: commitHash, subject: subject, worktreePath: worktreePath}
 	}
 }
 
@@ -1145,7 +1868,7 @@ func (m Model) autoCommitBeforePR(worktreePath, branch string) tea.Cmd {
 			subject = strings.ToUpper(subject[:1]) + subject[1:]
 		}
 
-		_, err := m.gitManager.CreateCommit(worktreePath, subject)
+		_, err := m.gitManager.CreateCommitWithOptions(worktreePath, subject, m.commitOptions("", nil))
 		return autoCommitBeforePRMsg{worktreePath: worktreePath, branch: branch, err: err}
 	}
 }
@@ -1168,7 +1891,8 @@ func (m Model) changeTheme(themeName string) tea.Cmd {
 }
 
 // generateCommitMessageWithAI generates a commit message using OpenRouter API
-func (m Model) generateCommitMessageWithAI(worktreePath string) tea.Cmd {
+// With stagedOnly, the prompt only gets the changes staged in the staging modal
+func (m Model) generateCommitMessageWithAI(worktreePath string, stagedOnly, withBody bool) tea.Cmd {
 	return func() tea.Msg {
 		apiKey := m.configManager.GetOpenRouterAPIKey()
 		if apiKey == "" {
@@ -1176,13 +1900,17 @@ func (m Model) generateCommitMessageWithAI(worktreePath string) tea.Cmd {
 		}
 
 		// Get git status
-		status, err := m.gitManager.GetStatus(worktreePath)
+		getStatus, getDiff := m.gitManager.GetStatus, m.gitManager.GetDiff
+		if stagedOnly {
+			getStatus, getDiff = m.gitManager.GetStagedStatus, m.gitManager.GetStagedDiff
+		}
+		status, err := getStatus(worktreePath)
 		if err != nil {
 			status = "(unable to get status)"
 		}
 
 		// Get the git diff as context
-		diff, err := m.gitManager.GetDiff(worktreePath)
+		diff, err := getDiff(worktreePath)
 		if err != nil {
 			return commitMessageGeneratedMsg{err: fmt.Errorf("failed to get diff: %w", err)}
 		}
@@ -1207,12 +1935,12 @@ func (m Model) generateCommitMessageWithAI(worktreePath string) tea.Cmd {
 		model := m.configManager.GetOpenRouterModel()
 		client := openrouter.NewClient(apiKey, model)
 		customPrompt := m.configManager.GetCommitPrompt()
-		subject, err := client.GenerateCommitMessage(status, diff, branch, log, customPrompt)
+		subject, body, err := client.GenerateCommitMessage(status, diff, branch, log, customPrompt, withBody)
 		if err != nil {
 			return commitMessageGeneratedMsg{err: fmt.Errorf("failed to generate commit message: %w", err)}
 		}
 
-		return commitMessageGeneratedMsg{subject: subject, err: nil}
+		return commitMessageGeneratedMsg{subject: subject, body: body, err: nil}
 	}
 }
 
@@ -1326,16 +2054,13 @@ func (m Model) renameBranchForPR(oldName, newName, worktreePath string) tea.Cmd
 			}
 		}
 
-		// Step 2: Rename directory if it's a workspace worktree
-		workspacesDir, err := m.gitManager.GetWorkspacesDir()
-		if err == nil && strings.HasPrefix(worktreePath, workspacesDir) {
-			// Sanitize the new branch name for use as directory name
-			sanitizedDirName := git.SanitizeBranchName(newName)
-			sanitizedDirName = strings.ReplaceAll(sanitizedDirName, "/", "-")
-			newPath := filepath.Join(workspacesDir, sanitizedDirName)
-
-			// Move the worktree directory (non-critical if it fails)
-			_ = m.gitManager.MoveWorktree(worktreePath, newPath)
+		// Step 2: Rename directory if it's a workspace worktree (created by jean)
+		if m.gitManager.IsManaged(worktreePath) {
+			// The new directory follows the worktree path template, like a new worktree
+			if newPath, err := m.gitManager.GetDefaultPath(git.SanitizeBranchName(newName)); err == nil {
+				// Move the worktree directory (non-critical if it fails)
+				_ = m.gitManager.MoveWorktree(worktreePath, newPath)
+			}
 		}
 
 		return prBranchRenamedMsg{
@@ -1409,7 +2134,7 @@ func (m Model) testOpenRouterAPIKey(apiKey, model string) tea.Cmd {
 		testDiff := "test content"
 		testBranch := "test-branch"
 		testLog := "test commit"
-		_, err := client.GenerateCommitMessage(testStatus, testDiff, testBranch, testLog, "")
+		_, _, err := client.GenerateCommitMessage(testStatus, testDiff, testBranch, testLog, "", false)
 		if err != nil {
 			return apiKeyTestedMsg{success: false, err: err}
 		}
@@ -1437,7 +2162,7 @@ func (m Model) refreshPRStatuses() tea.Cmd {
 		}
 
 		// Reload worktrees to get updated PR info
-		worktrees, err := m.gitManager.List(m.baseBranch)
+		worktrees, err := m.gitManager.ListLightweight()
 		if err != nil {
 			return prStatusesRefreshedMsg{err: err}
 		}
@@ -1582,16 +2307,13 @@ func (m Model) renameBranchForPush(oldName, newName, worktreePath string) tea.Cm
 
 		// Step 2: Rename directory if it's a workspace worktree
 		newWorktreePath := worktreePath
-		workspacesDir, err := m.gitManager.GetWorkspacesDir()
-		if err == nil && strings.HasPrefix(worktreePath, workspacesDir) {
-			// Sanitize the new branch name for use as directory name
-			sanitizedDirName := git.SanitizeBranchName(newName)
-			sanitizedDirName = strings.ReplaceAll(sanitizedDirName, "/", "-")
-			newPath := filepath.Join(workspacesDir, sanitizedDirName)
-
-			// Move the worktree directory (non-critical if it fails)
-			if moveErr := m.gitManager.MoveWorktree(worktreePath, newPath); moveErr == nil {
-				newWorktreePath = newPath
+		if m.gitManager.IsManaged(worktreePath) {
+			// The new directory follows the worktree path template, like a new worktree
+			if newPath, err := m.gitManager.GetDefaultPath(git.SanitizeBranchName(newName)); err == nil {
+				// Move the worktree directory (non-critical if it fails)
+				if moveErr := m.gitManager.MoveWorktree(worktreePath, newPath); moveErr == nil {
+					newWorktreePath = newPath
+				}
 			}
 		}
 
@@ -1616,12 +2338,18 @@ func (m Model) pushBranch(worktreePath, branch string) tea.Cmd {
 			return pushCompletedMsg{branch: branch, err: fmt.Errorf("no commits to push")}
 		}
 
+		// pre_push hook can veto the push
+		hookOutput, err := m.runHook(config.HookPrePush, m.hookContext(worktreePath, branch))
+		if err != nil {
+			return pushCompletedMsg{branch: branch, err: err}
+		}
+
 		// Push the branch
 		if err := m.gitManager.Push(worktreePath, branch); err != nil {
-			return pushCompletedMsg{branch: branch, err: fmt.Errorf("failed to push: %w", err)}
+			return pushCompletedMsg{branch: branch, err: fmt.Errorf("failed to push: %w", err), hookOutput: hookOutput}
 		}
 
-		return pushCompletedMsg{branch: branch, err: nil}
+		return pushCompletedMsg{branch: branch, err: nil, hookOutput: hookOutput}
 	}
 }
 
@@ -1691,10 +2419,19 @@ func (m Model) GetConfigManager() *config.Manager {
 	return m.configManager
 }
 
+// worktreePaths returns the paths of the loaded worktrees
+func (m Model) worktreePaths() []string {
+	paths := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		paths = append(paths, wt.Path)
+	}
+	return paths
+}
+
 // loadSessions loads tmux sessions for the current repository only
 func (m Model) loadSessions() tea.Cmd {
 	return func() tea.Msg {
-		sessions, err := m.sessionManager.List(m.repoPath)
+		sessions, err := m.sessionManager.List(m.repoPath, m.worktreePaths()...)
 		if err != nil {
 			return statusMsg("Failed to load sessions")
 		}
@@ -1718,8 +2455,9 @@ func (m Model) pullFromBaseBranch(worktreePath, baseBranch string) tea.Cmd {
 		err := m.gitManager.MergeBranch(worktreePath, baseBranch)
 		if err != nil {
 			// Check if it's a merge conflict
-			if strings.Contains(err.Error(), "merge conflict") {
-				return branchPulledMsg{err: err, hadConflict: true}
+			if errors.Is(err, git.ErrMergeConflict) {
+				files, _ := m.gitManager.Conflicts(worktreePath)
+				return branchPulledMsg{worktreePath: worktreePath, onto: baseBranch, files: files, err: err, hadConflict: true}
 			}
 			return branchPulledMsg{err: err, hadConflict: false}
 		}
@@ -1730,7 +2468,8 @@ func (m Model) pullFromBaseBranch(worktreePath, baseBranch string) tea.Cmd {
 
 // checkAndPullFromBase fetches first, then checks if behind, then pulls if needed
 // This ensures we check against actual remote state, not stale cached data
-func (m Model) checkAndPullFromBase(worktreePath, baseBranch string) tea.Cmd {
+// With autoStash, uncommitted changes are stashed for the merge and re-applied afterwards
+func (m Model) checkAndPullFromBase(worktreePath, baseBranch string, autoStash bool) tea.Cmd {
 	return func() tea.Msg {
 		// First: Fetch to get latest remote refs
 		if err := m.gitManager.FetchRemote(); err != nil {
@@ -1749,24 +2488,585 @@ func (m Model) checkAndPullFromBase(worktreePath, baseBranch string) tea.Cmd {
 			return branchPulledMsg{err: fmt.Errorf("worktree is already up-to-date with base branch"), hadConflict: false}
 		}
 
-		// Fourth: Pull if behind
+		// Fourth: Stash uncommitted changes out of the way
+		stash := ""
+		if autoStash {
+			if stash, err = m.stashBeforeUpdate(worktreePath, baseBranch); err != nil {
+				return branchPulledMsg{err: err}
+			}
+		}
+
+		// Fifth: Pull if behind
 		err = m.gitManager.MergeBranch(worktreePath, baseBranch)
 		if err != nil {
-			// Check if it's a merge conflict
-			if strings.Contains(err.Error(), "merge conflict") {
-				return branchPulledMsg{err: err, hadConflict: true}
+			// Check if it's a merge conflict (the changes stay stashed until it is resolved)
+			if errors.Is(err, git.ErrMergeConflict) {
+				files, _ := m.gitManager.Conflicts(worktreePath)
+				return branchPulledMsg{worktreePath: worktreePath, onto: baseBranch, files: files, stash: stash, err: err, hadConflict: true}
 			}
-			return branchPulledMsg{err: err, hadConflict: false}
+			// Nothing was merged, put the changes back
+			return branchPulledMsg{stash: stash, stashErr: m.restoreAutoStash(worktreePath, stash), err: err, hadConflict: false}
 		}
 
-		return branchPulledMsg{err: nil, hadConflict: false}
+		return branchPulledMsg{stash: stash, stashErr: m.restoreAutoStash(worktreePath, stash), err: nil, hadConflict: false}
+	}
+}
+
+// checkAndRebaseOnBase is the rebase counterpart of checkAndPullFromBase: it fetches, checks if the
+// worktree is behind and rebases the branch onto the base branch (<base remote>/<base> when it exists)
+func (m Model) checkAndRebaseOnBase(worktreePath, branch, baseBranch string, autoStash bool) tea.Cmd {
+	return func() tea.Msg {
+		// The force-push after the rebase must not overwrite commits the fetch brings in
+		lease := m.gitManager.RemoteBranchHead(worktreePath, branch)
+
+		// First: Fetch to get latest remote refs
+		if err := m.gitManager.FetchRemote(); err != nil {
+			return branchRebasedMsg{err: fmt.Errorf("failed to fetch: %w", err)}
+		}
+
+		// Rebase onto the fetched base so the branch is current with the remote, not a stale local copy
+		onto := m.gitManager.BaseRef(worktreePath, baseBranch)
+
+		// Second: Check if actually behind by comparing fresh refs
+		_, behindCount, err := m.gitManager.GetBranchStatus(worktreePath, "", onto)
+		if err != nil {
+			return branchRebasedMsg{err: fmt.Errorf("failed to check branch status: %w", err)}
+		}
+		if behindCount == 0 {
+			return branchRebasedMsg{err: fmt.Errorf("worktree is already up-to-date with base branch")}
+		}
+
+		// git refuses to rebase a dirty worktree, stash the changes or explain how to get there
+		stash := ""
+		if autoStash {
+			if stash, err = m.stashBeforeUpdate(worktreePath, baseBranch); err != nil {
+				return branchRebasedMsg{err: err}
+			}
+		} else if hasUncommitted, _ := m.gitManager.HasUncommittedChanges(worktreePath); hasUncommitted {
+			return branchRebasedMsg{err: fmt.Errorf("worktree has uncommitted changes. Commit or stash them ('z'), or enable Auto-stash in settings")}
+		}
+
+		// A branch that was pushed before has to be force-pushed after rewriting its history
+		hadUpstream := m.gitManager.HasUpstream(worktreePath)
+
+		err = m.gitManager.RebaseBranch(worktreePath, onto)
+		return m.rebaseResult(worktreePath, branch, onto, hadUpstream, lease, stash, err)
+	}
+}
+
+// continueRebase continues a rebase stopped on conflicts once they are resolved
+func (m Model) continueRebase(worktreePath, branch, onto string, hadUpstream bool, lease, stash string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.gitManager.ContinueRebase(worktreePath)
+		return m.rebaseResult(worktreePath, branch, onto, hadUpstream, lease, stash, err)
+	}
+}
+
+// stashBeforeUpdate stashes the uncommitted changes of a worktree before updating it from the base branch
+// Returns the stash message to find it again, "" if there was nothing to stash
+func (m Model) stashBeforeUpdate(worktreePath, baseBranch string) (string, error) {
+	message := fmt.Sprintf("jean: auto-stash before updating from %s", baseBranch)
+	stashed, err := m.gitManager.PushStash(worktreePath, message)
+	if err != nil || !stashed {
+		return "", err
+	}
+	return message, nil
+}
+
+// restoreAutoStash pops the auto-stash made by stashBeforeUpdate, a no-op for an empty message
+func (m Model) restoreAutoStash(worktreePath, message string) error {
+	if message == "" {
+		return nil
+	}
+
+	stashes, err := m.gitManager.ListStashes(worktreePath)
+	if err != nil {
+		return err
+	}
+	for _, stash := range stashes {
+		if stash.Message == message {
+			return m.gitManager.PopStash(worktreePath, stash.Ref)
+		}
+	}
+	return fmt.Errorf("auto-stash '%s' not found", message)
+}
+
+// resolveConflict resolves a conflicted file with one side ("ours"/"theirs") or marks it
+// resolved after it was edited by hand ("manual")
+func (m Model) resolveConflict(worktreePath string, file git.ConflictFile, side string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		resolution := "resolved manually"
+		switch side {
+		case "ours":
+			err = m.gitManager.ResolveOurs(worktreePath, file)
+			resolution = "took ours"
+		case "theirs":
+			err = m.gitManager.ResolveTheirs(worktreePath, file)
+			resolution = "took theirs"
+		default:
+			err = m.gitManager.MarkResolved(worktreePath, file.Path)
+		}
+
+		files, listErr := m.gitManager.Conflicts(worktreePath)
+		if err == nil {
+			err = listErr
+		}
+		return conflictsUpdatedMsg{worktreePath: worktreePath, files: files, path: file.Path, resolution: resolution, err: err}
+	}
+}
+
+// loadConflicts reloads the conflicted files of a worktree
+func (m Model) loadConflicts(worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := m.gitManager.Conflicts(worktreePath)
+		return conflictsUpdatedMsg{worktreePath: worktreePath, files: files, err: err}
+	}
+}
+
+// abortConflict aborts the merge or rebase stopped on conflicts and re-applies the auto-stash, if any
+func (m Model) abortConflict(worktreePath, operation, stash string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if operation == "rebase" {
+			err = m.gitManager.AbortRebase(worktreePath)
+		} else {
+			err = m.gitManager.AbortMerge(worktreePath)
+		}
+		if err != nil {
+			return conflictAbortedMsg{operation: operation, err: err}
+		}
+		return conflictAbortedMsg{operation: operation, stashErr: m.restoreAutoStash(worktreePath, stash)}
+	}
+}
+
+// prepareMergeCommit stages a merge whose conflicts are resolved so it can be committed
+// through the commit modal
+func (m Model) prepareMergeCommit(worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.gitManager.PrepareMergeCommit(worktreePath); err != nil {
+			return mergeReadyToCommitMsg{worktreePath: worktreePath, err: err}
+		}
+		return mergeReadyToCommitMsg{worktreePath: worktreePath, subject: m.gitManager.MergeMessage(worktreePath)}
+	}
+}
+
+// openConflictModal shows the conflicts of a merge or rebase.
+// Resolutions are kept while the same operation is reopened, so the commit summary covers all files.
+func (m *Model) openConflictModal(operation, worktreePath, branch, onto string, files []git.ConflictFile, hadUpstream bool) {
+	if m.conflictWorktree != worktreePath || m.conflictOperation != operation || m.conflictResolutions == nil {
+		m.conflictResolutions = make(map[string]string)
+		m.conflictStash = ""
+		m.conflictLease = ""
+		m.conflictLocalMerge = false
+	}
+	m.conflictWorktree = worktreePath
+	m.conflictOperation = operation
+	m.conflictBranch = branch
+	m.conflictOnto = onto
+	m.conflictHadUpstream = hadUpstream
+	m.setConflictFiles(files)
+	m.conflictCursor = 0
+	m.modal = conflictModal
+}
+
+// setConflictFiles updates the remaining conflicts, remembering every file that had one
+func (m *Model) setConflictFiles(files []git.ConflictFile) {
+	m.conflictFiles = files
+	for _, file := range files {
+		if _, ok := m.conflictResolutions[file.Path]; !ok {
+			m.conflictResolutions[file.Path] = ""
+		}
+	}
+	if m.conflictCursor >= len(files) {
+		m.conflictCursor = max(len(files)-1, 0)
+	}
+}
+
+// conflictSummary describes how each conflict was resolved, for the merge commit body
+func (m Model) conflictSummary() string {
+	if len(m.conflictResolutions) == 0 {
+		return ""
+	}
+
+	paths := make([]string, 0, len(m.conflictResolutions))
+	for path := range m.conflictResolutions {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	b.WriteString("Resolved conflicts:")
+	for _, path := range paths {
+		resolution := m.conflictResolutions[path]
+		if resolution == "" {
+			resolution = "resolved manually"
+		}
+		b.WriteString(fmt.Sprintf("\n- %s (%s)", path, resolution))
+	}
+	return b.String()
+}
+
+// commitTargetPath returns the path the commit modal commits in
+func (m Model) commitTargetPath() string {
+	if m.commitWorktreePath != "" {
+		return m.commitWorktreePath
+	}
+	if wt := m.selectedWorktree(); wt != nil {
+		return wt.Path
+	}
+	return ""
+}
+
+// closeCommitModal closes the commit modal and clears its inputs and commit target
+func (m *Model) closeCommitModal() {
+	m.modal = noModal
+	m.commitSubjectInput.Blur()
+	m.commitBodyInput.Blur()
+	m.commitBodyInput.Reset()
+	m.commitTrailersInput.Blur()
+	m.commitTrailersInput.Reset()
+	m.commitWorktreePath = ""
+	m.commitStagedOnly = false
+
+	// Squashing was started from the history modal, go back to it
+	if m.commitSquashOnto != "" {
+		m.commitSquashOnto = ""
+		m.modal = historyModal
+	}
+}
+
+// focusCommitInput focuses the commit modal field at modalFocused (0=subject, 1=body, 2=trailers)
+func (m *Model) focusCommitInput() {
+	m.commitSubjectInput.Blur()
+	m.commitBodyInput.Blur()
+	m.commitTrailersInput.Blur()
+	switch m.modalFocused {
+	case 0:
+		m.commitSubjectInput.Focus()
+	case 1:
+		m.commitBodyInput.Focus()
+	case 2:
+		m.commitTrailersInput.Focus()
+	}
+}
+
+// loadStashes loads the stashes of the repository for the stash modal
+func (m Model) loadStashes(worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		stashes, err := m.gitManager.ListStashes(worktreePath)
+		return stashesLoadedMsg{stashes: stashes, err: err}
+	}
+}
+
+// stashAction runs a stash modal action ("push", "pop", "apply" or "drop") in a worktree
+// For "push" ref is unused and message names the new stash
+func (m Model) stashAction(worktreePath, action, ref, message string) tea.Cmd {
+	return func() tea.Msg {
+		msg := stashActionMsg{action: action, ref: ref}
+		switch action {
+		case "push":
+			msg.stashed, msg.err = m.gitManager.PushStash(worktreePath, message)
+		case "pop":
+			msg.err = m.gitManager.PopStash(worktreePath, ref)
+		case "apply":
+			msg.err = m.gitManager.ApplyStash(worktreePath, ref)
+		case "drop":
+			msg.err = m.gitManager.DropStash(worktreePath, ref)
+		}
+		return msg
+	}
+}
+
+// loadDiff loads the changes shown in the diff viewer: uncommitted changes, or everything the
+// worktree changed since its branch forked off the base branch
+func (m Model) loadDiff(worktreePath string, againstBase bool) tea.Cmd {
+	baseBranch := m.baseBranch
+	return func() tea.Msg {
+		var files []git.ChangedFile
+		var err error
+		if againstBase {
+			files, err = m.gitManager.BaseDiff(worktreePath, baseBranch)
+		} else {
+			files, err = m.gitManager.UncommittedDiff(worktreePath)
+		}
+		if files == nil {
+			files = []git.ChangedFile{}
+		}
+		return diffLoadedMsg{worktreePath: worktreePath, againstBase: againstBase, files: files, err: err}
+	}
+}
+
+// diffLines returns the lines shown in the hunks pane for the selected file of the diff viewer
+func (m Model) diffLines() []string {
+	if m.diffFileIndex >= len(m.diffFiles) {
+		return nil
+	}
+	file := m.diffFiles[m.diffFileIndex]
+	var lines []string
+	for _, hunk := range file.Hunks {
+		lines = append(lines, hunk.Header)
+		lines = append(lines, hunk.Lines...)
+	}
+	return lines
+}
+
+// diffPageSize returns the number of lines shown in the panes of the diff viewer
+func (m Model) diffPageSize() int {
+	return max(m.height-10, 5)
+}
+
+// loadHistory loads the commits of the worktree branch that are not in the base branch
+func (m Model) loadHistory(worktreePath, baseBranch string) tea.Cmd {
+	return func() tea.Msg {
+		commits, err := m.gitManager.CommitsAhead(worktreePath, baseBranch)
+		if commits == nil {
+			commits = []git.Commit{}
+		}
+		return historyLoadedMsg{
+			worktreePath: worktreePath,
+			commits:      commits,
+			hasUpstream:  m.gitManager.HasUpstream(worktreePath),
+			err:          err,
+		}
+	}
+}
+
+// loadCommitDiff loads a commit for the diff pane of the history modal
+func (m Model) loadCommitDiff(worktreePath, hash string) tea.Cmd {
+	return func() tea.Msg {
+		diff, err := m.gitManager.CommitDiff(worktreePath, hash)
+		return commitDiffLoadedMsg{hash: hash, diff: diff, err: err}
+	}
+}
+
+// historyAction runs an action of the history modal on the worktree branch
+// reword takes the new subject, the body of the commit is kept
+func (m Model) historyAction(worktreePath, branch, action, hash, subject string) tea.Cmd {
+	opts := m.commitOptions("", nil)
+	return func() tea.Msg {
+		msg := historyActionMsg{worktreePath: worktreePath, action: action}
+		switch action {
+		case "amend":
+			_, msg.err = m.gitManager.AmendCommit(worktreePath, opts)
+		case "reword":
+			message, err := m.gitManager.CommitMessage(worktreePath, hash)
+			if err != nil {
+				msg.err = err
+				break
+			}
+			if _, body, ok := strings.Cut(message, "\n"); ok {
+				subject += "\n" + body
+			}
+			msg.err = m.gitManager.RewordCommit(worktreePath, hash, subject)
+		case "revert":
+			msg.err = m.gitManager.RevertCommit(worktreePath, hash)
+		case "force-push":
+			// pre_push hook can veto the push
+			hookOutput, err := m.runHook(config.HookPrePush, m.hookContext(worktreePath, branch))
+			if err != nil {
+				msg.err = err
+				break
+			}
+			msg.hookOutput = hookOutput
+			msg.err = m.gitManager.ForcePushWithLease(worktreePath, branch, "")
+		}
+		msg.rewritten = msg.err == nil && action != "revert" && action != "force-push" && m.gitManager.HasUpstream(worktreePath)
+		return msg
+	}
+}
+
+// squashCommits squashes the commits of the worktree branch ahead of the base branch into one
+func (m Model) squashCommits(worktreePath, baseBranch, subject, body string, trailers []string) tea.Cmd {
+	opts := m.commitOptions(body, trailers)
+	return func() tea.Msg {
+		_, err := m.gitManager.SquashCommits(worktreePath, baseBranch, subject, opts)
+		return historyActionMsg{
+			worktreePath: worktreePath,
+			action:       "squash",
+			rewritten:    err == nil && m.gitManager.HasUpstream(worktreePath),
+			err:          err,
+		}
+	}
+}
+
+// squashMessage proposes the message of the squashed commit: the subject of the first commit
+// and the subjects of the others as body
+func squashMessage(commits []git.Commit) (string, string) {
+	if len(commits) == 0 {
+		return "", ""
+	}
+	var body []string
+	for i := len(commits) - 2; i >= 0; i-- {
+		body = append(body, "- "+commits[i].Subject)
+	}
+	return commits[len(commits)-1].Subject, strings.Join(body, "\n")
+}
+
+// visibleStashes returns the stashes shown in the stash modal: those made on the selected
+// worktree's branch, or all of them when toggled
+func (m Model) visibleStashes() []git.Stash {
+	wt := m.selectedWorktree()
+	if m.stashShowAll || wt == nil {
+		return m.stashes
+	}
+
+	var stashes []git.Stash
+	for _, stash := range m.stashes {
+		if stash.Branch == wt.Branch {
+			stashes = append(stashes, stash)
+		}
+	}
+	return stashes
+}
+
+// loadChanges loads the uncommitted changes of a worktree for the staging modal
+func (m Model) loadChanges(worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := m.gitManager.Changes(worktreePath)
+		return changesLoadedMsg{worktreePath: worktreePath, files: files, err: err}
 	}
 }
 
+// stageChanges stages the files and hunks picked in the staging modal
+func (m Model) stageChanges(worktreePath string, selections []git.Selection) tea.Cmd {
+	return func() tea.Msg {
+		return changesStagedMsg{worktreePath: worktreePath, err: m.gitManager.StageSelection(worktreePath, selections)}
+	}
+}
+
+// stageRow is a line of the staging modal: a file (hunk == -1) or one of its hunks
+type stageRow struct {
+	file int
+	hunk int
+}
+
+// stageRows returns the visible rows of the staging modal, hunks are listed under expanded files
+func (m Model) stageRows() []stageRow {
+	var rows []stageRow
+	for i, file := range m.stageFiles {
+		rows = append(rows, stageRow{file: i, hunk: -1})
+		if m.stageExpanded[i] && file.HunkSelectable() {
+			for h := range file.Hunks {
+				rows = append(rows, stageRow{file: i, hunk: h})
+			}
+		}
+	}
+	return rows
+}
+
+// setStageFiles shows new changes in the staging modal with everything selected
+func (m *Model) setStageFiles(worktreePath string, files []git.ChangedFile) {
+	m.stageWorktree = worktreePath
+	m.stageFiles = files
+	m.stageSelected = make([][]bool, len(files))
+	for i, file := range files {
+		count := 1
+		if file.HunkSelectable() {
+			count = len(file.Hunks)
+		}
+		m.stageSelected[i] = make([]bool, count)
+		for h := range m.stageSelected[i] {
+			m.stageSelected[i][h] = true
+		}
+	}
+	m.stageExpanded = make(map[int]bool)
+	m.stageCursor = 0
+}
+
+// toggleStageRow selects or deselects a hunk, or a whole file (all of its hunks)
+func (m *Model) toggleStageRow(row stageRow) {
+	selected := m.stageSelected[row.file]
+	if row.hunk >= 0 {
+		selected[row.hunk] = !selected[row.hunk]
+		return
+	}
+
+	// A partially selected file becomes fully selected
+	value := m.stageFileState(row.file) != "all"
+	for h := range selected {
+		selected[h] = value
+	}
+}
+
+// stageFileState returns "all", "none" or "some" depending on the selected hunks of a file
+func (m Model) stageFileState(file int) string {
+	count := 0
+	for _, selected := range m.stageSelected[file] {
+		if selected {
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return "none"
+	case len(m.stageSelected[file]):
+		return "all"
+	}
+	return "some"
+}
+
+// stageSelections converts the staging modal state for git.StageSelection
+func (m Model) stageSelections() []git.Selection {
+	var selections []git.Selection
+	for i, file := range m.stageFiles {
+		switch m.stageFileState(i) {
+		case "all":
+			selections = append(selections, git.Selection{File: file})
+		case "some":
+			var hunks []int
+			for h, selected := range m.stageSelected[i] {
+				if selected {
+					hunks = append(hunks, h)
+				}
+			}
+			selections = append(selections, git.Selection{File: file, Hunks: hunks})
+		}
+	}
+	return selections
+}
+
+// rebaseResult builds the message for a rebase step: conflicts list the files to resolve,
+// a finished rebase re-applies the auto-stash and a previously pushed branch is force-pushed with lease
+func (m Model) rebaseResult(worktreePath, branch, onto string, hadUpstream bool, lease, stash string, err error) branchRebasedMsg {
+	msg := branchRebasedMsg{worktreePath: worktreePath, branch: branch, onto: onto, hadUpstream: hadUpstream, lease: lease, stash: stash}
+
+	if err != nil {
+		msg.err = err
+		if errors.Is(err, git.ErrMergeConflict) && m.gitManager.IsRebaseInProgress(worktreePath) {
+			// The changes stay stashed until the rebase is continued or aborted
+			msg.hadConflict = true
+			msg.files, _ = m.gitManager.Conflicts(worktreePath)
+			return msg
+		}
+		msg.stashErr = m.restoreAutoStash(worktreePath, stash)
+		return msg
+	}
+
+	msg.stashErr = m.restoreAutoStash(worktreePath, stash)
+	if hadUpstream {
+		// pre_push hook can veto the push, the rebase itself already succeeded
+		hookOutput, err := m.runHook(config.HookPrePush, m.hookContext(worktreePath, branch))
+		if err != nil {
+			msg.pushErr = err
+			return msg
+		}
+		msg.hookOutput = hookOutput
+		msg.pushErr = m.gitManager.ForcePushWithLease(worktreePath, branch, lease)
+		msg.pushed = msg.pushErr == nil
+	}
+	return msg
+}
+
 // prepareLocalMerge fetches remote and gets branch status for merge confirmation
 // This prepares the data needed to show the merge confirmation modal
 func (m Model) prepareLocalMerge(worktreePath, branch, baseBranch string) tea.Cmd {
 	return func() tea.Msg {
+		// Protected branches are long-lived; merging one into the base and cleaning it up is never intended
+		if m.gitManager.IsProtectedBranch(branch) {
+			return localMergePreparedMsg{err: fmt.Errorf("cannot merge '%s': %w", branch, git.ErrProtectedBranch)}
+		}
+
 		// First: Fetch to get latest remote refs
 		if err := m.gitManager.FetchRemote(); err != nil {
 			return localMergePreparedMsg{err: fmt.Errorf("failed to fetch: %w", err)}
@@ -1818,10 +3118,13 @@ func (m Model) executeLocalMerge(worktreePath, branch, baseBranch string) tea.Cm
 		err = m.gitManager.MergeBranch(repoRoot, branch)
 		if err != nil {
 			// Check if it's a merge conflict
-			if strings.Contains(err.Error(), "merge conflict") {
+			if errors.Is(err, git.ErrMergeConflict) {
+				files, _ := m.gitManager.Conflicts(repoRoot)
 				return localMergeCompletedMsg{
 					branch:       branch,
 					worktreePath: worktreePath,
+					mergePath:    repoRoot,
+					files:        files,
 					err:          err,
 					hadConflict:  true,
 				}
@@ -1834,12 +3137,30 @@ func (m Model) executeLocalMerge(worktreePath, branch, baseBranch string) tea.Cm
 			}
 		}
 
-		return localMergeCompletedMsg{
-			branch:       branch,
-			worktreePath: worktreePath,
-			err:          nil,
-			hadConflict:  false,
-		}
+		return m.localMergeCompleted(repoRoot, worktreePath, branch)
+	}
+}
+
+// completeLocalMerge runs the steps that follow a local merge once its conflicts were resolved
+// and the merge committed in the main repo
+func (m Model) completeLocalMerge(repoRoot, worktreePath, branch string) tea.Cmd {
+	return func() tea.Msg {
+		return m.localMergeCompleted(repoRoot, worktreePath, branch)
+	}
+}
+
+// localMergeCompleted runs the post_merge hook of a merged branch; the merge itself already succeeded
+func (m Model) localMergeCompleted(repoRoot, worktreePath, branch string) localMergeCompletedMsg {
+	// post_merge hook runs in the main repo
+	hookOutput, hookErr := m.runHook(config.HookPostMerge, m.hookContext(repoRoot, branch))
+
+	return localMergeCompletedMsg{
+		branch:       branch,
+		worktreePath: worktreePath,
+		err:          nil,
+		hadConflict:  false,
+		hookErr:      hookErr,
+		hookOutput:   hookOutput,
 	}
 }
 
@@ -1871,17 +3192,11 @@ func (m Model) refreshWithPull() tea.Cmd {
 			}
 
 			// Pull this worktree's current branch
-			var output string
-			var err error
-
+			path := wt.Path
 			if wt.IsCurrent {
-				// For main repo, use PullCurrentBranchWithOutput
-				output, err = m.gitManager.PullCurrentBranchWithOutput(m.repoPath, wt.Branch)
-			} else {
-				// For workspace branches, use PullBranchInPathWithOutput
-				output, err = m.gitManager.PullBranchInPathWithOutput(wt.Path, wt.Branch)
+				path = m.repoPath
 			}
-
+			result, err := m.gitManager.PullBranch(path, wt.Branch)
 			if err != nil {
 				// Pull failed for this worktree, but continue with others
 				// Store the first error if no error was already recorded
@@ -1891,10 +3206,8 @@ func (m Model) refreshWithPull() tea.Cmd {
 				continue
 			}
 
-			// Parse the output to extract commit count
-			isUpToDate, commitCount := m.gitManager.ParsePullOutput(output)
-			if !isUpToDate && commitCount > 0 {
-				msg.updatedBranches[wt.Branch] = commitCount
+			if !result.UpToDate && result.Commits > 0 {
+				msg.updatedBranches[wt.Branch] = result.Commits
 				msg.upToDate = false
 			}
 		}
@@ -1930,14 +3243,76 @@ func (m Model) animateRenameSpinner() tea.Cmd {
 // checkSessionActivity checks for recent session activity in current repository
 func (m Model) checkSessionActivity() tea.Cmd {
 	return func() tea.Msg {
-		sessions, err := m.sessionManager.List(m.repoPath)
+		sessions, err := m.sessionManager.List(m.repoPath, m.worktreePaths()...)
 		if err != nil {
 			return activityCheckedMsg{sessions: []session.Session{}, err: err}
 		}
-		return activityCheckedMsg{sessions: sessions, err: nil}
+
+		// Collect running/exited state of script windows for each session
+		scriptWindows := make(map[string][]session.ScriptWindow)
+		for _, sess := range sessions {
+			if windows, err := m.sessionManager.ListScriptWindows(sess.Name); err == nil && len(windows) > 0 {
+				scriptWindows[sess.Name] = windows
+			}
+		}
+		return activityCheckedMsg{sessions: sessions, scriptWindows: scriptWindows, err: nil}
 	}
 }
 
+// loadScripts loads the scripts from jean.json in the repository root
+func (m Model) loadScripts() tea.Cmd {
+	return func() tea.Msg {
+		scriptConfig, err := config.LoadScripts(m.repoPath)
+		return scriptsLoadedMsg{config: scriptConfig, err: err}
+	}
+}
+
+// runScript runs a jean.json script in its own window of the worktree's tmux session,
+// or in the background when the script is not interactive
+func (m Model) runScript(wt git.Worktree, script string) tea.Cmd {
+	return func() tea.Msg {
+		spec, ok := m.scriptConfig.LookupScript(script)
+		if !ok {
+			return scriptStartedMsg{script: script, err: fmt.Errorf("script '%s' not found in jean.json", script)}
+		}
+
+		hookCtx := m.hookContext(wt.Path, wt.Branch)
+		if hookCtx.Port == 0 && wt.Path != m.repoPath {
+			// Worktrees created before port allocation get their block on first run
+			hookCtx.Port = m.gitManager.AllocatePortBlock(wt.Path)
+		}
+		if !spec.IsInteractive() {
+			output, err := m.gitManager.RunScript(script, hookCtx)
+			return scriptFinishedMsg{script: script, output: output, err: err}
+		}
+
+		env := append(hookCtx.Environ(m.repoPath), spec.Environ()...)
+		if err := m.sessionManager.RunScript(wt.ClaudeSessionName, wt.Path, spec.Dir(wt.Path), script, spec.Command, env); err != nil {
+			return scriptStartedMsg{script: script, err: err}
+		}
+		return scriptStartedMsg{script: script, window: m.sessionManager.ScriptWindowName(script)}
+	}
+}
+
+// stopScript kills the window of a running jean.json script
+func (m Model) stopScript(wt git.Worktree, script string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.sessionManager.StopScript(wt.ClaudeSessionName, script)
+		return scriptStoppedMsg{script: script, err: err}
+	}
+}
+
+// scriptWindow returns the window of a script in a worktree's session, if it has been started
+func (m Model) scriptWindow(wt git.Worktree, script string) *session.ScriptWindow {
+	name := m.sessionManager.ScriptWindowName(script)
+	for _, window := range m.scriptWindows[wt.ClaudeSessionName] {
+		if window.Name == name {
+			return &window
+		}
+	}
+	return nil
+}
+
 // checkForUpdates checks if a new version of jean is available
 func (m Model) checkForUpdates() tea.Cmd {
 	return func() tea.Msg {
@@ -2084,7 +3459,15 @@ func (m Model) mergePR(worktreePath, prURL, mergeMethod string) tea.Cmd {
 		}
 
 		err := m.githubManager.MergePR(selected.Path, prURL, mergeMethod)
-		return prMergedMsg{prURL: prURL, branch: selected.Branch, err: err}
+		if err != nil {
+			return prMergedMsg{prURL: prURL, branch: selected.Branch, err: err}
+		}
+
+		// post_merge hook, the PR is already merged so failures are only reported
+		hookCtx := m.hookContext(selected.Path, selected.Branch)
+		hookCtx.PRURL = prURL
+		hookOutput, hookErr := m.runHook(config.HookPostMerge, hookCtx)
+		return prMergedMsg{prURL: prURL, branch: selected.Branch, hookErr: hookErr, hookOutput: hookOutput}
 	}
 }
 
@@ -2141,9 +3524,11 @@ type prMarkedReadyMsg struct {
 }
 
 type prMergedMsg struct {
-	prURL  string
-	branch string
-	err    error
+	prURL      string
+	branch     string
+	err        error
+	hookErr    error  // post_merge hook failure (PR itself was merged)
+	hookOutput string // Output of the post_merge hook, see runHook
 }
 
 // Message types for AI prompts modal
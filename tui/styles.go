@@ -57,6 +57,17 @@ var (
 	selectedDeleteButtonStyle  lipgloss.Style
 	disabledButtonStyle        lipgloss.Style
 
+	// Diff viewer styles
+	diffAddedStyle       lipgloss.Style
+	diffRemovedStyle     lipgloss.Style
+	diffHunkHeaderStyle  lipgloss.Style
+	diffChangedLineStyle lipgloss.Style
+	diffContextStyle     lipgloss.Style
+	syntaxKeywordStyle   lipgloss.Style
+	syntaxStringStyle    lipgloss.Style
+	syntaxCommentStyle   lipgloss.Style
+	syntaxNumberStyle    lipgloss.Style
+
 	// Notification styles
 	successNotifStyle lipgloss.Style
 	errorNotifStyle   lipgloss.Style
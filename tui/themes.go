@@ -293,6 +293,42 @@ func rebuildStyles(colors ThemeColors) {
 		Padding(0, 3).
 		MarginRight(2)
 
+	// Diff viewer styles
+	diffAddedStyle = lipgloss.NewStyle().
+		Foreground(colors.Success).
+		Background(colors.Surface2).
+		Bold(true)
+
+	diffRemovedStyle = lipgloss.NewStyle().
+		Foreground(colors.Error).
+		Background(colors.Surface2).
+		Bold(true)
+
+	diffHunkHeaderStyle = lipgloss.NewStyle().
+		Foreground(colors.Secondary).
+		Bold(true)
+
+	diffChangedLineStyle = lipgloss.NewStyle().
+		Foreground(colors.Foreground).
+		Background(colors.Surface2)
+
+	diffContextStyle = lipgloss.NewStyle().
+		Foreground(colors.Foreground)
+
+	syntaxKeywordStyle = lipgloss.NewStyle().
+		Foreground(colors.Primary).
+		Bold(true)
+
+	syntaxStringStyle = lipgloss.NewStyle().
+		Foreground(colors.Warning)
+
+	syntaxCommentStyle = lipgloss.NewStyle().
+		Foreground(colors.Muted).
+		Italic(true)
+
+	syntaxNumberStyle = lipgloss.NewStyle().
+		Foreground(colors.Accent)
+
 	// Notification styles
 	successNotifStyle = lipgloss.NewStyle().
 		Foreground(colors.Background).
@@ -1,10 +1,12 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -70,7 +72,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for i, wt := range msg.worktrees {
 				m.debugLog(fmt.Sprintf("  [%d] %s - HasUncommitted: %v", i, wt.Branch, wt.HasUncommitted))
 			}
+			// Keep the previous status of known worktrees until the fresh status arrives (avoids flicker)
+			previous := make(map[string]git.Worktree, len(m.worktrees))
+			for _, wt := range m.worktrees {
+				previous[wt.Path] = wt
+			}
 			m.worktrees = msg.worktrees
+			for i := range m.worktrees {
+				if old, ok := previous[m.worktrees[i].Path]; ok {
+					m.worktrees[i].HasUncommitted = old.HasUncommitted
+					m.worktrees[i].AheadCount = old.AheadCount
+					m.worktrees[i].BehindCount = old.BehindCount
+					m.worktrees[i].IsOutdated = old.IsOutdated
+					m.worktrees[i].SparseDirs = old.SparseDirs
+				}
+			}
 
 			// Mark initialization as complete after first successful worktree load
 			m.isInitializing = false
@@ -128,30 +144,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			// Launch background status loaders for each worktree (non-blocking)
+			// Stream status for each worktree from a bounded worker pool (non-blocking)
 			// This enables progressive status updates as each worktree's data loads
-			statusLoaders := make([]tea.Cmd, 0, len(m.worktrees))
-			for i := range m.worktrees {
-				statusLoaders = append(statusLoaders, m.loadWorktreeStatus(i, m.worktrees[i]))
-			}
-			if len(statusLoaders) > 0 {
-				cmd = tea.Batch(statusLoaders...)
-			} else {
-				cmd = nil
-			}
+			cmd = m.loadWorktreeStatuses()
 		}
 		// After first successful worktree load, check if we need to show onboarding
 		return m, tea.Batch(cmd, m.checkOnboardingStatus())
 
 	case worktreeStatusUpdatedMsg:
+		// Drop results of a load that was superseded by a newer reload
+		if msg.generation != m.statusGeneration {
+			return m, nil
+		}
+		// Release the context of the finished load
+		if msg.done {
+			if m.statusCancel != nil {
+				m.statusCancel()
+				m.statusCancel = nil
+			}
+			return m, nil
+		}
 		// Update individual worktree with loaded status data (no blocking, progressive update)
-		if msg.index >= 0 && msg.index < len(m.worktrees) {
-			m.worktrees[msg.index].HasUncommitted = msg.hasUncommitted
-			m.worktrees[msg.index].AheadCount = msg.aheadCount
-			m.worktrees[msg.index].BehindCount = msg.behindCount
-			m.worktrees[msg.index].IsOutdated = msg.behindCount > 0
+		if msg.status.Err != nil {
+			m.debugLog(fmt.Sprintf("Failed to load status for %s: %v", msg.status.Path, msg.status.Err))
+		} else {
+			for i := range m.worktrees {
+				if m.worktrees[i].Path == msg.status.Path {
+					m.worktrees[i].HasUncommitted = msg.status.HasUncommitted
+					m.worktrees[i].AheadCount = msg.status.AheadCount
+					m.worktrees[i].BehindCount = msg.status.BehindCount
+					m.worktrees[i].IsOutdated = msg.status.BehindCount > 0
+					m.worktrees[i].SparseDirs = msg.status.SparseDirs
+					break
+				}
+			}
 		}
-		return m, nil
+		return m, waitForWorktreeStatus(msg.generation, msg.results)
 
 	case onboardingStatusMsg:
 		// If user needs onboarding and we haven't shown it yet, show the modal
@@ -212,15 +240,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Reload worktrees to display the updated PR info
 		return m, m.loadWorktrees()
 
+	case createProgressMsg:
+		// Stays until the creation's result replaces it
+		m.showNotification(msg.step+"...", NotificationInfo, nil)
+		return m, waitForCreateProgress(msg.events)
+
 	case worktreeCreatedMsg:
 		if msg.err != nil {
-			// Check if this is a setup script error (warning) or a git error (error)
-			errMsg := msg.err.Error()
-			if strings.Contains(errMsg, "setup script failed") {
-				// Setup script failed - show warning but worktree was created
-				// Extract just the relevant error message (skip "setup script failed: " prefix)
-				warningMsg := strings.TrimPrefix(errMsg, "setup script failed: ")
-				cmd = m.showWarningNotification(fmt.Sprintf("Worktree created but setup script failed:\n%s", warningMsg))
+			// Check if this is a setup script/copy warning or a git error (error)
+			if warningMsg, ok := worktreeCreatedWarning(msg.err); ok {
+				// Setup script failed or files were not copied - show warning but worktree was created
+				cmd = m.showWarningNotification(warningMsg)
 				m.modal = noModal
 				m.lastCreatedBranch = msg.branch
 
@@ -230,6 +260,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Path:              msg.path,
 					Branch:            msg.branch,
 					LastModified:      time.Now(),
+					Managed:           true,
 					ClaudeSessionName: m.sessionManager.SanitizeName(repoName, msg.branch),
 				}
 				m.worktrees = append(m.worktrees, tempWorktree)
@@ -246,12 +277,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Still refresh worktrees since the worktree was created successfully
 				return m, tea.Batch(cmd, m.loadWorktrees())
 			} else {
-				// Git worktree creation failed - show error
-				cmd = m.showErrorNotification("Failed to create worktree", 4*time.Second)
+				// Git worktree creation failed (or pre_create hook aborted it) - show error
+				cmd = m.showErrorNotification(hookErrorMessage("Failed to create worktree", msg.err), 4*time.Second)
 				return m, cmd
 			}
 		} else {
-			cmd = m.showSuccessNotification("Worktree created successfully", 3*time.Second)
+			cmd = m.worktreeCreatedNotification(msg.hint)
 			m.modal = noModal
 
 			// Store the newly created branch name for selection after reload
@@ -276,6 +307,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Path:              msg.path,
 				Branch:            msg.branch,
 				LastModified:      time.Now(), // Set to now so it appears at top after sorting
+				Managed:           true,
 				ClaudeSessionName: m.sessionManager.SanitizeName(repoName, msg.branch),
 				// Other fields (Commit, BehindCount, etc.) will be filled by background refresh
 			}
@@ -301,12 +333,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case worktreeCreatedWithSessionMsg:
 		if msg.err != nil {
-			// Check if this is a setup script error (warning) or a git error (error)
-			errMsg := msg.err.Error()
-			if strings.Contains(errMsg, "setup script failed") {
-				// Setup script failed - show warning but worktree was created
-				warningMsg := strings.TrimPrefix(errMsg, "setup script failed: ")
-				cmd = m.showWarningNotification(fmt.Sprintf("Worktree created but setup script failed:\n%s", warningMsg))
+			// Check if this is a setup script/copy warning or a git error (error)
+			if warningMsg, ok := worktreeCreatedWarning(msg.err); ok {
+				// Setup script failed or files were not copied - show warning but worktree was created
+				cmd = m.showWarningNotification(warningMsg)
 				m.modal = noModal
 				m.lastCreatedBranch = msg.branch
 				// Store session name for switch
@@ -323,6 +353,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Path:              msg.path,
 					Branch:            msg.branch,
 					LastModified:      time.Now(),
+					Managed:           true,
 					ClaudeSessionName: m.sessionManager.SanitizeName(repoName, msg.branch),
 				}
 				m.worktrees = append(m.worktrees, tempWorktree)
@@ -338,12 +369,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				return m, tea.Batch(cmd, m.loadWorktrees())
 			} else {
-				// Git worktree creation failed - show error
-				cmd = m.showErrorNotification("Failed to create worktree", 4*time.Second)
+				// Git worktree creation failed (or pre_create hook aborted it) - show error
+				cmd = m.showErrorNotification(hookErrorMessage("Failed to create worktree", msg.err), 4*time.Second)
 				return m, cmd
 			}
 		} else {
-			cmd = m.showSuccessNotification("Worktree created successfully", 3*time.Second)
+			cmd = m.worktreeCreatedNotification(msg.hint)
 			m.modal = noModal
 
 			// Store the newly created branch name for selection after reload
@@ -364,6 +395,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Path:              msg.path,
 				Branch:            msg.branch,
 				LastModified:      time.Now(), // Set to now so it appears at top after sorting
+				Managed:           true,
 				ClaudeSessionName: m.sessionManager.SanitizeName(repoName, msg.branch),
 				// Other fields (Commit, BehindCount, etc.) will be filled by background refresh
 			}
@@ -389,10 +421,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case worktreeDeletedMsg:
 		if msg.err != nil {
-			cmd = m.showErrorNotification("Failed to delete worktree", 4*time.Second)
+			cmd = m.showErrorNotification(hookErrorMessage("Failed to delete worktree", msg.err), 4*time.Second)
 			return m, cmd
 		} else {
-			cmd = m.showSuccessNotification("Worktree and branch deleted successfully", 3*time.Second)
+			cmd = m.showSuccessNotification(withHookOutput("Worktree and branch deleted successfully", msg.hookOutput), 3*time.Second)
 			m.modal = noModal
 			if m.selectedIndex >= len(m.worktrees)-1 {
 				m.selectedIndex = len(m.worktrees) - 2
@@ -407,13 +439,74 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			)
 		}
 
+	case detachedWorktreeCreatedMsg:
+		if msg.err != nil {
+			if warningMsg, ok := worktreeCreatedWarning(msg.err); ok {
+				m.modal = noModal
+				cmd = m.showWarningNotification(warningMsg)
+				return m, tea.Batch(cmd, m.loadWorktrees())
+			}
+			cmd = m.showErrorNotification(hookErrorMessage("Failed to create worktree at "+msg.ref, msg.err), 4*time.Second)
+			return m, cmd
+		}
+		m.modal = noModal
+		cmd = m.showSuccessNotification(fmt.Sprintf("Worktree created at %s, press 'B' to create a branch in it", msg.ref), 4*time.Second)
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case sparseCheckoutWidenedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification(msg.err.Error(), 4*time.Second)
+			return m, cmd
+		}
+		if msg.profile == "" {
+			cmd = m.showSuccessNotification(fmt.Sprintf("'%s' now has a full checkout", msg.branch), 3*time.Second)
+		} else {
+			cmd = m.showSuccessNotification(fmt.Sprintf("Added the '%s' directories to '%s'", msg.profile, msg.branch), 3*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case worktreeAdoptedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification("Failed to adopt worktree: "+msg.err.Error(), 4*time.Second)
+			return m, cmd
+		}
+		if msg.moved {
+			cmd = m.showSuccessNotification(fmt.Sprintf("Adopted '%s' and moved it to %s", msg.name, msg.path), 4*time.Second)
+		} else {
+			cmd = m.showSuccessNotification(fmt.Sprintf("Adopted '%s'", msg.name), 3*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadWorktrees(), m.loadSessions())
+
+	case worktreeLockedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification(msg.err.Error(), 4*time.Second)
+			return m, cmd
+		}
+		if msg.locked {
+			cmd = m.showSuccessNotification(fmt.Sprintf("Locked '%s', it is only removed when forced", msg.branch), 3*time.Second)
+		} else {
+			cmd = m.showSuccessNotification(fmt.Sprintf("Unlocked '%s'", msg.branch), 3*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
 	case branchRenamedMsg:
 		if msg.err != nil {
+			if errors.Is(msg.err, git.ErrProtectedBranch) {
+				cmd = m.showWarningNotification("Failed to rename branch: " + msg.err.Error())
+				return m, cmd
+			}
+			if msg.createdFrom != "" {
+				cmd = m.showErrorNotification("Failed to create branch: "+msg.err.Error(), 4*time.Second)
+				return m, cmd
+			}
 			cmd = m.showErrorNotification("Failed to rename branch", 4*time.Second)
 			return m, cmd
 		} else {
 			// Branch renamed successfully (directory path unchanged to preserve sessions)
 			notificationMsg := fmt.Sprintf("Branch renamed: %s → %s", msg.oldBranch, msg.newBranch)
+			if msg.createdFrom != "" {
+				notificationMsg = fmt.Sprintf("Created branch %s at %s", msg.newBranch, msg.createdFrom)
+			}
 			cmd = m.showSuccessNotification(notificationMsg, 4*time.Second)
 
 			// Track the renamed branch for auto-selection after reload
@@ -441,7 +534,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Load worktrees with lightweight mode for instant UI appearance
 		// Status data (uncommitted changes, ahead/behind counts) loads asynchronously in background
 		// This dramatically improves perceived startup performance with many worktrees
-		return m, m.loadWorktreesLightweight()
+		return m, m.loadWorktrees()
 
 	case gitInitCompletedMsg:
 		if msg.err != nil {
@@ -478,6 +571,235 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.sessions = msg.sessions
 		return m, nil
 
+	case scriptsLoadedMsg:
+		// Invalid entries are skipped, the rest of jean.json is still used
+		var validationErr *config.ValidationError
+		if errors.As(msg.err, &validationErr) {
+			cmd = m.showWarningNotification(scriptConfigWarning(validationErr))
+		} else if msg.err != nil {
+			m.scriptConfig = nil
+			if m.modal == scriptPickerModal {
+				m.modal = noModal
+				return m, m.showErrorNotification("Failed to load jean.json: "+msg.err.Error(), 5*time.Second)
+			}
+			return m, nil
+		}
+		m.scriptConfig = msg.config
+		if m.modal == scriptPickerModal && !m.scriptConfig.HasScripts() {
+			m.modal = noModal
+			if validationErr == nil {
+				cmd = m.showWarningNotification("No scripts configured in jean.json")
+			}
+			return m, cmd
+		}
+		if m.scriptIndex >= len(m.scriptConfig.GetScriptNames()) {
+			m.scriptIndex = 0
+		}
+		return m, cmd
+
+	case changesLoadedMsg:
+		if m.modal != stageModal {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.modal = noModal
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		if len(msg.files) == 0 {
+			m.modal = noModal
+			cmd = m.showInfoNotification("Nothing to commit - no uncommitted changes")
+			return m, cmd
+		}
+		m.setStageFiles(msg.worktreePath, msg.files)
+		return m, nil
+
+	case changesStagedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification("Failed to stage changes: "+msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		m.modal = noModal
+		return m.startCommit(msg.worktreePath, true)
+
+	case stashesLoadedMsg:
+		if msg.err != nil {
+			if m.modal == stashModal {
+				m.modal = noModal
+			}
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		m.stashes = msg.stashes
+		if m.stashIndex >= len(m.visibleStashes()) {
+			m.stashIndex = max(len(m.visibleStashes())-1, 0)
+		}
+		return m, nil
+
+	case staleWorktreesLoadedMsg:
+		if msg.err != nil {
+			if m.modal == gcModal {
+				m.modal = noModal
+			}
+			cmd = m.showErrorNotification("Failed to find stale worktrees: "+msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		m.gcWorktrees = msg.worktrees
+		if m.gcWorktrees == nil {
+			m.gcWorktrees = []git.StaleWorktree{}
+		}
+		m.gcSelected = make([]bool, len(msg.worktrees))
+		for i, wt := range msg.worktrees {
+			m.gcSelected[i] = !wt.HasUncommitted && !wt.Locked
+		}
+		m.gcOrphanBranches = msg.orphanBranches
+		m.gcOrphanSessions = msg.orphanSessions
+		m.gcOrphansSelected = true
+		m.gcIndex = 0
+		return m, nil
+
+	case staleWorktreesRemovedMsg:
+		m.gcRemoving = false
+		m.modal = noModal
+		if m.selectedIndex >= len(m.worktrees)-msg.removed {
+			m.selectedIndex = max(len(m.worktrees)-msg.removed-1, 0)
+		}
+		summary := fmt.Sprintf("Removed %d stale worktree(s)", msg.removed)
+		if msg.orphans > 0 {
+			summary += fmt.Sprintf(" and %d orphaned entries", msg.orphans)
+		}
+		if len(msg.failed) > 0 {
+			cmd = m.showErrorNotification(withHookOutput(fmt.Sprintf("%s, failed: %s", summary, strings.Join(msg.failed, "; ")), msg.hookOutputs...), 6*time.Second)
+		} else {
+			cmd = m.showSuccessNotification(withHookOutput(summary, msg.hookOutputs...), 3*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case diffLoadedMsg:
+		if msg.worktreePath != m.diffWorktree || msg.againstBase != m.diffAgainstBase {
+			return m, nil
+		}
+		if msg.err != nil {
+			if m.modal == diffModal {
+				m.modal = noModal
+			}
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		m.diffFiles = msg.files
+		if m.diffFileIndex >= len(m.diffFiles) {
+			m.diffFileIndex = max(len(m.diffFiles)-1, 0)
+			m.diffScroll = 0
+		}
+		m.diffScroll = min(m.diffScroll, max(len(m.diffLines())-m.diffPageSize(), 0))
+		return m, nil
+
+	case historyLoadedMsg:
+		if msg.worktreePath != m.historyWorktree {
+			return m, nil
+		}
+		if msg.err != nil {
+			if m.modal == historyModal {
+				m.modal = noModal
+			}
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		m.historyCommits = msg.commits
+		m.historyHasUpstream = msg.hasUpstream
+		if m.historyIndex >= len(m.historyCommits) {
+			m.historyIndex = max(len(m.historyCommits)-1, 0)
+		}
+		return m, nil
+
+	case commitDiffLoadedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		m.historyDiff = strings.Split(strings.TrimRight(msg.diff, "\n"), "\n")
+		m.historyDiffScroll = 0
+		return m, nil
+
+	case historyActionMsg:
+		m.historyRevertConfirm = false
+		if msg.err != nil {
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, tea.Batch(cmd, m.loadHistory(msg.worktreePath, m.baseBranch), m.loadWorktrees())
+		}
+
+		switch msg.action {
+		case "amend":
+			cmd = m.showSuccessNotification("Uncommitted changes added to the last commit", 2*time.Second)
+		case "reword":
+			cmd = m.showSuccessNotification("Commit reworded", 2*time.Second)
+		case "revert":
+			cmd = m.showSuccessNotification("Revert commit created", 2*time.Second)
+		case "squash":
+			cmd = m.showSuccessNotification("Commits squashed into one", 2*time.Second)
+		case "force-push":
+			cmd = m.showSuccessNotification(withHookOutput("Branch force-pushed (with lease)", msg.hookOutput), 2*time.Second)
+		}
+		if msg.rewritten {
+			cmd = m.showWarningNotification("The branch was pushed before - press 'F' in the history (l) to force-push it (with lease)")
+		}
+		return m, tea.Batch(cmd, m.loadHistory(msg.worktreePath, m.baseBranch), m.loadWorktrees())
+
+	case stashActionMsg:
+		m.stashDropConfirm = false
+		wt := m.selectedWorktree()
+		if wt == nil {
+			return m, nil
+		}
+		if msg.err != nil {
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, tea.Batch(cmd, m.loadStashes(wt.Path), m.loadWorktrees())
+		}
+
+		switch msg.action {
+		case "push":
+			if !msg.stashed {
+				cmd = m.showInfoNotification("No local changes to stash")
+			} else {
+				cmd = m.showSuccessNotification("Changes stashed", 2*time.Second)
+			}
+		case "pop":
+			cmd = m.showSuccessNotification(fmt.Sprintf("Popped %s", msg.ref), 2*time.Second)
+		case "apply":
+			cmd = m.showSuccessNotification(fmt.Sprintf("Applied %s (kept in the stash list)", msg.ref), 2*time.Second)
+		case "drop":
+			cmd = m.showSuccessNotification(fmt.Sprintf("Dropped %s", msg.ref), 2*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadStashes(wt.Path), m.loadWorktrees())
+
+	case scriptStartedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification(fmt.Sprintf("Failed to run '%s': %s", msg.script, msg.err.Error()), 5*time.Second)
+			return m, cmd
+		}
+		cmd = m.showSuccessNotification(fmt.Sprintf("Started '%s' in tmux window '%s'", msg.script, msg.window), 3*time.Second)
+		return m, tea.Batch(cmd, m.checkSessionActivity())
+
+	case scriptFinishedMsg:
+		if msg.err != nil {
+			errMsg := fmt.Sprintf("'%s' failed: %s", msg.script, msg.err.Error())
+			if output := strings.TrimSpace(msg.output); output != "" {
+				errMsg += "\n\nScript output:\n" + output
+			}
+			cmd = m.showErrorNotification(errMsg, 8*time.Second)
+			return m, cmd
+		}
+		cmd = m.showSuccessNotification(fmt.Sprintf("'%s' finished", msg.script), 3*time.Second)
+		return m, cmd
+
+	case scriptStoppedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification(fmt.Sprintf("Failed to stop '%s'", msg.script), 3*time.Second)
+			return m, cmd
+		}
+		cmd = m.showSuccessNotification(fmt.Sprintf("Stopped '%s'", msg.script), 2*time.Second)
+		return m, tea.Batch(cmd, m.checkSessionActivity())
+
 	case editorOpenedMsg:
 		if msg.err != nil {
 			cmd = m.showErrorNotification("Failed to open editor: " + msg.err.Error(), 4*time.Second)
@@ -530,7 +852,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.prRetryTitle = ""
 			m.prRetryDescription = ""
 
-			cmd = m.showErrorNotification("Failed to create PR: " + errMsg, 4*time.Second)
+			cmd = m.showErrorNotification(withHookOutput("Failed to create PR: " + errMsg, msg.hookOutput), 4*time.Second)
 			return m, cmd
 		} else {
 			// Clear retry state on successful creation
@@ -565,7 +887,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.isDraft {
 				statusMsg = "Draft PR created / updated"
 			}
-			cmd = m.showSuccessNotification(statusMsg + ": " + msg.prURL, 5*time.Second)
+			cmd = m.showSuccessNotification(withHookOutput(statusMsg + ": " + msg.prURL, msg.hookOutput), 5*time.Second)
 			return m, tea.Batch(
 				cmd,
 				m.loadWorktrees(),
@@ -669,10 +991,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.prModalBranch = msg.newBranchName
 
 			// Default title to new branch name
-			defaultTitle := strings.ReplaceAll(msg.newBranchName, "-", " ")
-			defaultTitle = strings.ReplaceAll(defaultTitle, "_", " ")
-			defaultTitle = strings.Title(defaultTitle)
-			m.prTitleInput.SetValue(defaultTitle)
+			m.prTitleInput.SetValue(github.TitleFromBranch(msg.newBranchName))
 			m.prTitleInput.Focus()
 			m.prDescriptionInput.SetValue("")
 
@@ -706,6 +1025,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmd = m.showSuccessNotification("Commit created successfully", 3*time.Second)
 			}
 
+			// The commit concluded a local merge stopped on conflicts - continue with its post_merge
+			// hook and cleanup like a merge that went through
+			if m.conflictLocalMerge && msg.worktreePath == m.conflictWorktree {
+				m.conflictLocalMerge = false
+				return m, tea.Batch(cmd, m.completeLocalMerge(msg.worktreePath, m.localMergeWorktree, m.localMergeBranch))
+			}
+
 			// Check if we're committing before PR creation
 			if m.commitBeforePR && m.prCreationPending != "" {
 				// Get the branch name from the pending PR worktree
@@ -963,7 +1289,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case pushCompletedMsg:
 		// Push completed
 		if msg.err != nil {
-			cmd = m.showErrorNotification("Failed to push: " + msg.err.Error(), 4*time.Second)
+			cmd = m.showErrorNotification(withHookOutput("Failed to push: " + msg.err.Error(), msg.hookOutput), 4*time.Second)
 			return m, tea.Batch(
 				cmd,
 				m.loadWorktrees(),
@@ -971,7 +1297,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Push succeeded
-		cmd = m.showSuccessNotification("Pushed to origin/"+msg.branch, 3*time.Second)
+		push, _ := m.remotes()
+		cmd = m.showSuccessNotification(withHookOutput("Pushed to "+push+"/"+msg.branch, msg.hookOutput), 3*time.Second)
 		return m, tea.Batch(
 			cmd,
 			m.loadWorktrees(),
@@ -989,17 +1316,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case branchPulledMsg:
 		if msg.err != nil {
 			if msg.hadConflict {
-				// Show error with abort option
-				cmd = m.showWarningNotification("Merge conflict! Run 'git merge --abort' in the worktree to abort.")
-				return m, cmd
+				// Show the conflicts so they can be resolved or the merge aborted
+				branch := ""
+				if wt := m.selectedWorktree(); wt != nil && wt.Path == msg.worktreePath {
+					branch = wt.Branch
+				}
+				m.openConflictModal("merge", msg.worktreePath, branch, msg.onto, msg.files, false)
+				m.conflictStash = msg.stash
+				return m, nil
 			} else if strings.Contains(msg.err.Error(), "already up-to-date") {
 				// User tried to pull but worktree is already up-to-date (after checking fresh refs)
 				cmd = m.showInfoNotification("Worktree is already up-to-date with base branch")
 				return m, cmd
 			} else {
-				cmd = m.showErrorNotification("Failed to pull from base branch: " + msg.err.Error(), 5*time.Second)
+				cmd = m.showErrorNotification("Failed to pull from base branch: " + msg.err.Error() + stashErrorSuffix(msg.stashErr), 5*time.Second)
 				return m, cmd
 			}
+		} else if msg.stashErr != nil {
+			cmd = m.showWarningNotification("Pulled changes from base branch" + stashErrorSuffix(msg.stashErr))
+			return m, tea.Batch(cmd, m.loadWorktrees())
 		} else {
 			cmd = m.showSuccessNotification("Successfully pulled changes from base branch", 3*time.Second)
 			return m, tea.Batch(
@@ -1008,6 +1343,95 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			)
 		}
 
+	case branchRebasedMsg:
+		if msg.err != nil {
+			if msg.hadConflict {
+				// Rebase stopped on conflicts - offer resolve/continue/abort
+				m.openConflictModal("rebase", msg.worktreePath, msg.branch, msg.onto, msg.files, msg.hadUpstream)
+				m.conflictStash = msg.stash
+				m.conflictLease = msg.lease
+				m.debugLog(fmt.Sprintf("Rebase of %s onto %s stopped on %d conflict(s)", msg.branch, msg.onto, len(msg.files)))
+				return m, m.loadWorktrees()
+			} else if strings.Contains(msg.err.Error(), "already up-to-date") {
+				cmd = m.showInfoNotification("Worktree is already up-to-date with base branch")
+				return m, cmd
+			}
+			cmd = m.showErrorNotification("Failed to rebase on base branch: " + msg.err.Error() + stashErrorSuffix(msg.stashErr), 5*time.Second)
+			return m, tea.Batch(cmd, m.loadWorktrees())
+		}
+
+		m.modal = noModal
+		switch {
+		case msg.stashErr != nil:
+			cmd = m.showWarningNotification(fmt.Sprintf("Rebased onto %s", msg.onto) + stashErrorSuffix(msg.stashErr))
+		case msg.pushErr != nil:
+			cmd = m.showWarningNotification(withHookOutput(fmt.Sprintf("Rebased onto %s but force-push failed: %s", msg.onto, msg.pushErr.Error()), msg.hookOutput))
+		case msg.pushed:
+			cmd = m.showSuccessNotification(withHookOutput(fmt.Sprintf("Rebased onto %s and force-pushed (with lease)", msg.onto), msg.hookOutput), 3*time.Second)
+		default:
+			cmd = m.showSuccessNotification(fmt.Sprintf("Rebased onto %s", msg.onto), 3*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case conflictsUpdatedMsg:
+		if msg.worktreePath != m.conflictWorktree {
+			return m, nil
+		}
+		m.setConflictFiles(msg.files)
+		if msg.err != nil {
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+		if msg.path != "" {
+			m.conflictResolutions[msg.path] = msg.resolution
+			m.debugLog(fmt.Sprintf("Conflict in %s: %s", msg.path, msg.resolution))
+			cmd = m.showSuccessNotification(fmt.Sprintf("%s: %s", msg.path, msg.resolution), 2*time.Second)
+			return m, cmd
+		}
+		return m, nil
+
+	case conflictAbortedMsg:
+		m.conflictResolutions = nil
+		if msg.err != nil {
+			cmd = m.showErrorNotification(msg.err.Error(), 5*time.Second)
+			return m, tea.Batch(cmd, m.loadWorktrees())
+		}
+		m.conflictStash = ""
+		m.conflictLocalMerge = false
+		if msg.stashErr != nil {
+			cmd = m.showWarningNotification("Aborted, branch restored" + stashErrorSuffix(msg.stashErr))
+		} else if msg.operation == "rebase" {
+			cmd = m.showInfoNotification("Rebase aborted, branch restored")
+		} else {
+			cmd = m.showInfoNotification("Merge aborted, branch restored")
+		}
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case mergeReadyToCommitMsg:
+		if msg.err != nil {
+			if errors.Is(msg.err, git.ErrMergeConflict) {
+				// Still unresolved files - back to the conflict list
+				m.modal = conflictModal
+				cmd = m.showWarningNotification(msg.err.Error())
+				return m, tea.Batch(cmd, m.loadConflicts(msg.worktreePath))
+			}
+			cmd = m.showErrorNotification("Failed to conclude merge: " + msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+
+		// Conclude the merge through the regular commit flow, with the resolutions as body
+		m.commitWorktreePath = msg.worktreePath
+		m.commitBodyInput.SetValue(m.conflictSummary())
+		m.conflictResolutions = nil
+		// The resolutions are staged, don't add the rest of the worktree to the merge commit
+		m.commitStagedOnly = true
+		m.modal = commitModal
+		m.modalFocused = 0
+		m.commitSubjectInput.SetValue(msg.subject)
+		m.commitSubjectInput.Focus()
+		m.commitModalStatus = ""
+		return m, nil
+
 	case localMergePreparedMsg:
 		if msg.err != nil {
 			cmd = m.showErrorNotification("Failed to prepare merge: " + msg.err.Error(), 5*time.Second)
@@ -1028,12 +1452,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case localMergeCompletedMsg:
 		if msg.err != nil {
 			if msg.hadConflict {
-				// Show error with abort option
-				cmd = m.showWarningNotification("Merge conflict! Resolve conflicts and commit, or run 'git merge --abort' in main repo.")
-				return m, tea.Batch(
-					cmd,
-					m.loadWorktrees(), // Refresh to show updated state
-				)
+				// Show the conflicts in the main repo so they can be resolved or the merge aborted
+				m.openConflictModal("merge", msg.mergePath, m.localMergeTarget, msg.branch, msg.files, false)
+				m.conflictLocalMerge = true
+				return m, m.loadWorktrees() // Refresh to show updated state
 			} else {
 				cmd = m.showErrorNotification("Failed to merge: " + msg.err.Error(), 5*time.Second)
 				return m, tea.Batch(
@@ -1048,6 +1470,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.postMergeDeleteIndex = 0 // Default to delete option
 		m.modal = postMergeCleanupModal
 
+		// Merge went through, a failing post_merge hook is only a warning
+		if msg.hookErr != nil {
+			cmd = m.showWarningNotification(hookErrorMessage("Merged but post_merge hook failed", msg.hookErr))
+			return m, tea.Batch(cmd, m.loadWorktrees())
+		}
+		if msg.hookOutput != "" {
+			cmd = m.showInfoNotification(withHookOutput("Merged", msg.hookOutput))
+			return m, tea.Batch(cmd, m.loadWorktrees())
+		}
+
 		// Update worktree list to show we're now on base branch
 		return m, m.loadWorktrees()
 
@@ -1099,6 +1531,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err == nil {
 			// Update sessions with activity information
 			m.sessions = msg.sessions
+			m.scriptWindows = msg.scriptWindows
 		}
 		// Continue scheduling activity checks
 		cmd = m.scheduleActivityCheck()
@@ -1124,6 +1557,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// If auto-committing with AI, show error and abort
 			if m.autoCommitWithAI {
 				m.autoCommitWithAI = false
+				m.commitStagedOnly = false
 				cmd := m.showErrorNotification("🤖 Failed to generate commit message: " + msg.err.Error(), 4*time.Second)
 				return m, cmd
 			}
@@ -1141,24 +1575,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// If auto-committing with AI, commit immediately without PR flow
 			if m.autoCommitWithAI {
 				m.autoCommitWithAI = false
+				stagedOnly := m.commitStagedOnly
+				m.commitStagedOnly = false
 				if wt := m.selectedWorktree(); wt != nil {
-					return m, m.createCommit(wt.Path, msg.subject)
+					return m, m.createCommit(wt.Path, msg.subject, "", nil, stagedOnly)
 				}
 				return m, nil
 			}
 			// If in PR creation flow, auto-commit with generated message
 			if m.commitBeforePR {
 				cmd := m.showInfoNotification("🤖 Committing with AI-generated message...")
-				return m, tea.Batch(cmd, m.createCommit(m.prCreationPending, msg.subject))
+				return m, tea.Batch(cmd, m.createCommit(m.prCreationPending, msg.subject, "", nil, false))
 			}
 			// Otherwise populate the commit message fields with AI-generated content for user review
 			m.commitSubjectInput.SetValue(msg.subject)
+			if msg.body != "" {
+				m.commitBodyInput.SetValue(msg.body)
+			}
 			// Set success status message
 			m.commitModalStatus = "✅ Message generated successfully - review and edit if needed"
 			m.commitModalStatusTime = time.Now()
 			// Move focus to subject input so user can review/edit
 			m.modalFocused = 0
-			m.commitSubjectInput.Focus()
+			m.focusCommitInput()
 			return m, nil
 		}
 
@@ -1253,7 +1692,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					cmd := m.showInfoNotification("🤖 Generating conventional commit message...")
 					m.commitBeforePR = true
 					m.prCreationPending = wt.Path // Set to trigger PR creation after commit
-					return m, tea.Batch(cmd, m.generateCommitMessageWithAI(wt.Path))
+					return m, tea.Batch(cmd, m.generateCommitMessageWithAI(wt.Path, false, false))
 				} else if hasAI {
 					// AI is enabled for branch but not commit - auto-commit with simple message and proceed
 					cmd := m.showInfoNotification("Committing changes...")
@@ -1355,6 +1794,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Worktree is now ensured to exist, proceed with switch
 		if m.pendingSwitchInfo != nil {
 			m.switchInfo = *m.pendingSwitchInfo
+			m.switchInfo.HookOutput = msg.hookOutput // The TUI quits, printed after it exits
 			m.pendingSwitchInfo = nil
 			return m, tea.Quit
 		}
@@ -1405,8 +1845,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			_ = m.configManager.UpdatePRStatus(m.repoPath, msg.branch, msg.prURL, "merged")
 		}
 
+		// PR is merged, a failing post_merge hook is only a warning
+		if msg.hookErr != nil {
+			cmd = m.showWarningNotification(hookErrorMessage("PR merged but post_merge hook failed", msg.hookErr))
+			return m, tea.Batch(cmd, m.loadWorktrees())
+		}
+
 		// Show success and reload worktrees
-		cmd = m.showSuccessNotification("PR merged successfully!", 3*time.Second)
+		cmd = m.showSuccessNotification(withHookOutput("PR merged successfully!", msg.hookOutput), 3*time.Second)
 		return m, tea.Batch(cmd, m.loadWorktrees())
 	}
 
@@ -1450,6 +1896,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.sessionNameInput.SetValue("")  // Start with empty input
 		m.sessionNameInput.Focus()       // Focus the input field
 		m.modalFocused = 0               // Focus on input field
+		m.createSparseProfile = ""
 		return m, nil
 
 	case "b":
@@ -1468,12 +1915,23 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.modal = branchSelectModal
 		m.modalFocused = 0
 		m.createNewBranch = false
+		m.createSparseProfile = ""
 		m.branchIndex = 0
 		m.searchInput.SetValue("")
 		m.searchInput.Focus()
 		m.filteredBranches = nil
 		return m, m.loadBranches
 
+	case "T":
+		// Create a worktree at a tag or commit (Shift+T), e.g. to bisect or reproduce a release bug
+		m.modal = refSelectModal
+		m.modalFocused = 0
+		m.branchIndex = 0
+		m.searchInput.SetValue("")
+		m.searchInput.Focus()
+		m.filteredBranches = nil
+		return m, m.loadTags
+
 	case "d":
 		// Open delete modal
 		if wt := m.selectedWorktree(); wt != nil && !wt.IsCurrent {
@@ -1484,6 +1942,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, cmd
 			}
 			m.deleteHasUncommitted = hasUncommitted
+			m.deleteLocked = wt.Locked
 			m.deleteConfirmForce = false
 			m.modal = deleteModal
 			m.modalFocused = 0
@@ -1502,11 +1961,11 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Check if this Claude session has been initialized before
 			isInitialized := false
 			if m.configManager != nil {
-				isInitialized = m.configManager.IsClaudeInitialized(m.repoPath, wt.Branch)
+				isInitialized = m.configManager.IsClaudeInitialized(m.repoPath, wt.Name())
 				// Mark this branch as initialized for next time
 				// (so next run will use --continue instead of plain claude)
 				if m.autoClaude && !isInitialized {
-					_ = m.configManager.SetClaudeInitialized(m.repoPath, wt.Branch)
+					_ = m.configManager.SetClaudeInitialized(m.repoPath, wt.Name())
 				}
 			}
 			// Store pending switch info and ensure worktree exists
@@ -1527,9 +1986,18 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "B":
 		// Rename current branch (Shift+B)
 		if wt := m.selectedWorktree(); wt != nil {
-			// Check if this is a workspace worktree (in .workspaces directory)
-			if !strings.Contains(wt.Path, ".workspaces") {
-				return m, m.showWarningNotification("Cannot rename main branch. Only workspace branches can be renamed.")
+			// A detached worktree has no branch to rename, create one at its HEAD instead
+			if wt.Detached {
+				m.modal = renameModal
+				m.modalFocused = 0
+				m.nameInput.SetValue("")
+				m.nameInput.Focus()
+				return m, nil
+			}
+
+			// Check if this is a workspace worktree (created by jean)
+			if !wt.Managed {
+				return m, m.showWarningNotification("Cannot rename this branch. Only branches of worktrees created by jean can be renamed, press 'A' to adopt it.")
 			}
 
 			// Check if this branch has PRs
@@ -1537,6 +2005,11 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, m.showErrorNotification("Cannot rename branch with existing PRs. Delete PRs first or close them manually.", 5*time.Second)
 			}
 
+			// Protected branches (jean.json "protected") are never renamed
+			if m.gitManager.IsProtectedBranch(wt.Branch) {
+				return m, m.showWarningNotification(fmt.Sprintf("Cannot rename protected branch '%s'", wt.Branch))
+			}
+
 			m.modal = renameModal
 			m.modalFocused = 0
 			m.nameInput.SetValue(wt.Branch)
@@ -1629,24 +2102,40 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "u":
 		// Update from base branch (pull/merge base branch changes)
 		if wt := m.selectedWorktree(); wt != nil {
+			if wt.Detached {
+				return m, m.showWarningNotification(detachedWarning("update from the base branch"))
+			}
+			// A merge or rebase stopped on conflicts earlier (also a local merge in the main repo):
+			// show the conflicts again
+			if reopened, cmd := m.reopenConflicts(wt); reopened {
+				return m, cmd
+			}
+
 			// Check if base branch is set
 			if m.baseBranch == "" {
 				return m, m.showWarningNotification("Base branch not set. Press 'b' to set base branch")
 			}
 
-			// Don't allow pull on main worktree
-			if !strings.Contains(wt.Path, ".workspaces") {
-				return m, m.showWarningNotification("Cannot pull on main worktree. Use 'git pull' manually.")
+			// Don't allow pull on the main worktree (or worktrees not created by jean)
+			if !wt.Managed {
+				return m, m.showWarningNotification("Can only pull into worktrees created by jean. Press 'A' to adopt it or use 'git pull' manually.")
 			}
 
 			// Fetch and check for updates (don't rely on cached status)
 			cmd = m.showInfoNotification("Checking for updates...")
-			return m, tea.Batch(cmd, m.checkAndPullFromBase(wt.Path, m.baseBranch))
+			autoStash := m.configManager != nil && m.configManager.GetAutoStash(m.repoPath)
+			if m.configManager != nil && m.configManager.GetUpdateMode(m.repoPath) == "rebase" {
+				return m, tea.Batch(cmd, m.checkAndRebaseOnBase(wt.Path, wt.Branch, m.baseBranch, autoStash))
+			}
+			return m, tea.Batch(cmd, m.checkAndPullFromBase(wt.Path, m.baseBranch, autoStash))
 		}
 
 	case "p":
 		// Push branch to remote (with AI branch naming) - lowercase p
 		if wt := m.selectedWorktree(); wt != nil {
+			if wt.Detached {
+				return m, m.showWarningNotification(detachedWarning("push"))
+			}
 			// First check if there are uncommitted changes
 			hasUncommitted, err := m.gitManager.HasUncommittedChanges(wt.Path)
 			if err != nil {
@@ -1666,7 +2155,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					cmd = m.showInfoNotification("🤖 Generating commit message...")
 					m.commitBeforePR = true // Reuse this flag to track commit-before-push
 					m.prCreationPending = "" // Empty means push-only (no PR)
-					return m, tea.Batch(cmd, m.generateCommitMessageWithAI(wt.Path))
+					return m, tea.Batch(cmd, m.generateCommitMessageWithAI(wt.Path, false, false))
 				} else if hasAI {
 					// AI is enabled for branch but not commit - auto-commit with simple message and proceed
 					cmd = m.showInfoNotification("Committing changes...")
@@ -1704,6 +2193,9 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "P":
 		// Create new PR on GitHub (Shift+P)
 		if wt := m.selectedWorktree(); wt != nil {
+			if wt.Detached {
+				return m, m.showWarningNotification(detachedWarning("create a PR"))
+			}
 			// Check if a PR already exists for this branch
 			if m.configManager != nil {
 				existingPR := m.configManager.GetLatestPR(m.repoPath, wt.Branch)
@@ -1743,14 +2235,17 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "L":
 		// Local merge: merge worktree branch into base branch locally (Shift+L)
 		if wt := m.selectedWorktree(); wt != nil {
+			if wt.Detached {
+				return m, m.showWarningNotification(detachedWarning("merge"))
+			}
 			// Safety check: base branch must be set
 			if m.baseBranch == "" {
 				return m, m.showWarningNotification("Base branch not set. Press 'b' to set base branch")
 			}
 
 			// Safety check: only allow merge from workspace worktrees (not main repo)
-			if !strings.Contains(wt.Path, ".workspaces") {
-				return m, m.showWarningNotification("Can only merge workspace worktrees. Use 'git merge' manually in main repo.")
+			if !wt.Managed {
+				return m, m.showWarningNotification("Can only merge worktrees created by jean. Press 'A' to adopt it or use 'git merge' manually.")
 			}
 
 			// Safety check: cannot merge base branch into itself
@@ -1764,7 +2259,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, m.showErrorNotification("Failed to check for uncommitted changes: "+err.Error(), 3*time.Second)
 			}
 			if hasUncommitted {
-				return m, m.showWarningNotification("Commit or stash changes before merging. Press 'c' to commit.")
+				return m, m.showWarningNotification("Commit or stash changes before merging. Press 'c' to commit or 'z' to stash.")
 			}
 
 			// All checks passed - prepare merge (fetch and get status)
@@ -1786,30 +2281,15 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, cmd
 			}
 
-			// Check if AI commit generation is enabled and API key is set
-			aiEnabled := m.configManager.GetAICommitEnabled()
-			apiKey := m.configManager.GetOpenRouterAPIKey()
-
-			if aiEnabled && apiKey != "" {
-				// Auto-generate and auto-commit with AI (no modal shown)
-				m.generatingCommit = true
-				m.spinnerFrame = 0
-				m.autoCommitWithAI = true    // Flag for standalone auto-commit
-				notifyCmd := m.showInfoNotification("🤖 Generating commit message...")
-				return m, tea.Batch(
-					notifyCmd,
-					m.animateSpinner(),
-					m.generateCommitMessageWithAI(wt.Path),
-				)
-			} else {
-				// Manual commit mode - open modal for user to type message
-				m.modal = commitModal
-				m.modalFocused = 0
-				m.commitSubjectInput.SetValue("")
-				m.commitSubjectInput.Focus()
-					m.commitModalStatus = "" // Clear any previous status
-				return m, nil
+			// A merge in progress (e.g. after resolving conflicts) has to be committed as a whole
+			if m.gitManager.IsMergeInProgress(wt.Path) {
+				return m.startCommit(wt.Path, false)
 			}
+
+			// Pick the files and hunks to commit first
+			m.modal = stageModal
+			m.stageFiles = nil
+			return m, m.loadChanges(wt.Path)
 		}
 
 	case "v":
@@ -1881,12 +2361,121 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "h":
-		// Open help modal
-		m.modal = helperModal
-		return m, nil
-	}
-
+	case "x":
+		// Run a jean.json script in its own tmux window
+		if wt := m.selectedWorktree(); wt != nil {
+			m.modal = scriptPickerModal
+			m.scriptIndex = 0
+			// Reload jean.json so edits are picked up without restarting
+			return m, m.loadScripts()
+		}
+
+	case "z":
+		// Stash list of the selected worktree
+		if wt := m.selectedWorktree(); wt != nil {
+			m.modal = stashModal
+			m.stashIndex = 0
+			m.stashInputActive = false
+			m.stashDropConfirm = false
+			return m, m.loadStashes(wt.Path)
+		}
+
+	case "l":
+		// Commit history of the selected worktree (commits ahead of the base branch)
+		if wt := m.selectedWorktree(); wt != nil {
+			if m.baseBranch == "" {
+				return m, m.showWarningNotification("Base branch not set. Press 'b' to set base branch")
+			}
+			m.modal = historyModal
+			m.historyWorktree = wt.Path
+			m.historyBranch = wt.Branch
+			m.historyCommits = nil
+			m.historyIndex = 0
+			m.historyDiff = nil
+			m.historyRewording = false
+			m.historyRevertConfirm = false
+			return m, m.loadHistory(wt.Path, m.baseBranch)
+		}
+
+	case "D":
+		// Diff viewer for the uncommitted changes of the selected worktree
+		if wt := m.selectedWorktree(); wt != nil {
+			m.modal = diffModal
+			m.diffWorktree = wt.Path
+			m.diffAgainstBase = false
+			m.diffFiles = nil
+			m.diffFileIndex = 0
+			m.diffScroll = 0
+			m.diffFocusHunks = false
+			return m, m.loadDiff(wt.Path, false)
+		}
+
+	case "W":
+		// Lock/unlock the selected worktree (locked worktrees are only removed when forced)
+		if wt := m.selectedWorktree(); wt != nil {
+			if wt.IsCurrent {
+				return m, m.showWarningNotification("The main worktree cannot be locked")
+			}
+			if wt.Locked {
+				return m, m.setWorktreeLock(wt.Path, wt.Branch, false, "")
+			}
+			m.modal = lockModal
+			m.lockReasonInput.SetValue("")
+			m.lockReasonInput.Focus()
+			return m, nil
+		}
+
+	case "A":
+		// Adopt a worktree added outside jean (git worktree add) so it is managed like the others
+		if wt := m.selectedWorktree(); wt != nil {
+			if wt.Managed {
+				return m, m.showInfoNotification(fmt.Sprintf("'%s' is already managed by jean", wt.DisplayName()))
+			}
+			if wt.IsCurrent || wt.Path == m.repoPath {
+				return m, m.showWarningNotification("The main worktree cannot be adopted")
+			}
+			if wt.Prunable {
+				return m, m.showWarningNotification("The directory of this worktree was deleted. Press 'C' to clean it up.")
+			}
+			movePath, err := m.gitManager.GetDefaultPath(wt.Name())
+			if err != nil {
+				return m, m.showErrorNotification("Failed to get worktree directory: "+err.Error(), 3*time.Second)
+			}
+			m.modal = adoptModal
+			m.adoptIndex = 0
+			m.adoptMovePath = movePath
+			return m, nil
+		}
+
+	case "E":
+		// Widen the sparse checkout of the selected worktree (another profile or a full checkout)
+		if wt := m.selectedWorktree(); wt != nil {
+			if wt.SparseDirs == nil {
+				return m, m.showInfoNotification("This worktree has a full checkout")
+			}
+			m.modal = sparseWidenModal
+			m.sparseWidenIndex = 0
+			return m, nil
+		}
+
+	case "C":
+		// Clean up worktrees with merged/closed PRs, merged branches or no recent changes
+		m.modal = gcModal
+		m.gcWorktrees = nil
+		m.gcSelected = nil
+		m.gcIndex = 0
+		m.gcRemoving = false
+		m.gcConfirmForce = false
+		m.gcOrphanBranches = nil
+		m.gcOrphanSessions = nil
+		return m, m.loadStaleWorktrees()
+
+	case "h":
+		// Open help modal
+		m.modal = helperModal
+		return m, nil
+	}
+
 	return m, nil
 }
 
@@ -1915,6 +2504,21 @@ func (m Model) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case renameModal:
 		return m.handleRenameModalInput(msg)
 
+	case refSelectModal:
+		return m.handleRefSelectModalInput(msg)
+
+	case worktreeLocationModal:
+		return m.handleWorktreeLocationModalInput(msg)
+
+	case remotesModal:
+		return m.handleRemotesModalInput(msg)
+
+	case adoptModal:
+		return m.handleAdoptModalInput(msg)
+
+	case sparseWidenModal:
+		return m.handleSparseWidenModalInput(msg)
+
 	case changeBaseBranchModal:
 		return m.handleChangeBaseBranchModalInput(msg)
 
@@ -1966,6 +2570,30 @@ func (m Model) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case helperModal:
 		return m.handleHelperModalInput(msg)
+
+	case scriptPickerModal:
+		return m.handleScriptPickerModalInput(msg)
+
+	case conflictModal:
+		return m.handleConflictModalInput(msg)
+
+	case stashModal:
+		return m.handleStashModalInput(msg)
+
+	case stageModal:
+		return m.handleStageModalInput(msg)
+
+	case historyModal:
+		return m.handleHistoryModalInput(msg)
+
+	case diffModal:
+		return m.handleDiffModalInput(msg)
+
+	case gcModal:
+		return m.handleGCModalInput(msg)
+
+	case lockModal:
+		return m.handleLockModalInput(msg)
 	}
 
 	return m, cmd
@@ -2195,6 +2823,11 @@ func (m Model) handleCreateWithNameModalInput(msg tea.KeyMsg) (tea.Model, tea.Cm
 		m.sessionNameInput.Blur()
 		return m, nil
 
+	case "ctrl+p":
+		// Cycle through the sparse-checkout profiles of jean.json
+		m.createSparseProfile = m.nextSparseProfile()
+		return m, nil
+
 	case "tab", "shift+tab":
 		// Cycle through: sessionNameInput -> create button -> cancel button
 		m.modalFocused = (m.modalFocused + 1) % 3
@@ -2268,16 +2901,16 @@ func (m Model) handleDeleteModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "tab", "left", "right":
-		// If uncommitted changes, we have 3 buttons (Yes/No/Force), otherwise 2 (Yes/No)
-		if m.deleteHasUncommitted {
+		// If uncommitted changes or a lock, we have 3 buttons (Yes/No/Force), otherwise 2 (Yes/No)
+		if m.deleteNeedsForce() {
 			m.modalFocused = (m.modalFocused + 1) % 3
 		} else {
 			m.modalFocused = (m.modalFocused + 1) % 2
 		}
 
 	case "enter", "y":
-		// If there are uncommitted changes and user hasn't confirmed force
-		if m.deleteHasUncommitted && !m.deleteConfirmForce {
+		// If there are uncommitted changes (or a lock) and user hasn't confirmed force
+		if m.deleteNeedsForce() && !m.deleteConfirmForce {
 			// modalFocused: 0 = Yes (blocked), 1 = No, 2 = Force Delete
 			if m.modalFocused == 2 || msg.String() == "f" {
 				// User clicked "Force Delete" - set confirmation flag
@@ -2289,14 +2922,17 @@ func (m Model) handleDeleteModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, nil
 			} else if m.modalFocused == 0 {
 				// User tried to click "Yes" but it's blocked
+				if m.deleteLocked {
+					return m, m.showWarningNotification("Cannot delete: worktree is locked. Use 'Force Delete' to proceed.")
+				}
 				return m, m.showWarningNotification("Cannot delete: uncommitted changes. Use 'Force Delete' to proceed.")
 			}
-		} else if m.deleteHasUncommitted && m.deleteConfirmForce {
+		} else if m.deleteNeedsForce() && m.deleteConfirmForce {
 			// User already confirmed, now execute force delete
 			if m.modalFocused == 0 || msg.String() == "y" {
 				if wt := m.selectedWorktree(); wt != nil {
 					m.modal = noModal
-					return m, m.deleteWorktree(wt.Path, wt.Branch, true) // force = true
+					return m, m.deleteWorktree(wt.Path, wt.Name(), true) // force = true
 				}
 			}
 			m.modal = noModal
@@ -2306,7 +2942,7 @@ func (m Model) handleDeleteModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.modalFocused == 0 || msg.String() == "y" {
 				if wt := m.selectedWorktree(); wt != nil {
 					m.modal = noModal
-					return m, m.deleteWorktree(wt.Path, wt.Branch, false)
+					return m, m.deleteWorktree(wt.Path, wt.Name(), false)
 				}
 			}
 			m.modal = noModal
@@ -2315,7 +2951,7 @@ func (m Model) handleDeleteModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "f":
 		// Shortcut for "Force Delete"
-		if m.deleteHasUncommitted && !m.deleteConfirmForce {
+		if m.deleteNeedsForce() && !m.deleteConfirmForce {
 			m.deleteConfirmForce = true
 			return m, m.showWarningNotification("Press 'y' or Enter to confirm force delete")
 		}
@@ -2325,6 +2961,11 @@ func (m Model) handleDeleteModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleBranchSelectModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+p" {
+		m.createSparseProfile = m.nextSparseProfile()
+		return m, nil
+	}
+
 	config := searchModalConfig{
 		onConfirm: func(m Model, branch string) (tea.Model, tea.Cmd) {
 			// Generate random path
@@ -2349,6 +2990,26 @@ func (m Model) handleCheckoutBranchModalInput(msg tea.KeyMsg) (tea.Model, tea.Cm
 	return m.handleSearchBasedModalInput(msg, config)
 }
 
+func (m Model) handleRefSelectModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Any commit can be typed, not only the listed tags
+	if msg.String() == "enter" && m.modalFocused == 2 && m.selectedBranch() == "" {
+		if ref := strings.TrimSpace(m.searchInput.Value()); ref != "" {
+			m.modal = noModal
+			m.searchInput.Blur()
+			cmd := m.showInfoNotification("Creating worktree at " + ref + "...")
+			return m, tea.Batch(cmd, m.createDetachedWorktree(ref))
+		}
+	}
+
+	config := searchModalConfig{
+		onConfirm: func(m Model, ref string) (tea.Model, tea.Cmd) {
+			cmd := m.showInfoNotification("Creating worktree at " + ref + "...")
+			return m, tea.Batch(cmd, m.createDetachedWorktree(ref))
+		},
+	}
+	return m.handleSearchBasedModalInput(msg, config)
+}
+
 func (m Model) handleSessionListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	config := listSelectionConfig{
 		getCurrentIndex: func() int { return m.sessionIndex },
@@ -2445,6 +3106,12 @@ func (m Model) handleRenameModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 
 			if wt := m.selectedWorktree(); wt != nil {
+				if wt.Detached {
+					cmd := m.showInfoNotification(fmt.Sprintf("Creating branch '%s'...", newName))
+					m.modal = noModal
+					m.nameInput.Blur()
+					return m, tea.Batch(cmd, m.createBranchHere(wt.Path, wt.Name(), wt.Ref, newName))
+				}
 				if newName == wt.Branch {
 					cmd := m.showInfoNotification("Branch name unchanged")
 					m.modal = noModal
@@ -2504,59 +3171,167 @@ func (m Model) handleChangeBaseBranchModalInput(msg tea.KeyMsg) (tea.Model, tea.
 	return m.handleSearchBasedModalInput(msg, config)
 }
 
-func (m Model) handleCommitModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// startCommit commits with an AI-generated message when enabled, otherwise opens the commit modal
+// With stagedOnly, only the changes staged in the staging modal are committed
+func (m Model) startCommit(worktreePath string, stagedOnly bool) (tea.Model, tea.Cmd) {
+	m.commitStagedOnly = stagedOnly
+
+	// Check if AI commit generation is enabled and API key is set
+	aiEnabled := m.configManager.GetAICommitEnabled()
+	apiKey := m.configManager.GetOpenRouterAPIKey()
+
+	if aiEnabled && apiKey != "" {
+		// Auto-generate and auto-commit with AI (no modal shown)
+		m.generatingCommit = true
+		m.spinnerFrame = 0
+		m.autoCommitWithAI = true // Flag for standalone auto-commit
+		notifyCmd := m.showInfoNotification("🤖 Generating commit message...")
+		return m, tea.Batch(
+			notifyCmd,
+			m.animateSpinner(),
+			m.generateCommitMessageWithAI(worktreePath, stagedOnly, false),
+		)
+	}
+
+	// Manual commit mode - open modal for user to type message
+	m.modal = commitModal
+	m.modalFocused = 0
+	m.commitSubjectInput.SetValue("")
+	m.commitSubjectInput.Focus()
+	m.commitModalStatus = "" // Clear any previous status
+	return m, nil
+}
+
+func (m Model) handleStageModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.stageRows()
+	var row *stageRow
+	if m.stageCursor < len(rows) {
+		row = &rows[m.stageCursor]
+	}
+
 	switch msg.String() {
 	case "esc":
 		m.modal = noModal
-		m.commitSubjectInput.Blur()
 		return m, nil
 
-	case "tab", "shift+tab":
-		// Cycle through: subject input -> commit button -> cancel button
-		m.modalFocused = (m.modalFocused + 1) % 3
+	case "up":
+		if m.stageCursor > 0 {
+			m.stageCursor--
+		}
+		return m, nil
 
-		// Update focus state
-		if m.modalFocused == 0 {
-			m.commitSubjectInput.Focus()
+	case "down":
+		if m.stageCursor < len(rows)-1 {
+			m.stageCursor++
+		}
+		return m, nil
+
+	case " ":
+		// Toggle the file or hunk under the cursor
+		if row != nil {
+			m.toggleStageRow(*row)
+		}
+		return m, nil
+
+	case "right":
+		// List the hunks of the file
+		if row != nil && m.stageFiles[row.file].HunkSelectable() {
+			m.stageExpanded[row.file] = true
+		}
+		return m, nil
+
+	case "left":
+		// Hide the hunks again, moving the cursor back to the file
+		if row != nil && m.stageExpanded[row.file] {
+			delete(m.stageExpanded, row.file)
+			for i, r := range m.stageRows() {
+				if r.file == row.file && r.hunk == -1 {
+					m.stageCursor = i
+					break
+				}
+			}
+		}
+		return m, nil
+
+	case "a":
+		// Select everything, or nothing if everything is selected
+		value := false
+		for i := range m.stageFiles {
+			if m.stageFileState(i) != "all" {
+				value = true
+				break
+			}
+		}
+		for i := range m.stageSelected {
+			for h := range m.stageSelected[i] {
+				m.stageSelected[i][h] = value
+			}
+		}
+		return m, nil
+
+	case "enter":
+		// Stage the selection and go on with the commit
+		selections := m.stageSelections()
+		if len(selections) == 0 {
+			return m, m.showWarningNotification("Nothing selected - press space to select files or hunks")
+		}
+		m.debugLog(fmt.Sprintf("Staging %d of %d changed files in %s", len(selections), len(m.stageFiles), m.stageWorktree))
+		return m, m.stageChanges(m.stageWorktree, selections)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleCommitModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closeCommitModal()
+		return m, nil
+
+	case "tab", "shift+tab":
+		// Cycle through: subject -> body -> trailers -> commit button -> cancel button
+		if msg.String() == "tab" {
+			m.modalFocused = (m.modalFocused + 1) % 5
 		} else {
-			m.commitSubjectInput.Blur()
+			m.modalFocused = (m.modalFocused + 4) % 5
 		}
+		m.focusCommitInput()
 		return m, nil
 
 	case "g":
-		// Generate AI commit message (only if not focused on input field and API key is configured)
-		if m.modalFocused > 0 && m.configManager != nil && m.configManager.GetOpenRouterAPIKey() != "" {
-			if wt := m.selectedWorktree(); wt != nil {
+		// Generate AI commit message (only if not focused on an input field and API key is configured)
+		if m.modalFocused > 2 && m.commitSquashOnto == "" && m.configManager != nil && m.configManager.GetOpenRouterAPIKey() != "" {
+			if path := m.commitTargetPath(); path != "" {
 				m.generatingCommit = true
 				m.spinnerFrame = 0
 				m.commitModalStatus = ""
 				return m, tea.Batch(
 					m.animateSpinner(),
-					m.generateCommitMessageWithAI(wt.Path),
+					m.generateCommitMessageWithAI(path, m.commitStagedOnly, true),
 				)
 			}
 		}
-		// If in input field (modalFocused 0), fall through to handle text input
+		// If in an input field, fall through to handle text input
 
 	case "enter":
 		if m.modalFocused == 0 {
-			// In subject input, move to commit button
+			// In subject input, move to the body
 			m.modalFocused = 1
-			m.commitSubjectInput.Blur()
+			m.focusCommitInput()
 			return m, nil
-		} else if m.modalFocused == 1 {
+		} else if m.modalFocused == 3 {
 			// Commit button
 			subject := m.commitSubjectInput.Value()
 			if subject == "" {
 				// If AI commit is enabled and API key is configured, try auto-generate
-				if m.configManager != nil && m.configManager.GetAICommitEnabled() && m.configManager.GetOpenRouterAPIKey() != "" {
-					if wt := m.selectedWorktree(); wt != nil {
+				if m.commitSquashOnto == "" && m.configManager != nil && m.configManager.GetAICommitEnabled() && m.configManager.GetOpenRouterAPIKey() != "" {
+					if path := m.commitTargetPath(); path != "" {
 						m.generatingCommit = true
 						m.spinnerFrame = 0
 						m.commitModalStatus = ""
 						return m, tea.Batch(
 							m.animateSpinner(),
-							m.generateCommitMessageWithAI(wt.Path),
+							m.generateCommitMessageWithAI(path, m.commitStagedOnly, true),
 						)
 					}
 				} else {
@@ -2566,24 +3341,43 @@ func (m Model) handleCommitModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			if wt := m.selectedWorktree(); wt != nil {
+			trailers, err := git.ParseTrailers(m.commitTrailersInput.Value())
+			if err != nil {
+				m.commitModalStatus = "❌ " + err.Error()
+				m.commitModalStatusTime = time.Now()
+				return m, nil
+			}
+
+			if path := m.commitTargetPath(); path != "" && m.commitSquashOnto != "" {
+				cmd := m.showInfoNotification("Squashing commits...")
+				body, onto := m.commitBodyInput.Value(), m.commitSquashOnto
+				m.closeCommitModal()
+				return m, tea.Batch(cmd, m.squashCommits(path, onto, subject, body, trailers))
+			}
+
+			if path := m.commitTargetPath(); path != "" {
 				cmd := m.showInfoNotification("Creating commit...")
-				m.modal = noModal
-				m.commitSubjectInput.Blur()
-				return m, tea.Batch(cmd, m.createCommit(wt.Path, subject))
+				body, stagedOnly := m.commitBodyInput.Value(), m.commitStagedOnly
+				m.closeCommitModal()
+				return m, tea.Batch(cmd, m.createCommit(path, subject, body, trailers, stagedOnly))
 			}
-		} else {
-			// Cancel button (modalFocused == 2)
-			m.modal = noModal
-			m.commitSubjectInput.Blur()
+		} else if m.modalFocused == 4 {
+			// Cancel button
+			m.closeCommitModal()
 			return m, nil
 		}
+		// In the body and trailers, enter inserts a new line
 	}
 
 	// Handle text input
 	var cmd tea.Cmd
-	if m.modalFocused == 0 {
+	switch m.modalFocused {
+	case 0:
 		m.commitSubjectInput, cmd = m.commitSubjectInput.Update(msg)
+	case 1:
+		m.commitBodyInput, cmd = m.commitBodyInput.Update(msg)
+	case 2:
+		m.commitTrailersInput, cmd = m.commitTrailersInput.Update(msg)
 	}
 
 	return m, cmd
@@ -3017,120 +3811,604 @@ func (m Model) handlePostMergeCleanupModalInput(msg tea.KeyMsg) (tea.Model, tea.
 	return m, nil
 }
 
-func (m Model) handlePRTypeModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// reopenConflicts shows the conflict modal again if the worktree has a merge or rebase
+// stopped on conflicts, e.g. after "resolve later" or when it was started outside jean
+func (m *Model) reopenConflicts(wt *git.Worktree) (bool, tea.Cmd) {
+	operation := ""
+	branch := wt.Branch
+	if rebasing := m.gitManager.RebasingBranch(wt.Path); rebasing != "" {
+		operation = "rebase"
+		branch = rebasing
+	} else if m.gitManager.IsMergeInProgress(wt.Path) {
+		operation = "merge"
+	} else {
+		return false, nil
+	}
+
+	onto, hadUpstream := m.baseBranch, false
+	if m.conflictWorktree == wt.Path && m.conflictOperation == operation {
+		onto, hadUpstream = m.conflictOnto, m.conflictHadUpstream
+	} else if operation == "rebase" {
+		// Started in an earlier session, or outside jean
+		hadUpstream = m.gitManager.HasUpstream(wt.Path)
+	}
+
+	files, _ := m.gitManager.Conflicts(wt.Path)
+	m.openConflictModal(operation, wt.Path, branch, onto, files, hadUpstream)
+	return true, nil
+}
+
+func (m Model) handleConflictModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	laterMsg := "Merge still in progress. Press 'u' on the worktree to get back to it."
+	if m.conflictOperation == "rebase" {
+		laterMsg = "Rebase still in progress. Press 'u' on the worktree to get back to it."
+	}
+
+	var selected *git.ConflictFile
+	if m.conflictCursor < len(m.conflictFiles) {
+		selected = &m.conflictFiles[m.conflictCursor]
+	}
+
 	switch msg.String() {
 	case "esc":
+		// Leave the merge/rebase in progress to resolve it later or outside jean
 		m.modal = noModal
-		return m, nil
+		return m, m.showWarningNotification(laterMsg)
 
 	case "up":
-		if m.prTypeCursor > 0 {
-			m.prTypeCursor--
+		if m.conflictCursor > 0 {
+			m.conflictCursor--
 		}
 		return m, nil
 
 	case "down":
-		if m.prTypeCursor < 1 {
-			m.prTypeCursor++
+		if m.conflictCursor < len(m.conflictFiles)-1 {
+			m.conflictCursor++
 		}
 		return m, nil
 
-	case "enter":
-		// User confirmed PR type selection
-		// Set isDraft based on selection (0=draft, 1=ready for review)
-		m.prIsDraft = (m.prTypeCursor == 0)
+	case "o":
+		// Take ours
+		if selected != nil {
+			return m, m.resolveConflict(m.conflictWorktree, *selected, "ours")
+		}
 
-		// Close modal and create PR with commit message as title
-		m.modal = noModal
+	case "t":
+		// Take theirs
+		if selected != nil {
+			return m, m.resolveConflict(m.conflictWorktree, *selected, "theirs")
+		}
 
-		// Use the last commit message as PR title
-		title := m.lastCommitMessage
-		if title == "" {
-			// Fallback if no commit message available (shouldn't happen in normal flow)
-			title = strings.ReplaceAll(m.prModalBranch, "-", " ")
-			title = strings.ReplaceAll(title, "_", " ")
-			title = strings.TrimSpace(title)
-			if len(title) > 0 {
-				title = strings.ToUpper(title[:1]) + title[1:]
-			}
+	case "e":
+		// Open in editor to resolve by hand, then mark resolved with 'r'
+		if selected != nil {
+			return m, m.openInEditor(filepath.Join(m.conflictWorktree, selected.Path))
 		}
 
-		// Create PR with commit message as title and empty description
-		cmd := m.showInfoNotification("Creating draft PR...")
-		return m, tea.Batch(cmd, m.createOrUpdatePR(m.prModalWorktreePath, m.prModalBranch, title, ""))
+	case "r":
+		// Mark resolved after editing
+		if selected != nil {
+			return m, m.resolveConflict(m.conflictWorktree, *selected, "manual")
+		}
+
+	case "c", "enter":
+		// Continue: rebase goes on with the next commit, a merge is concluded through the commit modal
+		m.modal = noModal
+		if m.conflictOperation == "rebase" {
+			m.debugLog(fmt.Sprintf("Continuing rebase of %s onto %s", m.conflictBranch, m.conflictOnto))
+			notifyCmd := m.showInfoNotification("Continuing rebase...")
+			return m, tea.Batch(
+				notifyCmd,
+				m.continueRebase(m.conflictWorktree, m.conflictBranch, m.conflictOnto, m.conflictHadUpstream, m.conflictLease, m.conflictStash),
+			)
+		}
+		m.debugLog(fmt.Sprintf("Concluding merge of %s in %s", m.conflictOnto, m.conflictWorktree))
+		return m, m.prepareMergeCommit(m.conflictWorktree)
+
+	case "a":
+		// Abort the whole merge/rebase
+		m.debugLog(fmt.Sprintf("Aborting %s in %s", m.conflictOperation, m.conflictWorktree))
+		m.modal = noModal
+		return m, m.abortConflict(m.conflictWorktree, m.conflictOperation, m.conflictStash)
 	}
 
 	return m, nil
 }
 
-func (m Model) handleEditorSelectModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	config := listSelectionConfig{
-		getCurrentIndex: func() int { return m.editorIndex },
-		getItemCount:    func(m Model) int { return len(m.editors) },
-		incrementIndex:  func(m *Model) { m.editorIndex++ },
-		decrementIndex:  func(m *Model) { m.editorIndex-- },
-		onConfirm: func(m Model) (tea.Model, tea.Cmd) {
-			if m.editorIndex >= 0 && m.editorIndex < len(m.editors) {
-				selectedEditor := m.editors[m.editorIndex]
-				if m.configManager != nil {
-					if err := m.configManager.SetEditor(m.repoPath, selectedEditor); err != nil {
-						m.showErrorNotification("Failed to save editor preference", 3*time.Second)
-					} else {
-						m.showSuccessNotification("Editor set to: " + selectedEditor, 3*time.Second)
-					}
-				}
-			}
-			m.modal = settingsModal
-			m.settingsIndex = 0
-			return m, nil
-		},
-		onCancel: func(m Model) (tea.Model, tea.Cmd) {
-			// Return to settings modal
-			m.modal = settingsModal
-			m.settingsIndex = 0
-			return m, nil
-		},
-	}
-	return m.handleListSelectionModalInput(msg, config)
-}
+func (m Model) handleDiffModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := m.diffPageSize()
+	maxScroll := max(len(m.diffLines())-page, 0)
 
-func (m Model) handleThemeSelectModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc", "q":
-		// Revert to original theme when cancelling
-		if m.originalTheme != "" {
-			ApplyTheme(m.originalTheme)
-		}
-		m.modal = settingsModal
-		m.settingsIndex = 1
+		m.modal = noModal
 		return m, nil
 
-	case "up":
-		if m.themeIndex > 0 {
-			m.themeIndex--
-			// Apply theme preview immediately
-			selectedTheme := m.availableThemes[m.themeIndex]
-			themeName := strings.ToLower(selectedTheme.Name)
-			if err := ApplyTheme(themeName); err == nil {
-				// Theme applied successfully, UI will refresh with new colors
-			}
+	case "tab", "left", "right", "h", "l":
+		// Switch between the file list and the hunks
+		m.diffFocusHunks = !m.diffFocusHunks
+
+	case "up", "k":
+		if m.diffFocusHunks {
+			m.diffScroll = max(m.diffScroll-1, 0)
+		} else if m.diffFileIndex > 0 {
+			m.diffFileIndex--
+			m.diffScroll = 0
 		}
-		return m, nil
 
-	case "down":
-		if m.themeIndex < len(m.availableThemes)-1 {
-			m.themeIndex++
-			// Apply theme preview immediately
-			selectedTheme := m.availableThemes[m.themeIndex]
-			themeName := strings.ToLower(selectedTheme.Name)
-			if err := ApplyTheme(themeName); err == nil {
-				// Theme applied successfully, UI will refresh with new colors
-			}
+	case "down", "j":
+		if m.diffFocusHunks {
+			m.diffScroll = min(m.diffScroll+1, maxScroll)
+		} else if m.diffFileIndex < len(m.diffFiles)-1 {
+			m.diffFileIndex++
+			m.diffScroll = 0
 		}
-		return m, nil
 
-	case "enter":
-		// Save the selected theme
+	case "pgup", "ctrl+u":
+		m.diffScroll = max(m.diffScroll-page, 0)
+
+	case "pgdown", "ctrl+d", " ":
+		m.diffScroll = min(m.diffScroll+page, maxScroll)
+
+	case "g", "home":
+		m.diffScroll = 0
+
+	case "G", "end":
+		m.diffScroll = maxScroll
+
+	case "n":
+		// Jump to the next hunk of the selected file
+		lines := m.diffLines()
+		for i := m.diffScroll + 1; i < len(lines); i++ {
+			if strings.HasPrefix(lines[i], "@@") {
+				m.diffScroll = min(i, maxScroll)
+				break
+			}
+		}
+
+	case "N":
+		// Jump to the previous hunk of the selected file
+		lines := m.diffLines()
+		for i := min(m.diffScroll, len(lines)) - 1; i >= 0; i-- {
+			if strings.HasPrefix(lines[i], "@@") {
+				m.diffScroll = i
+				break
+			}
+		}
+
+	case "b":
+		// Toggle between the uncommitted changes and the branch against the base branch
+		if !m.diffAgainstBase && m.baseBranch == "" {
+			return m, m.showWarningNotification("Base branch not set. Press 'b' in the main view to set base branch")
+		}
+		m.diffAgainstBase = !m.diffAgainstBase
+		m.diffFiles = nil
+		m.diffFileIndex = 0
+		m.diffScroll = 0
+		return m, m.loadDiff(m.diffWorktree, m.diffAgainstBase)
+
+	case "r":
+		// Reload, e.g. after Claude changed files
+		return m, m.loadDiff(m.diffWorktree, m.diffAgainstBase)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleHistoryModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Typing the new subject of the selected commit
+	if m.historyRewording {
+		switch msg.String() {
+		case "esc":
+			m.historyRewording = false
+			m.historyRewordInput.Blur()
+			return m, nil
+
+		case "enter":
+			subject := strings.TrimSpace(m.historyRewordInput.Value())
+			if subject == "" {
+				return m, m.showWarningNotification("Commit subject cannot be empty")
+			}
+			m.historyRewording = false
+			m.historyRewordInput.Blur()
+			if m.historyIndex < len(m.historyCommits) {
+				hash := m.historyCommits[m.historyIndex].Hash
+				return m, m.historyAction(m.historyWorktree, m.historyBranch, "reword", hash, subject)
+			}
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.historyRewordInput, cmd = m.historyRewordInput.Update(msg)
+		return m, cmd
+	}
+
+	// Scrolling the diff of the selected commit
+	if m.historyDiff != nil {
+		page := max(m.height-12, 5)
+		maxScroll := max(len(m.historyDiff)-page, 0)
+		switch msg.String() {
+		case "esc", "q", "enter":
+			m.historyDiff = nil
+		case "up", "k":
+			m.historyDiffScroll = max(m.historyDiffScroll-1, 0)
+		case "down", "j":
+			m.historyDiffScroll = min(m.historyDiffScroll+1, maxScroll)
+		case "pgup", "b":
+			m.historyDiffScroll = max(m.historyDiffScroll-page, 0)
+		case "pgdown", " ":
+			m.historyDiffScroll = min(m.historyDiffScroll+page, maxScroll)
+		case "home", "g":
+			m.historyDiffScroll = 0
+		case "end", "G":
+			m.historyDiffScroll = maxScroll
+		}
+		return m, nil
+	}
+
+	var selected *git.Commit
+	if m.historyIndex < len(m.historyCommits) {
+		selected = &m.historyCommits[m.historyIndex]
+	}
+
+	// Any key other than a second 'v' cancels a pending revert
+	if msg.String() != "v" {
+		m.historyRevertConfirm = false
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		return m, nil
+
+	case "up":
+		if m.historyIndex > 0 {
+			m.historyIndex--
+		}
+
+	case "down":
+		if m.historyIndex < len(m.historyCommits)-1 {
+			m.historyIndex++
+		}
+
+	case "enter", "d":
+		// Show the diff of the selected commit
+		if selected != nil {
+			return m, m.loadCommitDiff(m.historyWorktree, selected.Hash)
+		}
+
+	case "m":
+		// Amend the last commit with the uncommitted changes
+		if len(m.historyCommits) == 0 {
+			return m, m.showWarningNotification("No commits ahead of the base branch to amend")
+		}
+		hasUncommitted, err := m.gitManager.HasUncommittedChanges(m.historyWorktree)
+		if err != nil {
+			return m, m.showErrorNotification("Failed to check for uncommitted changes: "+err.Error(), 3*time.Second)
+		}
+		if !hasUncommitted {
+			return m, m.showInfoNotification("No uncommitted changes to add to the last commit - use 'r' to change its message")
+		}
+		return m, m.historyAction(m.historyWorktree, m.historyBranch, "amend", "", "")
+
+	case "r":
+		// Reword the selected commit
+		if selected != nil {
+			m.historyRewording = true
+			m.historyRewordInput.SetValue(selected.Subject)
+			m.historyRewordInput.CursorEnd()
+			m.historyRewordInput.Focus()
+		}
+
+	case "v":
+		// Revert the selected commit, after confirming with a second 'v'
+		if selected != nil {
+			if !m.historyRevertConfirm {
+				m.historyRevertConfirm = true
+				return m, nil
+			}
+			return m, m.historyAction(m.historyWorktree, m.historyBranch, "revert", selected.Hash, "")
+		}
+
+	case "s":
+		// Squash all commits into one, with a message edited in the commit modal
+		if len(m.historyCommits) < 2 {
+			return m, m.showInfoNotification("Nothing to squash - the branch has less than two commits ahead of the base branch")
+		}
+		subject, body := squashMessage(m.historyCommits)
+		m.modal = commitModal
+		m.modalFocused = 0
+		m.commitWorktreePath = m.historyWorktree
+		m.commitSquashOnto = m.baseBranch
+		m.commitSubjectInput.SetValue(subject)
+		m.commitBodyInput.SetValue(body)
+		m.focusCommitInput()
+		m.commitModalStatus = ""
+		return m, nil
+
+	case "F":
+		// Force-push the rewritten branch
+		if m.historyHasUpstream {
+			cmd := m.showInfoNotification("Force-pushing (with lease)...")
+			return m, tea.Batch(cmd, m.historyAction(m.historyWorktree, m.historyBranch, "force-push", "", ""))
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) handleStashModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		m.modal = noModal
+		return m, nil
+	}
+
+	// Typing the message of a new stash
+	if m.stashInputActive {
+		switch msg.String() {
+		case "esc":
+			m.stashInputActive = false
+			m.stashInput.Blur()
+			return m, nil
+
+		case "enter":
+			message := strings.TrimSpace(m.stashInput.Value())
+			m.stashInputActive = false
+			m.stashInput.Blur()
+			return m, m.stashAction(wt.Path, "push", "", message)
+		}
+
+		var cmd tea.Cmd
+		m.stashInput, cmd = m.stashInput.Update(msg)
+		return m, cmd
+	}
+
+	stashes := m.visibleStashes()
+	var selected *git.Stash
+	if m.stashIndex < len(stashes) {
+		selected = &stashes[m.stashIndex]
+	}
+
+	// Any key other than a second 'd' cancels a pending drop
+	if msg.String() != "d" {
+		m.stashDropConfirm = false
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		return m, nil
+
+	case "up":
+		if m.stashIndex > 0 {
+			m.stashIndex--
+		}
+		return m, nil
+
+	case "down":
+		if m.stashIndex < len(stashes)-1 {
+			m.stashIndex++
+		}
+		return m, nil
+
+	case "tab":
+		// Toggle between this branch's stashes and all stashes of the repository
+		m.stashShowAll = !m.stashShowAll
+		m.stashIndex = 0
+		return m, nil
+
+	case "n":
+		// Stash the worktree's changes
+		m.stashInputActive = true
+		m.stashInput.SetValue("")
+		m.stashInput.Focus()
+		return m, nil
+
+	case "p", "enter":
+		// Pop: apply and drop
+		if selected != nil {
+			return m, m.stashAction(wt.Path, "pop", selected.Ref, "")
+		}
+
+	case "a":
+		// Apply and keep the stash
+		if selected != nil {
+			return m, m.stashAction(wt.Path, "apply", selected.Ref, "")
+		}
+
+	case "d":
+		// Drop, after confirming with a second 'd'
+		if selected != nil {
+			if !m.stashDropConfirm {
+				m.stashDropConfirm = true
+				return m, nil
+			}
+			return m, m.stashAction(wt.Path, "drop", selected.Ref, "")
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) handlePRTypeModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		return m, nil
+
+	case "up":
+		if m.prTypeCursor > 0 {
+			m.prTypeCursor--
+		}
+		return m, nil
+
+	case "down":
+		if m.prTypeCursor < 1 {
+			m.prTypeCursor++
+		}
+		return m, nil
+
+	case "enter":
+		// User confirmed PR type selection
+		// Set isDraft based on selection (0=draft, 1=ready for review)
+		m.prIsDraft = (m.prTypeCursor == 0)
+
+		// Close modal and create PR with commit message as title
+		m.modal = noModal
+
+		// Use the last commit message as PR title
+		title := m.lastCommitMessage
+		if title == "" {
+			// Fallback if no commit message available (shouldn't happen in normal flow)
+			title = strings.ReplaceAll(m.prModalBranch, "-", " ")
+			title = strings.ReplaceAll(title, "_", " ")
+			title = strings.TrimSpace(title)
+			if len(title) > 0 {
+				title = strings.ToUpper(title[:1]) + title[1:]
+			}
+		}
+
+		// Create PR with commit message as title and empty description
+		cmd := m.showInfoNotification("Creating draft PR...")
+		return m, tea.Batch(cmd, m.createOrUpdatePR(m.prModalWorktreePath, m.prModalBranch, title, ""))
+	}
+
+	return m, nil
+}
+
+func (m Model) handleScriptPickerModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	config := listSelectionConfig{
+		getCurrentIndex: func() int { return m.scriptIndex },
+		getItemCount:    func(m Model) int { return len(m.scriptConfig.GetScriptNames()) },
+		incrementIndex:  func(m *Model) { m.scriptIndex++ },
+		decrementIndex:  func(m *Model) { m.scriptIndex-- },
+		onConfirm: func(m Model) (tea.Model, tea.Cmd) {
+			wt := m.selectedWorktree()
+			names := m.scriptConfig.GetScriptNames()
+			if wt == nil || m.scriptIndex < 0 || m.scriptIndex >= len(names) {
+				return m, nil
+			}
+			// Run (or restart) the selected script
+			m.modal = noModal
+			script := names[m.scriptIndex]
+			message := fmt.Sprintf("Starting '%s'...", script)
+			if spec, _ := m.scriptConfig.LookupScript(script); !spec.IsInteractive() {
+				message = fmt.Sprintf("Running '%s' in the background...", script)
+			}
+			cmd := m.showInfoNotification(message)
+			return m, tea.Batch(cmd, m.runScript(*wt, script))
+		},
+		onCustomKey: func(m Model, key string) (tea.Model, tea.Cmd) {
+			wt := m.selectedWorktree()
+			names := m.scriptConfig.GetScriptNames()
+			if wt == nil || m.scriptIndex < 0 || m.scriptIndex >= len(names) {
+				return m, nil
+			}
+			script := names[m.scriptIndex]
+			if spec, _ := m.scriptConfig.LookupScript(script); !spec.IsInteractive() && (key == "d" || key == "a") {
+				return m, m.showWarningNotification(fmt.Sprintf("'%s' runs in the background and has no tmux window", script))
+			}
+
+			switch key {
+			case "d":
+				// Stop the script and close its window
+				if m.scriptWindow(*wt, script) == nil {
+					return m, m.showWarningNotification(fmt.Sprintf("'%s' is not running", script))
+				}
+				return m, m.stopScript(*wt, script)
+
+			case "a":
+				// Attach to the script window (handled by the shell wrapper like 't')
+				if m.scriptWindow(*wt, script) == nil {
+					return m, m.showWarningNotification(fmt.Sprintf("'%s' has not been started yet", script))
+				}
+				m.modal = noModal
+				m.pendingSwitchInfo = &SwitchInfo{
+					Path:         wt.Path,
+					Branch:       wt.Branch,
+					SessionName:  wt.ClaudeSessionName,
+					AutoClaude:   false,
+					TargetWindow: m.sessionManager.ScriptWindowName(script),
+				}
+				m.ensuringWorktree = true
+				cmd := m.showInfoNotification("Preparing workspace...")
+				return m, tea.Batch(cmd, m.ensureWorktreeExists(wt.Path, wt.Branch))
+			}
+			return m, nil
+		},
+	}
+	return m.handleListSelectionModalInput(msg, config)
+}
+
+func (m Model) handleEditorSelectModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	config := listSelectionConfig{
+		getCurrentIndex: func() int { return m.editorIndex },
+		getItemCount:    func(m Model) int { return len(m.editors) },
+		incrementIndex:  func(m *Model) { m.editorIndex++ },
+		decrementIndex:  func(m *Model) { m.editorIndex-- },
+		onConfirm: func(m Model) (tea.Model, tea.Cmd) {
+			if m.editorIndex >= 0 && m.editorIndex < len(m.editors) {
+				selectedEditor := m.editors[m.editorIndex]
+				if m.configManager != nil {
+					if err := m.configManager.SetEditor(m.repoPath, selectedEditor); err != nil {
+						m.showErrorNotification("Failed to save editor preference", 3*time.Second)
+					} else {
+						m.showSuccessNotification("Editor set to: " + selectedEditor, 3*time.Second)
+					}
+				}
+			}
+			m.modal = settingsModal
+			m.settingsIndex = 0
+			return m, nil
+		},
+		onCancel: func(m Model) (tea.Model, tea.Cmd) {
+			// Return to settings modal
+			m.modal = settingsModal
+			m.settingsIndex = 0
+			return m, nil
+		},
+	}
+	return m.handleListSelectionModalInput(msg, config)
+}
+
+func (m Model) handleThemeSelectModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		// Revert to original theme when cancelling
+		if m.originalTheme != "" {
+			ApplyTheme(m.originalTheme)
+		}
+		m.modal = settingsModal
+		m.settingsIndex = 1
+		return m, nil
+
+	case "up":
+		if m.themeIndex > 0 {
+			m.themeIndex--
+			// Apply theme preview immediately
+			selectedTheme := m.availableThemes[m.themeIndex]
+			themeName := strings.ToLower(selectedTheme.Name)
+			if err := ApplyTheme(themeName); err == nil {
+				// Theme applied successfully, UI will refresh with new colors
+			}
+		}
+		return m, nil
+
+	case "down":
+		if m.themeIndex < len(m.availableThemes)-1 {
+			m.themeIndex++
+			// Apply theme preview immediately
+			selectedTheme := m.availableThemes[m.themeIndex]
+			themeName := strings.ToLower(selectedTheme.Name)
+			if err := ApplyTheme(themeName); err == nil {
+				// Theme applied successfully, UI will refresh with new colors
+			}
+		}
+		return m, nil
+
+	case "enter":
+		// Save the selected theme
 		if m.themeIndex >= 0 && m.themeIndex < len(m.availableThemes) {
 			selectedTheme := m.availableThemes[m.themeIndex]
 			themeName := strings.ToLower(selectedTheme.Name)
@@ -3159,7 +4437,7 @@ func (m Model) handleSettingsModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "down":
-		if m.settingsIndex < 6 { // Now 7 settings (editor, theme, base branch, tmux config, AI integration, debug logs, PR default state)
+		if m.settingsIndex < 13 { // Now 14 settings (editor, theme, base branch, tmux config, AI integration, debug logs, PR default state, update mode, auto-stash, sign-off, GPG signing, worktree location, submodules & LFS, remotes)
 			m.settingsIndex++
 		}
 
@@ -3205,6 +4483,48 @@ func (m Model) handleSettingsModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		msg = tea.KeyMsg{Type: tea.KeyEnter}
 		return m.handleSettingsModalInput(msg)
 
+	case "u":
+		// Quick key for Update Mode
+		m.settingsIndex = 7
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "s":
+		// Quick key for Auto-stash
+		m.settingsIndex = 8
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "o":
+		// Quick key for Sign-off
+		m.settingsIndex = 9
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "g":
+		// Quick key for GPG Signing
+		m.settingsIndex = 10
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "w":
+		// Quick key for Worktree Location
+		m.settingsIndex = 11
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "m":
+		// Quick key for Submodules & LFS
+		m.settingsIndex = 12
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "r":
+		// Quick key for Remotes
+		m.settingsIndex = 13
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
 	case "enter":
 		// Open the selected setting's modal
 		switch m.settingsIndex {
@@ -3307,12 +4627,237 @@ func (m Model) handleSettingsModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+
+		case 7:
+			// Update Mode setting - toggle between merge and rebase
+			if m.configManager != nil {
+				mode := "rebase"
+				if m.configManager.GetUpdateMode(m.repoPath) == "rebase" {
+					mode = "merge"
+				}
+				if err := m.configManager.SetUpdateMode(m.repoPath, mode); err != nil {
+					cmd := m.showErrorNotification("Failed to save update mode: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				if mode == "rebase" {
+					return m, m.showSuccessNotification("'u' now rebases onto the base branch", 2*time.Second)
+				}
+				return m, m.showSuccessNotification("'u' now merges the base branch", 2*time.Second)
+			}
+			return m, nil
+
+		case 8:
+			// Auto-stash setting - toggle stashing uncommitted changes around 'u'
+			if m.configManager != nil {
+				enabled := !m.configManager.GetAutoStash(m.repoPath)
+				if err := m.configManager.SetAutoStash(m.repoPath, enabled); err != nil {
+					cmd := m.showErrorNotification("Failed to save auto-stash setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				if enabled {
+					return m, m.showSuccessNotification("'u' now stashes uncommitted changes and re-applies them", 2*time.Second)
+				}
+				return m, m.showSuccessNotification("'u' no longer stashes uncommitted changes", 2*time.Second)
+			}
+			return m, nil
+
+		case 9:
+			// Sign-off setting - toggle adding a Signed-off-by trailer to commits
+			if m.configManager != nil {
+				enabled := !m.configManager.GetCommitSignoff(m.repoPath)
+				if err := m.configManager.SetCommitSignoff(m.repoPath, enabled); err != nil {
+					cmd := m.showErrorNotification("Failed to save sign-off setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				if enabled {
+					return m, m.showSuccessNotification("Commits are now signed off (--signoff)", 2*time.Second)
+				}
+				return m, m.showSuccessNotification("Commits are no longer signed off", 2*time.Second)
+			}
+			return m, nil
+
+		case 10:
+			// GPG Signing setting - cycle through git's default (commit.gpgsign), always and never
+			if m.configManager != nil {
+				var mode, message string
+				switch m.configManager.GetCommitGPGSign(m.repoPath) {
+				case "":
+					mode, message = "always", "Commits are now GPG-signed (-S)"
+				case "always":
+					mode, message = "never", "Commits are no longer GPG-signed (--no-gpg-sign)"
+				default:
+					mode, message = "", "GPG signing now follows git's commit.gpgsign"
+				}
+				if err := m.configManager.SetCommitGPGSign(m.repoPath, mode); err != nil {
+					cmd := m.showErrorNotification("Failed to save GPG signing setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				return m, m.showSuccessNotification(message, 2*time.Second)
+			}
+			return m, nil
+
+		case 11:
+			// Worktree Location setting - edit the root directory and path template of new worktrees
+			root, template := m.worktreeLocation()
+			m.modal = worktreeLocationModal
+			m.modalFocused = 0
+			m.worktreeRootInput.SetValue(root)
+			m.worktreeRootInput.Focus()
+			m.worktreeRootInput.CursorEnd()
+			m.worktreeTemplateInput.SetValue(template)
+			m.worktreeTemplateInput.Blur()
+			return m, nil
+
+		case 12:
+			// Submodules & LFS setting - toggle setting up submodules and LFS files on creation
+			if m.configManager != nil {
+				enabled := !m.configManager.GetInitSubmodules(m.repoPath)
+				if err := m.configManager.SetInitSubmodules(m.repoPath, enabled); err != nil {
+					cmd := m.showErrorNotification("Failed to save submodules & LFS setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				if enabled {
+					return m, m.showSuccessNotification("New worktrees now initialize submodules and pull LFS files", 2*time.Second)
+				}
+				return m, m.showSuccessNotification("New worktrees no longer initialize submodules or pull LFS files", 2*time.Second)
+			}
+			return m, nil
+
+		case 13:
+			// Remotes setting - edit the push remote and the base remote
+			push, base := "", ""
+			if m.configManager != nil {
+				push, base = m.configManager.GetRemotes(m.repoPath)
+			}
+			m.remoteNames, _ = m.gitManager.ListRemotes()
+			m.modal = remotesModal
+			m.modalFocused = 0
+			m.pushRemoteInput.SetValue(push)
+			m.pushRemoteInput.Focus()
+			m.pushRemoteInput.CursorEnd()
+			m.baseRemoteInput.SetValue(base)
+			m.baseRemoteInput.Blur()
+			return m, nil
 		}
 	}
 
 	return m, nil
 }
 
+func (m Model) handleWorktreeLocationModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		// Close without saving and return to settings
+		m.modal = settingsModal
+		m.settingsIndex = 11
+		m.worktreeRootInput.Blur()
+		m.worktreeTemplateInput.Blur()
+		return m, nil
+
+	case "tab", "shift+tab", "up", "down":
+		// Switch between the root and template inputs
+		m.modalFocused = 1 - m.modalFocused
+		if m.modalFocused == 0 {
+			m.worktreeRootInput.Focus()
+			m.worktreeTemplateInput.Blur()
+		} else {
+			m.worktreeRootInput.Blur()
+			m.worktreeTemplateInput.Focus()
+		}
+		return m, nil
+
+	case "enter":
+		root := strings.TrimSpace(m.worktreeRootInput.Value())
+		template := strings.TrimSpace(m.worktreeTemplateInput.Value())
+		if err := git.ValidateWorktreeTemplate(template); err != nil {
+			return m, m.showWarningNotification(err.Error())
+		}
+		if m.configManager != nil {
+			if err := m.configManager.SetWorktreeLocation(m.repoPath, root, template); err != nil {
+				return m, m.showErrorNotification("Failed to save worktree location: "+err.Error(), 3*time.Second)
+			}
+		}
+
+		m.modal = settingsModal
+		m.settingsIndex = 11
+		m.worktreeRootInput.Blur()
+		m.worktreeTemplateInput.Blur()
+		return m, m.showSuccessNotification("New worktrees are created in "+m.previewWorktreePath(root, template), 3*time.Second)
+	}
+
+	var cmd tea.Cmd
+	if m.modalFocused == 0 {
+		m.worktreeRootInput, cmd = m.worktreeRootInput.Update(msg)
+	} else {
+		m.worktreeTemplateInput, cmd = m.worktreeTemplateInput.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) handleRemotesModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		// Close without saving and return to settings
+		m.modal = settingsModal
+		m.settingsIndex = 13
+		m.pushRemoteInput.Blur()
+		m.baseRemoteInput.Blur()
+		return m, nil
+
+	case "tab", "shift+tab", "up", "down":
+		// Switch between the push and base remote inputs
+		m.modalFocused = 1 - m.modalFocused
+		if m.modalFocused == 0 {
+			m.pushRemoteInput.Focus()
+			m.baseRemoteInput.Blur()
+		} else {
+			m.pushRemoteInput.Blur()
+			m.baseRemoteInput.Focus()
+		}
+		return m, nil
+
+	case "enter":
+		push := strings.TrimSpace(m.pushRemoteInput.Value())
+		base := strings.TrimSpace(m.baseRemoteInput.Value())
+		if err := m.checkRemotes(push, base); err != nil {
+			return m, m.showWarningNotification(err.Error())
+		}
+		if m.configManager != nil {
+			if err := m.configManager.SetRemotes(m.repoPath, push, base); err != nil {
+				return m, m.showErrorNotification("Failed to save remotes: "+err.Error(), 3*time.Second)
+			}
+		}
+
+		m.modal = settingsModal
+		m.settingsIndex = 13
+		m.pushRemoteInput.Blur()
+		m.baseRemoteInput.Blur()
+		push, base = m.remotes()
+		return m, m.showSuccessNotification(fmt.Sprintf("Branches are pushed to '%s', PRs target '%s'", push, base), 3*time.Second)
+	}
+
+	var cmd tea.Cmd
+	if m.modalFocused == 0 {
+		m.pushRemoteInput, cmd = m.pushRemoteInput.Update(msg)
+	} else {
+		m.baseRemoteInput, cmd = m.baseRemoteInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// checkRemotes checks that the remotes entered in the remotes modal exist ("" is origin)
+func (m Model) checkRemotes(remotes ...string) error {
+	if m.remoteNames == nil {
+		return nil // Couldn't list them, git reports a missing remote when it's used
+	}
+	for _, remote := range remotes {
+		if remote != "" && !slices.Contains(m.remoteNames, remote) {
+			return fmt.Errorf("no remote named '%s', expected one of %s", remote, strings.Join(m.remoteNames, ", "))
+		}
+	}
+	return nil
+}
+
 func (m Model) handlePRStateSettingsModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
@@ -3667,6 +5212,65 @@ func (m Model) handleTmuxConfigModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// detachedWarning explains why a branch action is not available in a detached worktree
+func detachedWarning(action string) string {
+	return fmt.Sprintf("Cannot %s, HEAD is detached. Press 'B' to create a branch here first", action)
+}
+
+// hookErrorMessage appends the hook output to a notification message when err came from a jean.json hook
+func hookErrorMessage(message string, err error) string {
+	var hookErr *git.HookError
+	if errors.As(err, &hookErr) {
+		return fmt.Sprintf("%s:\n%s", message, hookErr.Error())
+	}
+	return message
+}
+
+// scriptConfigWarning describes the jean.json entries that were ignored because they are invalid
+func scriptConfigWarning(err *config.ValidationError) string {
+	return "Ignoring invalid jean.json entries:\n  - " + strings.Join(err.Problems, "\n  - ")
+}
+
+// withHookOutput appends the output of the hooks that ran (see runHook) to a notification message
+func withHookOutput(message string, hookOutputs ...string) string {
+	for _, output := range hookOutputs {
+		if output != "" {
+			message += "\n\n" + output
+		}
+	}
+	return message
+}
+
+// worktreeCreatedWarning returns the warning to show when a worktree was created but its
+// setup script or post_create hook failed, or some jean.json copy/symlink entries were skipped
+func worktreeCreatedWarning(err error) (string, bool) {
+	var setupErr *git.SetupError
+	var conflictErr *git.CopyConflictError
+	var submoduleErr *git.SubmoduleError
+	if errors.As(err, &setupErr) || errors.As(err, &conflictErr) || errors.As(err, &submoduleErr) {
+		return fmt.Sprintf("Worktree created but %s", err.Error()), true
+	}
+	return "", false
+}
+
+// worktreeCreatedNotification confirms a worktree creation, pointing out the submodules and
+// LFS files that were not set up (see submoduleHint)
+func (m *Model) worktreeCreatedNotification(hint string) tea.Cmd {
+	if hint != "" {
+		duration := 6 * time.Second
+		return m.showNotification(fmt.Sprintf("Worktree created, %s (or enable 'Submodules & LFS' in the settings)", hint), NotificationInfo, &duration)
+	}
+	return m.showSuccessNotification("Worktree created successfully", 3*time.Second)
+}
+
+// stashErrorSuffix explains a failed re-apply of the auto-stash, "" if there was no error
+func stashErrorSuffix(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf(", but re-applying your stashed changes failed (they are kept, press 'z'): %s", err.Error())
+}
+
 // buildRefreshStatusMessage constructs a detailed status message based on refresh results
 func buildRefreshStatusMessage(msg refreshWithPullMsg) string {
 	// If everything was already up to date
@@ -3765,3 +5369,178 @@ func (m Model) handleHelperModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	return m, nil
 }
+
+func (m Model) handleGCModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Nothing to do until the list is loaded or while removing
+	if m.gcWorktrees == nil || m.gcRemoving {
+		if msg.String() == "esc" && !m.gcRemoving {
+			m.modal = noModal
+		}
+		return m, nil
+	}
+
+	// Locked worktrees were selected, they are only removed once the force is confirmed
+	if m.gcConfirmForce {
+		switch msg.String() {
+		case "y":
+			m.gcConfirmForce = false
+			return m.removeSelectedStaleWorktrees(true)
+		case "n", "esc":
+			m.gcConfirmForce = false
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.modal = noModal
+		return m, nil
+
+	case "up", "k":
+		if m.gcIndex > 0 {
+			m.gcIndex--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.gcIndex < m.gcRowCount()-1 {
+			m.gcIndex++
+		}
+		return m, nil
+
+	case " ":
+		if m.gcIndex < len(m.gcSelected) {
+			m.gcSelected[m.gcIndex] = !m.gcSelected[m.gcIndex]
+		} else {
+			m.gcOrphansSelected = !m.gcOrphansSelected
+		}
+		return m, nil
+
+	case "a":
+		// Select all, or none if all are selected already
+		all := m.gcOrphansSelected || !m.hasGCOrphans()
+		for _, selected := range m.gcSelected {
+			all = all && selected
+		}
+		for i := range m.gcSelected {
+			m.gcSelected[i] = !all
+		}
+		m.gcOrphansSelected = !all
+		return m, nil
+
+	case "enter":
+		if m.gcLockedSelected() > 0 {
+			m.gcConfirmForce = true
+			return m, nil
+		}
+		return m.removeSelectedStaleWorktrees(false)
+	}
+
+	return m, nil
+}
+
+// removeSelectedStaleWorktrees starts removing the worktrees (and orphans) selected in the
+// cleanup modal, forcing the removal of locked ones with forceLocked
+func (m Model) removeSelectedStaleWorktrees(forceLocked bool) (tea.Model, tea.Cmd) {
+	var worktrees []git.StaleWorktree
+	for i, wt := range m.gcWorktrees {
+		if m.gcSelected[i] {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	var orphanBranches, orphanSessions []string
+	if m.gcOrphansSelected {
+		orphanBranches, orphanSessions = m.gcOrphanBranches, m.gcOrphanSessions
+	}
+	if len(worktrees) == 0 && len(orphanBranches) == 0 && len(orphanSessions) == 0 {
+		cmd := m.showInfoNotification("Nothing selected")
+		return m, cmd
+	}
+	m.gcRemoving = true
+	return m, m.removeStaleWorktrees(worktrees, orphanBranches, orphanSessions, forceLocked)
+}
+
+func (m Model) handleSparseWidenModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		m.modal = noModal
+		return m, nil
+	}
+	options := m.sparseWidenOptions(wt)
+
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		return m, nil
+
+	case "up":
+		if m.sparseWidenIndex > 0 {
+			m.sparseWidenIndex--
+		}
+		return m, nil
+
+	case "down":
+		if m.sparseWidenIndex < len(options)-1 {
+			m.sparseWidenIndex++
+		}
+		return m, nil
+
+	case "enter":
+		m.modal = noModal
+		profile := options[min(m.sparseWidenIndex, len(options)-1)]
+		cmd := m.showInfoNotification("Checking out more of the repository...")
+		return m, tea.Batch(cmd, m.widenSparseCheckout(wt.Path, wt.Name(), profile))
+	}
+
+	return m, nil
+}
+
+func (m Model) handleAdoptModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		return m, nil
+
+	case "up":
+		if m.adoptIndex > 0 {
+			m.adoptIndex--
+		}
+		return m, nil
+
+	case "down":
+		if m.adoptIndex < 1 {
+			m.adoptIndex++
+		}
+		return m, nil
+
+	case "enter":
+		m.modal = noModal
+		if wt := m.selectedWorktree(); wt != nil {
+			return m, m.adoptWorktree(wt.Path, wt.Name(), m.adoptIndex == 1)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m Model) handleLockModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		m.lockReasonInput.Blur()
+		return m, nil
+
+	case "enter":
+		m.modal = noModal
+		m.lockReasonInput.Blur()
+		if wt := m.selectedWorktree(); wt != nil {
+			return m, m.setWorktreeLock(wt.Path, wt.Branch, true, strings.TrimSpace(m.lockReasonInput.Value()))
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.lockReasonInput, cmd = m.lockReasonInput.Update(msg)
+	return m, cmd
+}
@@ -1,10 +1,15 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/coollabsio/jean/config"
+	"github.com/coollabsio/jean/git"
 	"github.com/coollabsio/jean/session"
 )
 
@@ -380,6 +385,227 @@ func TestListSelectionModalInput_BoundaryCheck_Down(t *testing.T) {
 	}
 }
 
+// TestPushCompleted_ShowsHookOutput tests that the output of a successful pre_push hook is shown
+func TestPushCompleted_ShowsHookOutput(t *testing.T) {
+	m := setupTestModel()
+
+	resultModel, _ := m.Update(pushCompletedMsg{branch: "feature", hookOutput: "pre_push hook:\nlint ok"})
+
+	notification := resultModel.(Model).notification
+	if notification == nil || notification.Type != NotificationSuccess {
+		t.Fatalf("Expected a success notification, got %+v", notification)
+	}
+	if want := "Pushed to origin/feature\n\npre_push hook:\nlint ok"; notification.Message != want {
+		t.Errorf("Expected notification %q, got %q", want, notification.Message)
+	}
+}
+
+// TestWorktreeStatusUpdated_AppliesByPath tests that streamed status results update the matching worktree
+func TestWorktreeStatusUpdated_AppliesByPath(t *testing.T) {
+	m := setupTestModel()
+	m.worktrees = []git.Worktree{{Path: "/repo", Branch: "main"}, {Path: "/repo/.workspaces/a", Branch: "a"}}
+	m.statusGeneration = 2
+
+	resultModel, _ := m.Update(worktreeStatusUpdatedMsg{
+		generation: 2,
+		status:     git.WorktreeStatus{Path: "/repo/.workspaces/a", HasUncommitted: true, AheadCount: 3, BehindCount: 1},
+	})
+
+	wt := resultModel.(Model).worktrees[1]
+	if !wt.HasUncommitted || wt.AheadCount != 3 || wt.BehindCount != 1 || !wt.IsOutdated {
+		t.Errorf("Expected status to be applied to worktree 'a', got %+v", wt)
+	}
+	if resultModel.(Model).worktrees[0].HasUncommitted {
+		t.Errorf("Expected worktree 'main' to be untouched")
+	}
+}
+
+// TestWorktreeStatusUpdated_DropsStaleGeneration tests that results of a superseded load are ignored
+func TestWorktreeStatusUpdated_DropsStaleGeneration(t *testing.T) {
+	m := setupTestModel()
+	m.worktrees = []git.Worktree{{Path: "/repo/.workspaces/a", Branch: "a"}}
+	m.statusGeneration = 2
+
+	resultModel, cmd := m.Update(worktreeStatusUpdatedMsg{
+		generation: 1,
+		status:     git.WorktreeStatus{Path: "/repo/.workspaces/a", HasUncommitted: true},
+	})
+
+	if resultModel.(Model).worktrees[0].HasUncommitted {
+		t.Errorf("Expected stale status to be dropped")
+	}
+	if cmd != nil {
+		t.Errorf("Expected no further reads from a stale load")
+	}
+}
+
+// TestWorktreeStatusUpdated_ReleasesFinishedLoad tests that the context of a load is cancelled
+// once its last result arrived, but not by a superseded load
+func TestWorktreeStatusUpdated_ReleasesFinishedLoad(t *testing.T) {
+	m := setupTestModel()
+	m.statusGeneration = 2
+	cancelled := false
+	m.statusCancel = func() { cancelled = true }
+
+	resultModel, _ := m.Update(worktreeStatusUpdatedMsg{generation: 1, done: true})
+	if cancelled || resultModel.(Model).statusCancel == nil {
+		t.Fatal("Expected a superseded load to leave the current one running")
+	}
+
+	resultModel, cmd := resultModel.(Model).Update(worktreeStatusUpdatedMsg{generation: 2, done: true})
+	if !cancelled || resultModel.(Model).statusCancel != nil {
+		t.Errorf("Expected the finished load to be cancelled and released")
+	}
+	if cmd != nil {
+		t.Errorf("Expected no further reads from a finished load")
+	}
+}
+
+// TestStageModal_PartialSelection tests that deselecting a hunk stages the rest of its file only
+func TestStageModal_PartialSelection(t *testing.T) {
+	m := setupTestModel()
+	m.modal = stageModal
+	m.setStageFiles("/repo/.workspaces/a", []git.ChangedFile{
+		{Path: "main.go", Status: "M", Hunks: []git.Hunk{{Header: "@@ -1 +1 @@"}, {Header: "@@ -9 +9 @@"}}},
+		{Path: "debug.log", Status: "?"},
+	})
+
+	// Expand main.go, deselect its first hunk and the untracked file
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRight},
+		{Type: tea.KeyDown},
+		{Type: tea.KeySpace},
+		{Type: tea.KeyDown},
+		{Type: tea.KeyDown},
+		{Type: tea.KeySpace},
+	}
+	var model tea.Model = m
+	for _, key := range keys {
+		model, _ = model.(Model).handleStageModalInput(key)
+	}
+
+	selections := model.(Model).stageSelections()
+	if len(selections) != 1 || selections[0].File.Path != "main.go" {
+		t.Fatalf("Expected only main.go to be selected, got %+v", selections)
+	}
+	if len(selections[0].Hunks) != 1 || selections[0].Hunks[0] != 1 {
+		t.Errorf("Expected only the second hunk of main.go, got %v", selections[0].Hunks)
+	}
+	if state := model.(Model).stageFileState(0); state != "some" {
+		t.Errorf("Expected main.go to be partially selected, got %q", state)
+	}
+}
+
+// TestCommitCreated_CompletesLocalMergeAfterConflicts tests that committing the resolved
+// conflicts of a local merge runs its post_merge hook and offers the cleanup
+func TestCommitCreated_CompletesLocalMergeAfterConflicts(t *testing.T) {
+	repoRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoRoot, "jean.json"), []byte(`{"hooks": {"post_merge": "echo cleaned"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := git.NewFakeRunner()
+	runner.On(repoRoot+"\n", "rev-parse", "--show-toplevel")
+
+	m := setupTestModel()
+	m.gitManager = git.NewManagerWithRunner(repoRoot, runner)
+	m.localMergeBranch = "feature"
+	m.localMergeWorktree = filepath.Join(repoRoot, ".workspaces", "feature")
+	m.openConflictModal("merge", repoRoot, "main", "feature", nil, false)
+	m.conflictLocalMerge = true
+
+	// A commit elsewhere doesn't conclude the merge
+	resultModel, _ := m.Update(commitCreatedMsg{commitHash: "abc", worktreePath: m.localMergeWorktree})
+	if !resultModel.(Model).conflictLocalMerge {
+		t.Fatal("Expected the local merge to be still pending")
+	}
+
+	resultModel, _ = m.Update(commitCreatedMsg{commitHash: "abc", worktreePath: repoRoot})
+	if resultModel.(Model).conflictLocalMerge {
+		t.Error("Expected the local merge to be completed")
+	}
+
+	msg := m.completeLocalMerge(repoRoot, m.localMergeWorktree, "feature")().(localMergeCompletedMsg)
+	if msg.err != nil || msg.hookErr != nil || msg.hookOutput != "post_merge hook:\ncleaned" {
+		t.Fatalf("Expected the post_merge hook to run, got %+v", msg)
+	}
+	resultModel, _ = resultModel.(Model).Update(msg)
+	if resultModel.(Model).modal != postMergeCleanupModal {
+		t.Errorf("Expected the post-merge cleanup modal, got %v", resultModel.(Model).modal)
+	}
+}
+
+// TestMergeReadyToCommit_CommitsStagedOnly tests that a merge with resolved conflicts is
+// concluded with the staged resolutions only, leaving other changes out of the merge commit
+func TestMergeReadyToCommit_CommitsStagedOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("config.NewManager: %v", err)
+	}
+	runner := git.NewFakeRunner()
+	m := setupTestModel()
+	m.gitManager = git.NewManagerWithRunner("/repo", runner)
+	m.configManager = configManager
+	m.commitSubjectInput = textinput.New()
+	m.commitBodyInput = textarea.New()
+
+	resultModel, _ := m.Update(mergeReadyToCommitMsg{worktreePath: "/repo", subject: "Merge branch 'feature'"})
+	model := resultModel.(Model)
+	if model.modal != commitModal || !model.commitStagedOnly {
+		t.Fatalf("Expected the commit modal for the staged changes only, got modal %v (staged only %v)", model.modal, model.commitStagedOnly)
+	}
+
+	msg := model.createCommit(model.commitWorktreePath, model.commitSubjectInput.Value(), "", nil, model.commitStagedOnly)().(commitCreatedMsg)
+	if msg.err != nil {
+		t.Fatalf("Expected the merge commit to be created, got %v", msg.err)
+	}
+	if runner.Ran("add", "-A") {
+		t.Errorf("Expected the merge to be concluded without staging everything, got calls %v", runner.Calls())
+	}
+}
+
+// TestGCModal_LockedNeedsForceConfirmation tests that selected locked worktrees are only
+// removed after a separate force confirmation
+func TestGCModal_LockedNeedsForceConfirmation(t *testing.T) {
+	m := setupTestModel()
+	m.modal = gcModal
+	m.gcWorktrees = []git.StaleWorktree{
+		{Worktree: git.Worktree{Path: "/repo/.workspaces/kept", Branch: "kept", Locked: true}},
+		{Worktree: git.Worktree{Path: "/repo/.workspaces/old", Branch: "old"}},
+	}
+	m.gcSelected = []bool{true, true}
+
+	resultModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if !m.gcConfirmForce || m.gcRemoving || cmd != nil {
+		t.Fatalf("Expected a force confirmation before removing, got confirm=%v removing=%v", m.gcConfirmForce, m.gcRemoving)
+	}
+
+	resultModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = resultModel.(Model)
+	if m.gcConfirmForce || m.gcRemoving || m.modal != gcModal {
+		t.Fatalf("Expected 'n' to go back to the list, got confirm=%v removing=%v", m.gcConfirmForce, m.gcRemoving)
+	}
+
+	resultModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	resultModel, cmd = resultModel.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m = resultModel.(Model); !m.gcRemoving || cmd == nil {
+		t.Errorf("Expected 'y' to start the removal")
+	}
+}
+
+// TestRemoveStaleWorktrees_SkipsLockedWithoutForce tests that a locked worktree is never
+// removed just because it was passed in
+func TestRemoveStaleWorktrees_SkipsLockedWithoutForce(t *testing.T) {
+	m := setupTestModel()
+	locked := git.StaleWorktree{Worktree: git.Worktree{Path: "/repo/.workspaces/kept", Branch: "kept", Locked: true}}
+
+	msg := m.removeStaleWorktrees([]git.StaleWorktree{locked}, nil, nil, false)().(staleWorktreesRemovedMsg)
+	if msg.removed != 0 || len(msg.failed) != 1 || msg.failed[0] != "kept: locked" {
+		t.Errorf("Expected the locked worktree to be skipped, got %+v", msg)
+	}
+}
+
 // Helper function to set up a basic test model
 func setupTestModel() Model {
 	return Model{
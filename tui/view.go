@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/coollabsio/jean/config"
+	"github.com/coollabsio/jean/git"
 	"github.com/coollabsio/jean/internal/version"
 )
 
@@ -110,7 +111,7 @@ func (m Model) renderWorktreeList() string {
 		}
 
 		// Show branch name and shortened path
-		branch := wt.Branch
+		branch := wt.DisplayName()
 		if branch == "" {
 			branch = "(no branch)"
 		}
@@ -129,6 +130,15 @@ func (m Model) renderWorktreeList() string {
 				line += normalItemStyle.Copy().Foreground(warningColor).Render(uncommittedIndicator)
 			}
 
+			// Directory was deleted outside jean
+			if wt.Prunable {
+				line += normalItemStyle.Copy().Foreground(errorColor).Render(" ✗ deleted")
+			}
+
+			if wt.Locked {
+				line += normalItemStyle.Copy().Foreground(accentColor).Render(" 🔒")
+			}
+
 			// Show behind count if outdated
 			if wt.IsOutdated && wt.BehindCount > 0 {
 				behindIndicator := fmt.Sprintf(" ↓%d", wt.BehindCount)
@@ -136,6 +146,17 @@ func (m Model) renderWorktreeList() string {
 			}
 		}
 
+		// Show running jean.json scripts
+		running := 0
+		for _, window := range m.scriptWindows[wt.ClaudeSessionName] {
+			if window.Running {
+				running++
+			}
+		}
+		if running > 0 {
+			line += normalItemStyle.Copy().Foreground(successColor).Render(fmt.Sprintf(" ▶%d", running))
+		}
+
 
 		b.WriteString(style.Render(line))
 		b.WriteString("\n")
@@ -158,7 +179,7 @@ func (m Model) renderDetails() string {
 
 	// Render details in a nice format
 	b.WriteString(detailKeyStyle.Render("Branch: "))
-	b.WriteString(detailValueStyle.Render(wt.Branch))
+	b.WriteString(detailValueStyle.Render(wt.DisplayName()))
 	b.WriteString("\n")
 
 	// Show base branch right after branch
@@ -167,7 +188,7 @@ func (m Model) renderDetails() string {
 		b.WriteString(detailValueStyle.Render(m.baseBranch))
 
 		// Show status on the same line if branch differs from base branch
-		if wt.Branch != m.baseBranch && !strings.HasPrefix(wt.Branch, "(detached") {
+		if wt.Branch != m.baseBranch && !wt.Detached {
 			b.WriteString("  ")
 			// Show ahead/behind counts
 			if wt.AheadCount > 0 || wt.BehindCount > 0 {
@@ -181,7 +202,7 @@ func (m Model) renderDetails() string {
 				b.WriteString(strings.Join(statusParts, ", "))
 
 				// Add pull hint directly on the same line if behind
-				if wt.BehindCount > 0 && !wt.IsCurrent && strings.Contains(wt.Path, ".workspaces") {
+				if wt.BehindCount > 0 && !wt.IsCurrent && wt.Managed {
 					b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(" (press 'u' to pull)"))
 				}
 			} else {
@@ -205,6 +226,63 @@ func (m Model) renderDetails() string {
 		b.WriteString("\n")
 	}
 
+	// Where the branch is pushed, and where its PRs go when that's another remote (a fork)
+	if wt.Branch != "" {
+		push, base := m.remotes()
+		b.WriteString(detailKeyStyle.Render("Remote: "))
+		b.WriteString(detailValueStyle.Render(push + "/" + wt.Branch))
+		if base != push {
+			b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(fmt.Sprintf(" (PRs against %s)", base)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Port block exported to scripts as JEAN_PORT / JEAN_PORT_RANGE
+	if portRange := m.hookContext(wt.Path, wt.Branch).PortRange(); portRange != "" {
+		b.WriteString(detailKeyStyle.Render("Ports: "))
+		b.WriteString(detailValueStyle.Render(portRange))
+		b.WriteString("\n")
+	}
+
+	if wt.Locked {
+		lock := "  🔒 Locked, only removed when forced"
+		if wt.LockReason != "" {
+			lock = fmt.Sprintf("  🔒 Locked: %s", wt.LockReason)
+		}
+		b.WriteString("\n")
+		b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render(lock))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("  Press 'W' to unlock"))
+		b.WriteString("\n")
+	}
+
+	if wt.Prunable {
+		b.WriteString("\n")
+		b.WriteString(normalItemStyle.Copy().Foreground(errorColor).Render("  ✗ Directory was deleted outside jean"))
+		b.WriteString("\n")
+		b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render("  Press 'C' to prune it"))
+		b.WriteString("\n")
+	}
+
+	if wt.SparseDirs != nil {
+		sparse := "custom"
+		if profiles := m.scriptConfig.SparseProfilesOf(wt.SparseDirs); profiles != nil {
+			sparse = strings.Join(profiles, " + ")
+		}
+		b.WriteString("\n")
+		b.WriteString(detailKeyStyle.Render("Sparse checkout: "))
+		b.WriteString(detailValueStyle.Render(fmt.Sprintf("%s (%s)", sparse, strings.Join(wt.SparseDirs, ", "))))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("  Press 'E' to widen it"))
+		b.WriteString("\n")
+	}
+
+	if !wt.Prunable && !wt.Managed && !wt.IsCurrent && wt.Path != m.repoPath {
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("  Added outside jean, press 'A' to adopt it"))
+		b.WriteString("\n")
+	}
+
 	// Show uncommitted changes status
 	if wt.HasUncommitted {
 		b.WriteString("\n")
@@ -217,6 +295,24 @@ func (m Model) renderDetails() string {
 	}
 
 
+	// Show jean.json script windows with their running/exited state
+	if windows := m.scriptWindows[wt.ClaudeSessionName]; len(windows) > 0 {
+		b.WriteString("\n")
+		b.WriteString(detailKeyStyle.Render("Scripts:"))
+		b.WriteString("\n")
+		for _, window := range windows {
+			name := strings.TrimPrefix(window.Name, "script-")
+			if window.Running {
+				b.WriteString(normalItemStyle.Copy().Foreground(successColor).Render(fmt.Sprintf("  ▶ %s (running)", name)))
+			} else if window.ExitCode == 0 {
+				b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(fmt.Sprintf("  ✓ %s (exited)", name)))
+			} else {
+				b.WriteString(normalItemStyle.Copy().Foreground(errorColor).Render(fmt.Sprintf("  ✗ %s (exited %d)", name, window.ExitCode)))
+			}
+			b.WriteString("\n")
+		}
+	}
+
 	// Show PR status
 	if prs, ok := wt.PRs.([]config.PRInfo); ok && len(prs) > 0 {
 		b.WriteString("\n")
@@ -266,6 +362,10 @@ func (m Model) renderDetails() string {
 	}
 	b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render(fmt.Sprintf("  o open in default editor (%s)", editor)))
 	b.WriteString("\n")
+	if m.scriptConfig.HasScripts() {
+		b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render("  x run a jean.json script"))
+		b.WriteString("\n")
+	}
 	b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render("  Enter to start Claude"))
 
 	return b.String()
@@ -443,6 +543,32 @@ func (m Model) renderModal() string {
 		return m.renderOnboardingModal()
 	case gitInitModal:
 		return m.renderGitInitModal()
+	case scriptPickerModal:
+		return m.renderScriptPickerModal()
+	case conflictModal:
+		return m.renderConflictModal()
+	case stashModal:
+		return m.renderStashModal()
+	case stageModal:
+		return m.renderStageModal()
+	case historyModal:
+		return m.renderHistoryModal()
+	case diffModal:
+		return m.renderDiffModal()
+	case gcModal:
+		return m.renderGCModal()
+	case lockModal:
+		return m.renderLockModal()
+	case refSelectModal:
+		return m.renderRefSelectModal()
+	case worktreeLocationModal:
+		return m.renderWorktreeLocationModal()
+	case remotesModal:
+		return m.renderRemotesModal()
+	case adoptModal:
+		return m.renderAdoptModal()
+	case sparseWidenModal:
+		return m.renderSparseWidenModal()
 	}
 	return ""
 }
@@ -473,7 +599,8 @@ func (m Model) renderCreateModal() string {
 	}
 
 	// Show info about auto-generated workspace location
-	b.WriteString(helpStyle.Render("Workspace location: .workspaces/<random-name>"))
+	root, template := m.worktreeLocation()
+	b.WriteString(helpStyle.Render("Workspace location: " + m.previewWorktreePath(root, template)))
 	b.WriteString("\n\n")
 
 	// Buttons (now only 2 buttons: Create and Cancel)
@@ -551,6 +678,7 @@ func (m Model) renderCreateWithNameModal() string {
 	b.WriteString("\n")
 	b.WriteString(helpStyle.Render(fmt.Sprintf("  Claude will automatically continue previous conversations")))
 	b.WriteString("\n\n")
+	b.WriteString(m.renderSparseProfileChoice())
 
 	// Buttons (Create and Cancel)
 	createBtn := "Create"
@@ -578,6 +706,27 @@ func (m Model) renderCreateWithNameModal() string {
 	)
 }
 
+// renderSparseProfileChoice shows the checkout of the worktree being created when jean.json
+// has sparse-checkout profiles to pick from
+func (m Model) renderSparseProfileChoice() string {
+	if len(m.scriptConfig.SparseProfileNames()) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(inputLabelStyle.Render("Checkout: "))
+	if m.createSparseProfile == "" {
+		b.WriteString(detailValueStyle.Render("full"))
+	} else {
+		dirs, _ := m.scriptConfig.SparseProfile(m.createSparseProfile)
+		b.WriteString(detailValueStyle.Render(fmt.Sprintf("sparse '%s' (%s)", m.createSparseProfile, strings.Join(dirs, ", "))))
+	}
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("(ctrl+p to pick a sparse-checkout profile)"))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
 func (m Model) renderDeleteModal() string {
 	var b strings.Builder
 
@@ -590,15 +739,25 @@ func (m Model) renderDeleteModal() string {
 	b.WriteString("\n\n")
 	b.WriteString(normalItemStyle.Render(fmt.Sprintf("Are you sure you want to delete worktree:")))
 	b.WriteString("\n\n")
-	b.WriteString(detailValueStyle.Render(fmt.Sprintf("  Branch: %s", wt.Branch)))
+	b.WriteString(detailValueStyle.Render(fmt.Sprintf("  Branch: %s", wt.DisplayName())))
 	b.WriteString("\n")
 	b.WriteString(detailValueStyle.Render(fmt.Sprintf("  Path: %s", wt.Path)))
 	b.WriteString("\n\n")
 
-	// Show warning if there are uncommitted changes
-	if m.deleteHasUncommitted {
-		b.WriteString(errorStyle.Render("⚠️  WARNING: This worktree has uncommitted changes!"))
+	// Show warning if there are uncommitted changes or a lock
+	if m.deleteLocked {
+		lock := "🔒 This worktree is locked"
+		if wt.LockReason != "" {
+			lock += ": " + wt.LockReason
+		}
+		b.WriteString(errorStyle.Render(lock))
 		b.WriteString("\n")
+	}
+	if m.deleteNeedsForce() {
+		if m.deleteHasUncommitted {
+			b.WriteString(errorStyle.Render("⚠️  WARNING: This worktree has uncommitted changes!"))
+			b.WriteString("\n")
+		}
 		if m.deleteConfirmForce {
 			b.WriteString(errorStyle.Render("    Confirm force delete below."))
 		} else {
@@ -611,13 +770,13 @@ func (m Model) renderDeleteModal() string {
 	}
 
 	// Buttons
-	if m.deleteHasUncommitted {
+	if m.deleteNeedsForce() {
 		// Show 3 buttons: Yes (disabled), Cancel, Force Delete
 		yesBtn := "Yes"
 		noBtn := "Cancel"
 		forceBtn := "Force Delete"
 
-		// Yes button (disabled if uncommitted changes and not confirmed)
+		// Yes button (disabled if uncommitted changes or locked and not confirmed)
 		if m.deleteConfirmForce {
 			if m.modalFocused == 0 {
 				b.WriteString(selectedDeleteButtonStyle.Render(yesBtn))
@@ -733,6 +892,7 @@ func (m Model) renderBranchSelectModal() string {
 	}
 
 	b.WriteString("\n")
+	b.WriteString(m.renderSparseProfileChoice())
 
 	// Buttons
 	okBtn := "OK"
@@ -840,6 +1000,86 @@ func (m Model) renderCheckoutBranchModal() string {
 	)
 }
 
+// renderRefSelectModal renders the modal to create a worktree at a tag or commit
+func (m Model) renderRefSelectModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Create Worktree at Tag or Commit"))
+	b.WriteString("\n\n")
+
+	b.WriteString(inputLabelStyle.Render("Tag or commit:"))
+	b.WriteString("\n")
+	b.WriteString(m.searchInput.View())
+	b.WriteString("\n\n")
+
+	tags := m.branches
+	if m.searchInput.Value() != "" {
+		tags = m.filteredBranches
+	}
+
+	if len(tags) == 0 {
+		if ref := strings.TrimSpace(m.searchInput.Value()); ref != "" {
+			b.WriteString(normalItemStyle.Render(fmt.Sprintf("No matching tags, the worktree is created at commit '%s'", ref)))
+		} else {
+			b.WriteString(normalItemStyle.Render("No tags found, type a commit"))
+		}
+		b.WriteString("\n\n")
+	} else {
+		maxVisible := 10
+		start := m.branchIndex - maxVisible/2
+		if start < 0 {
+			start = 0
+		}
+		end := start + maxVisible
+		if end > len(tags) {
+			end = len(tags)
+			start = end - maxVisible
+			if start < 0 {
+				start = 0
+			}
+		}
+
+		for i := start; i < end; i++ {
+			if i == m.branchIndex {
+				b.WriteString(selectedItemStyle.Render(fmt.Sprintf("› %s", tags[i])))
+			} else {
+				b.WriteString(normalItemStyle.Render(fmt.Sprintf("  %s", tags[i])))
+			}
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(fmt.Sprintf("Showing %d-%d of %d tags", start+1, end, len(tags))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+
+	createBtn := "Create"
+	cancelBtn := "Cancel"
+
+	if m.modalFocused == 2 {
+		b.WriteString(selectedButtonStyle.Render(createBtn))
+	} else {
+		b.WriteString(buttonStyle.Render(createBtn))
+	}
+
+	if m.modalFocused == 3 {
+		b.WriteString(selectedCancelButtonStyle.Render(cancelBtn))
+	} else {
+		b.WriteString(cancelButtonStyle.Render(cancelBtn))
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("Type a tag or any commit • ↑↓ navigate • Tab to switch • Enter to create • Esc to cancel"))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		modalStyle.Render(b.String()),
+	)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -917,7 +1157,16 @@ func (m Model) renderSessionListModal() string {
 func (m Model) renderRenameModal() string {
 	var b strings.Builder
 
+	// A detached worktree gets a new branch at its HEAD instead
+	wt := m.selectedWorktree()
+	detached := wt != nil && wt.Detached
+
 	title := "Rename Branch"
+	action := "Rename"
+	if detached {
+		title = "Create Branch Here"
+		action = "Create"
+	}
 	b.WriteString(modalTitleStyle.Render(title))
 	b.WriteString("\n\n")
 
@@ -935,7 +1184,11 @@ func (m Model) renderRenameModal() string {
 	sanitizedName := m.sessionManager.SanitizeBranchName(newName)
 
 	if newName != "" {
-		b.WriteString(helpStyle.Render("Will rename to:"))
+		if detached {
+			b.WriteString(helpStyle.Render("Will create:"))
+		} else {
+			b.WriteString(helpStyle.Render("Will rename to:"))
+		}
 		b.WriteString("\n")
 		b.WriteString(helpStyle.Render(fmt.Sprintf("  Branch: %s", sanitizedName)))
 		b.WriteString("\n")
@@ -949,8 +1202,11 @@ func (m Model) renderRenameModal() string {
 	}
 
 	// Show info about what will be renamed
-	if wt := m.selectedWorktree(); wt != nil {
-		if strings.Contains(wt.Path, ".workspaces") {
+	if detached {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("ℹ️  The branch starts at %s and is checked out in this worktree", wt.Ref)))
+		b.WriteString("\n\n")
+	} else if wt != nil {
+		if wt.Managed {
 			b.WriteString(helpStyle.Render("ℹ️  This will rename the git branch only"))
 			b.WriteString("\n")
 			b.WriteString(helpStyle.Render("   Directory path stays the same to preserve active sessions"))
@@ -995,7 +1251,7 @@ func (m Model) renderRenameModal() string {
 
 	buttons := lipgloss.JoinHorizontal(
 		lipgloss.Left,
-		renameStyle.Render("[ Rename ]"),
+		renameStyle.Render(fmt.Sprintf("[ %s ]", action)),
 		"  ",
 		cancelStyle.Render("[ Cancel ]"),
 	)
@@ -1123,9 +1379,18 @@ func (m Model) renderCommitModal() string {
 	var b strings.Builder
 
 	title := "Commit Changes"
+	if m.commitSquashOnto != "" {
+		title = "Squash Commits"
+	}
 	b.WriteString(modalTitleStyle.Render(title))
 	b.WriteString("\n\n")
 
+	// The commits ahead of the base branch are replaced by this one
+	if m.commitSquashOnto != "" {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("The %d commits ahead of %s become a single commit", len(m.historyCommits), m.commitSquashOnto)))
+		b.WriteString("\n\n")
+	}
+
 	// Subject input (one-line conventional commit)
 	b.WriteString(inputLabelStyle.Render("Subject (required, one-line conventional commit):"))
 	b.WriteString("\n")
@@ -1136,6 +1401,51 @@ func (m Model) renderCommitModal() string {
 	b.WriteString(subjectStyle.Render(m.commitSubjectInput.View()))
 	b.WriteString("\n\n")
 
+	// Only the selection of the staging modal is committed
+	if m.commitStagedOnly {
+		b.WriteString(helpStyle.Render("Committing the staged selection only, other changes stay in the worktree"))
+		b.WriteString("\n\n")
+	}
+
+	// Body input (optional, prefilled by jean e.g. with how merge conflicts were resolved)
+	b.WriteString(inputLabelStyle.Render("Body (optional):"))
+	b.WriteString("\n")
+	bodyStyle := normalItemStyle
+	if m.modalFocused == 1 {
+		bodyStyle = selectedItemStyle
+	}
+	b.WriteString(bodyStyle.Render(m.commitBodyInput.View()))
+	b.WriteString("\n\n")
+
+	// Trailers input, one "Key: value" per line
+	b.WriteString(inputLabelStyle.Render("Trailers (optional, e.g. Co-authored-by: Name <email>, Refs #123):"))
+	b.WriteString("\n")
+	trailersStyle := normalItemStyle
+	if m.modalFocused == 2 {
+		trailersStyle = selectedItemStyle
+	}
+	b.WriteString(trailersStyle.Render(m.commitTrailersInput.View()))
+	b.WriteString("\n")
+
+	// Signing preferences of the repository (settings)
+	var signing []string
+	if m.configManager != nil {
+		if m.configManager.GetCommitSignoff(m.repoPath) {
+			signing = append(signing, "Signed-off-by is added")
+		}
+		switch m.configManager.GetCommitGPGSign(m.repoPath) {
+		case "always":
+			signing = append(signing, "GPG-signed")
+		case "never":
+			signing = append(signing, "not GPG-signed")
+		}
+	}
+	if len(signing) > 0 {
+		b.WriteString(helpStyle.Render("Commit: " + strings.Join(signing, ", ") + " (change in settings)"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
 	// Status message (error or success from AI generation) or spinner
 	if m.generatingCommit {
 		// Show spinner animation while generating
@@ -1152,12 +1462,12 @@ func (m Model) renderCommitModal() string {
 		b.WriteString("\n\n")
 	}
 
-	// AI availability indicator
+	// AI availability indicator (generation works on uncommitted changes, not when squashing)
 	hasAIKey := m.configManager != nil && m.configManager.GetOpenRouterAPIKey() != ""
-	if hasAIKey {
+	if m.commitSquashOnto == "" && hasAIKey {
 		b.WriteString(helpStyle.Render("💡 Press 'g' to generate commit message with AI"))
 		b.WriteString("\n\n")
-	} else {
+	} else if m.commitSquashOnto == "" {
 		b.WriteString(helpStyle.Render("💡 Tip: Enable AI in settings (s → a) to auto-generate commit messages"))
 		b.WriteString("\n\n")
 	}
@@ -1166,9 +1476,9 @@ func (m Model) renderCommitModal() string {
 	commitStyle := normalItemStyle
 	cancelStyle := normalItemStyle
 
-	if m.modalFocused == 1 {
+	if m.modalFocused == 3 {
 		commitStyle = selectedItemStyle
-	} else if m.modalFocused == 2 {
+	} else if m.modalFocused == 4 {
 		cancelStyle = selectedItemStyle
 	}
 
@@ -1181,7 +1491,7 @@ func (m Model) renderCommitModal() string {
 	b.WriteString(buttons)
 
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("Tab: next • Enter: confirm • Esc: cancel"))
+	b.WriteString(helpStyle.Render("Tab: next field • Enter: new line in body/trailers, confirm on buttons • Esc: cancel"))
 
 	// Center the modal
 	modalContent := b.String()
@@ -1431,6 +1741,75 @@ func (m Model) renderPRListModal() string {
 	)
 }
 
+func (m Model) renderScriptPickerModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Run Script"))
+	b.WriteString("\n\n")
+
+	wt := m.selectedWorktree()
+	names := m.scriptConfig.GetScriptNames()
+	if wt == nil || len(names) == 0 {
+		b.WriteString(normalItemStyle.Render("Loading scripts from jean.json..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Esc to close"))
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalStyle.Render(b.String()),
+		)
+	}
+
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s", wt.DisplayName())))
+	b.WriteString("\n\n")
+
+	for i, name := range names {
+		// Running/exited state of the script's tmux window
+		state := ""
+		if spec, _ := m.scriptConfig.LookupScript(name); !spec.IsInteractive() {
+			state = " (background)"
+		} else if window := m.scriptWindow(*wt, name); window != nil {
+			if window.Running {
+				state = " ▶ running"
+			} else {
+				state = fmt.Sprintf(" ■ exited (%d)", window.ExitCode)
+			}
+		}
+
+		if i == m.scriptIndex {
+			b.WriteString(selectedItemStyle.Render(fmt.Sprintf("› %s%s", name, state)))
+		} else {
+			b.WriteString(normalItemStyle.Render(fmt.Sprintf("  %s%s", name, state)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Show the description and command of the selected script
+	if m.scriptIndex >= 0 && m.scriptIndex < len(names) {
+		spec, _ := m.scriptConfig.LookupScript(names[m.scriptIndex])
+		b.WriteString("\n")
+		if spec.Description != "" {
+			b.WriteString(normalItemStyle.Render(spec.Description))
+			b.WriteString("\n")
+		}
+		command := fmt.Sprintf("$ %s", spec.Command)
+		if spec.Cwd != "" {
+			command = fmt.Sprintf("%s $ %s", spec.Cwd, spec.Command)
+		}
+		b.WriteString(helpStyle.Render(command))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑↓ navigate • Enter run/restart • a attach • d stop • Esc close"))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		modalStyle.Render(b.String()),
+	)
+}
+
 func (m Model) renderEditorSelectModal() string {
 	var b strings.Builder
 
@@ -1598,6 +1977,87 @@ func (m Model) renderSettingsModal() string {
 				return "Ready for Review"
 			},
 		},
+		{
+			name:        "Update Mode",
+			key:         "u",
+			description: "How 'u' updates a worktree from the base branch (merge, or rebase + force-push with lease)",
+			getCurrent: func() string {
+				if m.configManager != nil && m.configManager.GetUpdateMode(m.repoPath) == "rebase" {
+					return "Rebase"
+				}
+				return "Merge"
+			},
+		},
+		{
+			name:        "Auto-stash",
+			key:         "s",
+			description: "Stash uncommitted changes before 'u' updates from the base branch and re-apply them afterwards",
+			getCurrent: func() string {
+				if m.configManager != nil && m.configManager.GetAutoStash(m.repoPath) {
+					return "Enabled"
+				}
+				return "Disabled"
+			},
+		},
+		{
+			name:        "Sign-off",
+			key:         "o",
+			description: "Add a Signed-off-by trailer to commits made with jean (--signoff)",
+			getCurrent: func() string {
+				if m.configManager != nil && m.configManager.GetCommitSignoff(m.repoPath) {
+					return "Enabled"
+				}
+				return "Disabled"
+			},
+		},
+		{
+			name:        "GPG Signing",
+			key:         "g",
+			description: "Sign commits made with jean: git default (commit.gpgsign), always (-S) or never",
+			getCurrent: func() string {
+				if m.configManager != nil {
+					switch m.configManager.GetCommitGPGSign(m.repoPath) {
+					case "always":
+						return "Always"
+					case "never":
+						return "Never"
+					}
+				}
+				return "Git Default"
+			},
+		},
+		{
+			name:        "Worktree Location",
+			key:         "w",
+			description: "Directory and path template of new worktrees, e.g. ~/worktrees/{repo} and {repo}-{branch}",
+			getCurrent: func() string {
+				root, template := m.worktreeLocation()
+				return m.previewWorktreePath(root, template)
+			},
+		},
+		{
+			name:        "Submodules & LFS",
+			key:         "m",
+			description: "Initialize submodules (recursively) and pull Git LFS files in new worktrees",
+			getCurrent: func() string {
+				if m.configManager != nil && m.configManager.GetInitSubmodules(m.repoPath) {
+					return "Enabled"
+				}
+				return "Disabled"
+			},
+		},
+		{
+			name:        "Remotes",
+			key:         "r",
+			description: "Remote branches are pushed to and remote PRs are opened against, e.g. fork and upstream",
+			getCurrent: func() string {
+				push, base := m.remotes()
+				if push == base {
+					return push
+				}
+				return fmt.Sprintf("push to %s, PRs against %s", push, base)
+			},
+		},
 	}
 
 	// Render settings list
@@ -2023,10 +2483,16 @@ func (m Model) renderHelperModal() string {
 				{"↓", "Move cursor down"},
 				{"n", "Create new worktree (with AI)"},
 				{"a", "Create new worktree (from existing branch)"},
+				{"T", "Create worktree at a tag or commit (detached HEAD)"},
 				{"enter", "Open CLI (Claude for now)"},
 				{"t", "Open terminal"},
 				{"o", "Open default editor"},
+				{"x", "Run jean.json script in tmux window"},
 				{"d", "Delete selected worktree"},
+				{"C", "Clean up stale worktrees (merged/closed PRs, merged or untouched)"},
+				{"W", "Lock/unlock worktree (locked ones are only removed when forced)"},
+				{"A", "Adopt a worktree added outside jean (git worktree add)"},
+				{"E", "Widen a sparse checkout (another profile or a full checkout)"},
 			},
 		},
 		{
@@ -2035,12 +2501,15 @@ func (m Model) renderHelperModal() string {
 				key         string
 				description string
 			}{
-				{"c", "Commit all uncommitted changes (with AI)"},
+				{"c", "Commit: pick files/hunks to stage, then message (with AI)"},
 				{"p", "Push to remote (with AI)"},
-				{"u", "Update from base branch (pull/merge)"},
+				{"u", "Update from base branch (merge or rebase, see settings)"},
+				{"z", "Stashes (stash, pop, apply, drop)"},
+				{"l", "Commit history (diff, amend, reword, revert, squash)"},
+				{"D", "Diff viewer (uncommitted changes or branch vs base)"},
 				{"r", "Refresh status (fetch from remote, no merging)"},
 				{"b", "Change base branch for new worktrees"},
-				{"B", "Rename current branch"},
+				{"B", "Rename current branch (create one in a detached worktree)"},
 				{"K", "Checkout/switch branch in main repo"},
 			},
 		},
@@ -2323,6 +2792,848 @@ func (m Model) renderPostMergeCleanupModal() string {
 	)
 }
 
+func (m Model) renderConflictModal() string {
+	var b strings.Builder
+
+	title := "⚠ Merge Conflict"
+	if m.conflictOperation == "rebase" {
+		title = "⚠ Rebase Conflict"
+	}
+	b.WriteString(modalTitleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	if m.conflictBranch != "" {
+		b.WriteString(detailKeyStyle.Render("Branch:  "))
+		b.WriteString(detailValueStyle.Render(m.conflictBranch))
+		b.WriteString("\n")
+	}
+	if m.conflictOperation == "rebase" {
+		b.WriteString(detailKeyStyle.Render("Onto:    "))
+	} else {
+		b.WriteString(detailKeyStyle.Render("Merging: "))
+	}
+	b.WriteString(detailValueStyle.Render(m.conflictOnto))
+	b.WriteString("\n")
+	b.WriteString(detailKeyStyle.Render("Path:    "))
+	b.WriteString(detailValueStyle.Render(m.conflictWorktree))
+	b.WriteString("\n\n")
+
+	// Which side is which differs between merge and rebase
+	if m.conflictOperation == "rebase" {
+		b.WriteString(helpStyle.Render("ours = " + m.conflictOnto + " • theirs = your commit being replayed"))
+	} else {
+		b.WriteString(helpStyle.Render("ours = current branch • theirs = " + m.conflictOnto))
+	}
+	b.WriteString("\n\n")
+
+	// Conflicted files
+	if len(m.conflictFiles) > 0 {
+		descStyle := normalItemStyle.Copy().Foreground(mutedColor)
+		for i, file := range m.conflictFiles {
+			line := fmt.Sprintf("%s %s", file.Status, file.Path)
+			if i == m.conflictCursor {
+				b.WriteString(selectedItemStyle.Render("▶ " + line))
+			} else {
+				b.WriteString(normalItemStyle.Render("  " + line))
+			}
+			b.WriteString(descStyle.Render("  " + file.Description()))
+			b.WriteString("\n")
+		}
+	} else {
+		b.WriteString(normalItemStyle.Copy().Foreground(successColor).Render("✓ All conflicts are resolved."))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Resolved so far
+	resolved := 0
+	for _, resolution := range m.conflictResolutions {
+		if resolution != "" {
+			resolved++
+		}
+	}
+	if resolved > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%d file(s) resolved in jean", resolved)))
+		b.WriteString("\n\n")
+	}
+
+	// What continue does
+	continueDescription := "c: continue - create the merge commit (resolutions are summarized in its message)"
+	if m.conflictOperation == "rebase" {
+		continueDescription = "c: continue - stage the resolved files and continue the rebase"
+		if m.conflictHadUpstream {
+			continueDescription += ", then force-push with lease"
+		}
+	}
+	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(continueDescription))
+	b.WriteString("\n\n")
+
+	// Uncommitted changes put aside by auto-stash
+	if m.conflictStash != "" {
+		if m.conflictOperation == "rebase" {
+			b.WriteString(helpStyle.Render("Your uncommitted changes are stashed and re-applied when the rebase finishes or is aborted."))
+		} else {
+			b.WriteString(helpStyle.Render("Your uncommitted changes are stashed: pop them with 'z' once the merge is committed (abort re-applies them)."))
+		}
+		b.WriteString("\n\n")
+	}
+
+	// Help text
+	b.WriteString(helpStyle.Render("↑/↓ select • o ours • t theirs • e editor • r mark resolved • c continue • a abort • esc later"))
+
+	// Center the modal
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderStashModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Stashes"))
+	b.WriteString("\n\n")
+
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return ""
+	}
+
+	scope := fmt.Sprintf("Worktree: %s • stashes made on this branch", wt.DisplayName())
+	if m.stashShowAll {
+		scope = fmt.Sprintf("Worktree: %s • all stashes of the repository", wt.DisplayName())
+	}
+	b.WriteString(helpStyle.Render(scope))
+	b.WriteString("\n\n")
+
+	// New stash message
+	if m.stashInputActive {
+		b.WriteString(inputLabelStyle.Render("Stash uncommitted changes (including untracked files):"))
+		b.WriteString("\n")
+		b.WriteString(m.stashInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Enter stash • Esc cancel"))
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalStyle.Render(b.String()),
+		)
+	}
+
+	stashes := m.visibleStashes()
+	if len(stashes) == 0 {
+		b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("No stashes"))
+		b.WriteString("\n")
+	}
+	descStyle := normalItemStyle.Copy().Foreground(mutedColor)
+	for i, stash := range stashes {
+		line := fmt.Sprintf("%s %s", stash.Ref, stash.Message)
+		if i == m.stashIndex {
+			b.WriteString(selectedItemStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + line))
+		}
+
+		details := stash.Date
+		if m.stashShowAll && stash.Branch != "" {
+			details = fmt.Sprintf("%s • %s", stash.Branch, stash.Date)
+		}
+		b.WriteString(descStyle.Render("  " + details))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.stashDropConfirm && m.stashIndex < len(stashes) {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Press d again to drop %s, it cannot be recovered", stashes[m.stashIndex].Ref)))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓ select • n stash changes • p/enter pop • a apply • d drop • tab this branch/all • esc close"))
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderLockModal() string {
+	var b strings.Builder
+
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return ""
+	}
+
+	b.WriteString(modalTitleStyle.Render("Lock Worktree"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s", wt.DisplayName())))
+	b.WriteString("\n\n")
+	b.WriteString(inputLabelStyle.Render("Locked worktrees are only deleted or cleaned up when forced:"))
+	b.WriteString("\n")
+	b.WriteString(m.lockReasonInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("Enter lock • Esc cancel"))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		modalStyle.Render(b.String()),
+	)
+}
+
+func (m Model) renderAdoptModal() string {
+	var b strings.Builder
+
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return ""
+	}
+
+	b.WriteString(modalTitleStyle.Render("Adopt Worktree"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s", wt.DisplayName())))
+	b.WriteString("\n\n")
+	b.WriteString(normalItemStyle.Render("This worktree was added outside jean. Adopting it enables pull, merge and rename,"))
+	b.WriteString("\n")
+	b.WriteString(normalItemStyle.Render("gives it a port block and creates its tmux session."))
+	b.WriteString("\n\n")
+
+	options := []struct {
+		name        string
+		description string
+	}{
+		{"Adopt in place", "Keep it at " + wt.Path},
+		{"Adopt and move", "Move it to " + m.adoptMovePath},
+	}
+
+	for i, option := range options {
+		if i == m.adoptIndex {
+			b.WriteString(selectedItemStyle.Render("▶ " + option.name))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + option.name))
+		}
+		b.WriteString("\n")
+		descStyle := normalItemStyle.Copy().Foreground(mutedColor)
+		b.WriteString(descStyle.Render("  " + option.description))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓ select • enter adopt • esc cancel"))
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderSparseWidenModal() string {
+	var b strings.Builder
+
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return ""
+	}
+
+	b.WriteString(modalTitleStyle.Render("Widen Sparse Checkout"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s", wt.DisplayName())))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("Checked out: " + strings.Join(wt.SparseDirs, ", ")))
+	b.WriteString("\n\n")
+
+	for i, profile := range m.sparseWidenOptions(wt) {
+		name := "Full checkout"
+		description := "Check out the whole repository"
+		if profile != "" {
+			dirs, _ := m.scriptConfig.SparseProfile(profile)
+			name = "Add '" + profile + "'"
+			description = strings.Join(dirs, ", ")
+		}
+
+		if i == m.sparseWidenIndex {
+			b.WriteString(selectedItemStyle.Render("▶ " + name))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + name))
+		}
+		b.WriteString("\n")
+		descStyle := normalItemStyle.Copy().Foreground(mutedColor)
+		b.WriteString(descStyle.Render("  " + description))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓ select • enter widen • esc cancel"))
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderWorktreeLocationModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Worktree Location"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("Where new worktrees of this repository are created. Existing worktrees stay where they are."))
+	b.WriteString("\n\n")
+
+	b.WriteString(inputLabelStyle.Render("Root directory (relative to the repository, ~ for home):"))
+	b.WriteString("\n")
+	b.WriteString(m.worktreeRootInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(inputLabelStyle.Render("Path template ({repo} and {branch} placeholders):"))
+	b.WriteString("\n")
+	b.WriteString(m.worktreeTemplateInput.View())
+	b.WriteString("\n\n")
+
+	root := strings.TrimSpace(m.worktreeRootInput.Value())
+	template := strings.TrimSpace(m.worktreeTemplateInput.Value())
+	if err := git.ValidateWorktreeTemplate(template); err != nil {
+		b.WriteString(errorStyle.Render(err.Error()))
+	} else {
+		b.WriteString(helpStyle.Render("New worktrees: " + m.previewWorktreePath(root, template)))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("Tab switch field • Enter save • Esc cancel • leave empty for the default"))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		modalStyle.Render(b.String()),
+	)
+}
+
+func (m Model) renderRemotesModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Remotes"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("In a fork-based workflow branches are pushed to your fork and PRs opened against upstream."))
+	b.WriteString("\n\n")
+
+	b.WriteString(inputLabelStyle.Render("Push remote (branches are pushed to and pulled from it):"))
+	b.WriteString("\n")
+	b.WriteString(m.pushRemoteInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(inputLabelStyle.Render("Base remote (the base branch is fetched from it, PRs target it):"))
+	b.WriteString("\n")
+	b.WriteString(m.baseRemoteInput.View())
+	b.WriteString("\n\n")
+
+	push := strings.TrimSpace(m.pushRemoteInput.Value())
+	base := strings.TrimSpace(m.baseRemoteInput.Value())
+	if err := m.checkRemotes(push, base); err != nil {
+		b.WriteString(errorStyle.Render(err.Error()))
+	} else if len(m.remoteNames) > 0 {
+		b.WriteString(helpStyle.Render("Remotes: " + strings.Join(m.remoteNames, ", ")))
+	} else {
+		b.WriteString(helpStyle.Render("This repository has no remotes yet"))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("Tab switch field • Enter save • Esc cancel • leave empty for " + git.DefaultRemote))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		modalStyle.Render(b.String()),
+	)
+}
+
+func (m Model) renderGCModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Clean Up Stale Worktrees"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Merged or closed PRs, branches merged into %s, or untouched for %d days", m.baseBranch, git.DefaultStaleDays)))
+	b.WriteString("\n\n")
+
+	descStyle := normalItemStyle.Copy().Foreground(mutedColor)
+	switch {
+	case m.gcWorktrees == nil:
+		b.WriteString(descStyle.Render("Looking for stale worktrees..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("esc close"))
+
+	case len(m.gcWorktrees) == 0 && !m.hasGCOrphans():
+		b.WriteString(descStyle.Render("No stale worktrees, nothing to clean up"))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("esc close"))
+
+	default:
+		count := 0
+		for i, wt := range m.gcWorktrees {
+			check := "[ ]"
+			if m.gcSelected[i] {
+				check = "[x]"
+				count++
+			}
+			line := fmt.Sprintf("%s %s", check, wt.DisplayName())
+			if i == m.gcIndex {
+				b.WriteString(selectedItemStyle.Render("▶ " + line))
+			} else {
+				b.WriteString(normalItemStyle.Render("  " + line))
+			}
+
+			details := strings.Join(wt.Reasons, ", ")
+			if wt.HasUncommitted {
+				details += " • uncommitted changes will be lost"
+			}
+			if wt.Prunable {
+				details += " • prune, the branch is kept"
+			}
+			if wt.Locked {
+				details += " • 🔒 locked"
+				if wt.LockReason != "" {
+					details += ": " + wt.LockReason
+				}
+			}
+			b.WriteString(descStyle.Render("  " + details))
+			b.WriteString("\n")
+		}
+
+		// Config entries and sessions left behind by worktrees removed outside jean
+		if m.hasGCOrphans() {
+			check := "[ ]"
+			if m.gcOrphansSelected {
+				check = "[x]"
+			}
+			line := fmt.Sprintf("%s orphaned: %d config entries, %d tmux sessions", check, len(m.gcOrphanBranches), len(m.gcOrphanSessions))
+			if m.gcIndex == len(m.gcWorktrees) {
+				b.WriteString(selectedItemStyle.Render("▶ " + line))
+			} else {
+				b.WriteString(normalItemStyle.Render("  " + line))
+			}
+			b.WriteString(descStyle.Render("  " + strings.Join(append(append([]string{}, m.gcOrphanBranches...), m.gcOrphanSessions...), ", ")))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+
+		if m.gcRemoving {
+			b.WriteString(descStyle.Render(fmt.Sprintf("Removing %d worktree(s)...", count)))
+		} else if m.gcConfirmForce {
+			b.WriteString(errorStyle.Render(fmt.Sprintf("⚠️  %d of the selected worktree(s) are locked. Force-remove them?", m.gcLockedSelected())))
+			b.WriteString("\n\n")
+			b.WriteString(helpStyle.Render("y force-remove • n back to the list"))
+		} else {
+			b.WriteString(helpStyle.Render(fmt.Sprintf("Removes %d worktree(s) with their branches, tmux sessions and saved PRs", count)))
+			b.WriteString("\n\n")
+			b.WriteString(helpStyle.Render("↑/↓ select • space toggle • a all/none • enter remove selected • esc cancel"))
+		}
+	}
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderDiffModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Diff"))
+	b.WriteString("\n")
+
+	branch := ""
+	if wt := m.selectedWorktree(); wt != nil {
+		branch = wt.DisplayName()
+	}
+	scope := "uncommitted changes"
+	if m.diffAgainstBase {
+		scope = fmt.Sprintf("changes since forking off %s", m.baseBranch)
+	}
+
+	if m.diffFiles == nil {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s • %s", branch, scope)))
+		b.WriteString("\n\n")
+		b.WriteString(normalItemStyle.Render("Loading changes..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Esc to close"))
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalStyle.Render(b.String()),
+		)
+	}
+
+	// Lines added and removed per file
+	added := make([]int, len(m.diffFiles))
+	removed := make([]int, len(m.diffFiles))
+	totalAdded, totalRemoved := 0, 0
+	for i, file := range m.diffFiles {
+		for _, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				switch {
+				case strings.HasPrefix(line, "+"):
+					added[i]++
+				case strings.HasPrefix(line, "-"):
+					removed[i]++
+				}
+			}
+		}
+		totalAdded += added[i]
+		totalRemoved += removed[i]
+	}
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s • %s • %d file(s) +%d -%d", branch, scope, len(m.diffFiles), totalAdded, totalRemoved)))
+	b.WriteString("\n\n")
+
+	page := m.diffPageSize()
+	leftWidth := min(40, m.width/3)
+	rightWidth := max(m.width-leftWidth-14, 20)
+
+	// File list on the left, the cursor kept in view
+	var left []string
+	start := 0
+	if m.diffFileIndex >= page {
+		start = m.diffFileIndex - page + 1
+	}
+	end := min(start+page, len(m.diffFiles))
+	if len(m.diffFiles) == 0 {
+		left = append(left, helpStyle.Render("No changes"))
+	}
+	for i := start; i < end; i++ {
+		file := m.diffFiles[i]
+		counts := fmt.Sprintf(" +%d -%d", added[i], removed[i])
+		name := file.Path
+		if room := leftWidth - 6 - len(counts); len(name) > room && room > 3 {
+			// Keep the end of long paths, it holds the file name
+			name = "…" + name[len(name)-room+1:]
+		}
+		line := fmt.Sprintf("%s %s", file.Status, name)
+		if i == m.diffFileIndex {
+			left = append(left, selectedItemStyle.Copy().PaddingLeft(0).PaddingRight(0).Render("▶ "+line)+helpStyle.Render(counts))
+		} else {
+			left = append(left, "  "+line+helpStyle.Render(counts))
+		}
+	}
+
+	// Hunks of the selected file on the right
+	var right []string
+	if m.diffFileIndex < len(m.diffFiles) {
+		file := m.diffFiles[m.diffFileIndex]
+		lines := m.diffLines()
+		lang := syntaxForFile(file.Path)
+		switch {
+		case file.Binary:
+			right = append(right, helpStyle.Render("Binary file"))
+		case len(lines) == 0:
+			right = append(right, helpStyle.Render("No textual changes (empty, large or mode-only change)"))
+		}
+		last := min(m.diffScroll+page, len(lines))
+		for _, line := range lines[min(m.diffScroll, last):last] {
+			right = append(right, highlightDiffLine(line, lang))
+		}
+	}
+
+	paneStyle := func(focused bool, width int) lipgloss.Style {
+		style := panelStyle
+		if focused {
+			style = activePanelStyle
+		}
+		return style.Copy().Width(width + 4).Height(page + 2)
+	}
+	truncate := lipgloss.NewStyle().MaxWidth(leftWidth)
+	for i := range left {
+		left[i] = truncate.Render(left[i])
+	}
+	truncate = lipgloss.NewStyle().MaxWidth(rightWidth)
+	for i := range right {
+		right[i] = truncate.Render(right[i])
+	}
+	b.WriteString(lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		paneStyle(!m.diffFocusHunks, leftWidth).Render(strings.Join(left, "\n")),
+		paneStyle(m.diffFocusHunks, rightWidth).Render(strings.Join(right, "\n")),
+	))
+	b.WriteString("\n")
+
+	toggle := "b branch vs " + m.baseBranch
+	if m.diffAgainstBase {
+		toggle = "b uncommitted changes"
+	}
+	if m.baseBranch == "" {
+		toggle = "b branch vs base"
+	}
+	b.WriteString(helpStyle.Render(fmt.Sprintf("tab switch pane • ↑/↓ select/scroll • pgup/pgdown page • n/N next/prev hunk • %s • r reload • esc close", toggle)))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		b.String(),
+	)
+}
+
+func (m Model) renderHistoryModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Commit History"))
+	b.WriteString("\n\n")
+
+	if m.historyCommits == nil {
+		b.WriteString(normalItemStyle.Render("Loading commits..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Esc to close"))
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalStyle.Render(b.String()),
+		)
+	}
+
+	// Diff of the selected commit, scrolled by page
+	if m.historyDiff != nil {
+		page := max(m.height-12, 5)
+		end := min(m.historyDiffScroll+page, len(m.historyDiff))
+
+		// The syntax follows the file of the patch, which may start above the visible lines
+		var lang *syntax
+		inPatch := false
+		for _, line := range m.historyDiff[:m.historyDiffScroll] {
+			if strings.HasPrefix(line, "diff --git ") {
+				lang, inPatch = syntaxForFile(line), true
+			}
+		}
+		for _, line := range m.historyDiff[m.historyDiffScroll:end] {
+			switch {
+			case strings.HasPrefix(line, "diff --git "):
+				lang, inPatch = syntaxForFile(line), true
+				b.WriteString(inputLabelStyle.Render(line))
+			case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "index "):
+				b.WriteString(helpStyle.Render(line))
+			case inPatch:
+				b.WriteString(highlightDiffLine(line, lang))
+			default:
+				// Commit header, message and diffstat
+				b.WriteString(normalItemStyle.Render(line))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(fmt.Sprintf("Lines %d-%d of %d • ↑/↓ scroll • pgup/pgdown page • g/G top/bottom • esc back", m.historyDiffScroll+1, end, len(m.historyDiff))))
+
+		content := modalStyle.Width(m.width - 4).Render(b.String())
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			content,
+		)
+	}
+
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s • %d commit(s) ahead of %s", m.historyBranch, len(m.historyCommits), m.baseBranch)))
+	b.WriteString("\n\n")
+
+	if len(m.historyCommits) == 0 {
+		b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("No commits ahead of the base branch"))
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor in view for long lists
+	const maxRows = 15
+	start := 0
+	if m.historyIndex >= maxRows {
+		start = m.historyIndex - maxRows + 1
+	}
+	end := min(start+maxRows, len(m.historyCommits))
+
+	descStyle := normalItemStyle.Copy().Foreground(mutedColor)
+	for i := start; i < end; i++ {
+		commit := m.historyCommits[i]
+		line := fmt.Sprintf("%s %s", commit.ShortHash, commit.Subject)
+		if i == m.historyIndex {
+			b.WriteString(selectedItemStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + line))
+		}
+
+		details := fmt.Sprintf("%s • %s", commit.Author, commit.Date)
+		if commit.FilesChanged > 0 {
+			details += fmt.Sprintf(" • %d file(s) +%d -%d", commit.FilesChanged, commit.Insertions, commit.Deletions)
+		}
+		b.WriteString(descStyle.Render("  " + details))
+		b.WriteString("\n")
+	}
+	if end < len(m.historyCommits) {
+		b.WriteString(descStyle.Render(fmt.Sprintf("  ... %d more", len(m.historyCommits)-end)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// New subject of the commit being reworded
+	if m.historyRewording {
+		b.WriteString(inputLabelStyle.Render("New subject (the body is kept):"))
+		b.WriteString("\n")
+		b.WriteString(m.historyRewordInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Enter reword • Esc cancel"))
+
+		content := modalStyle.Width(m.width - 4).Render(b.String())
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			content,
+		)
+	}
+
+	if m.historyRevertConfirm && m.historyIndex < len(m.historyCommits) {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Press v again to create a commit reverting %s", m.historyCommits[m.historyIndex].ShortHash)))
+		b.WriteString("\n\n")
+	}
+	if m.historyHasUpstream {
+		b.WriteString(helpStyle.Render("The branch was pushed: amend, reword and squash need a force-push (F) afterwards"))
+		b.WriteString("\n\n")
+	}
+
+	help := "↑/↓ select • enter/d diff • m amend last • r reword • v revert • s squash all"
+	if m.historyHasUpstream {
+		help += " • F force-push"
+	}
+	b.WriteString(helpStyle.Render(help + " • esc close"))
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderStageModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Stage Changes"))
+	b.WriteString("\n\n")
+
+	if m.stageFiles == nil {
+		b.WriteString(normalItemStyle.Render("Loading changes..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Esc to close"))
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			modalStyle.Render(b.String()),
+		)
+	}
+
+	selectedFiles := 0
+	for i := range m.stageFiles {
+		if m.stageFileState(i) != "none" {
+			selectedFiles++
+		}
+	}
+	branch := ""
+	if wt := m.selectedWorktree(); wt != nil {
+		branch = wt.DisplayName()
+	}
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Worktree: %s • %d of %d files selected for the commit", branch, selectedFiles, len(m.stageFiles))))
+	b.WriteString("\n\n")
+
+	// Keep the cursor in view for long lists
+	rows := m.stageRows()
+	const maxRows = 15
+	start := 0
+	if m.stageCursor >= maxRows {
+		start = m.stageCursor - maxRows + 1
+	}
+	end := min(start+maxRows, len(rows))
+
+	descStyle := normalItemStyle.Copy().Foreground(mutedColor)
+	for i := start; i < end; i++ {
+		row := rows[i]
+		file := m.stageFiles[row.file]
+
+		var line, desc string
+		if row.hunk >= 0 {
+			check := "[ ]"
+			if m.stageSelected[row.file][row.hunk] {
+				check = "[x]"
+			}
+			line = fmt.Sprintf("    %s %s", check, file.Hunks[row.hunk].Header)
+		} else {
+			check := "[x]"
+			switch m.stageFileState(row.file) {
+			case "none":
+				check = "[ ]"
+			case "some":
+				check = "[~]"
+			}
+			line = fmt.Sprintf("%s %s %s", check, file.Status, file.Path)
+
+			switch {
+			case file.Status == "?":
+				desc = "untracked"
+			case file.Binary:
+				desc = "binary"
+			case file.HunkSelectable() && !m.stageExpanded[row.file]:
+				desc = fmt.Sprintf("%d hunk(s), → to pick", len(file.Hunks))
+			}
+		}
+
+		if i == m.stageCursor {
+			b.WriteString(selectedItemStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + line))
+		}
+		if desc != "" {
+			b.WriteString(descStyle.Render("  " + desc))
+		}
+		b.WriteString("\n")
+	}
+	if end < len(rows) {
+		b.WriteString(descStyle.Render(fmt.Sprintf("  ... %d more", len(rows)-end)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Preview of the hunk under the cursor (the first hunk on file rows)
+	if m.stageCursor < len(rows) {
+		row := rows[m.stageCursor]
+		file := m.stageFiles[row.file]
+		hunk := max(row.hunk, 0)
+		if hunk < len(file.Hunks) {
+			addedStyle := lipgloss.NewStyle().Foreground(successColor)
+			removedStyle := lipgloss.NewStyle().Foreground(errorColor)
+			b.WriteString(helpStyle.Render(file.Hunks[hunk].Header))
+			b.WriteString("\n")
+			const maxPreviewLines = 10
+			for i, line := range file.Hunks[hunk].Lines {
+				if i == maxPreviewLines {
+					b.WriteString(helpStyle.Render(fmt.Sprintf("... %d more lines", len(file.Hunks[hunk].Lines)-maxPreviewLines)))
+					b.WriteString("\n")
+					break
+				}
+				switch {
+				case strings.HasPrefix(line, "+"):
+					b.WriteString(addedStyle.Render(line))
+				case strings.HasPrefix(line, "-"):
+					b.WriteString(removedStyle.Render(line))
+				default:
+					b.WriteString(helpStyle.Render(line))
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓ select • space toggle • →/← show/hide hunks • a all/none • enter commit selection • esc cancel"))
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
 func (m Model) renderOnboardingModal() string {
 	var b strings.Builder
 